@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// columnsProgram : the entities can only be walked using reflection on runtime, so a temporary
+// program which imports the package of the entities will be built and run
+var columnsProgram = template.Must(template.New("program").Parse(`package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Oskang09/sqlike/codegen"
+	pkg_ {{ printf "%q" .ImportPath }}
+)
+
+func main() {
+	entities := []interface{}{
+		{{- range .Types }}
+		pkg_.{{ . }}{},
+		{{- end }}
+	}
+	if err := codegen.GenerateColumns(
+		os.Stdout,
+		entities,
+		codegen.WithPackage({{ printf "%q" .Package }}),
+		codegen.WithImportPath({{ printf "%q" .OutputPath }}),
+	); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+`))
+
+func runColumns(args []string) error {
+	fs := flag.NewFlagSet("columns", flag.ExitOnError)
+	var (
+		pkg   = fs.String("pkg", ".", "package pattern of the entities")
+		types = fs.String("types", "", "comma separated entity struct names (required)")
+		out   = fs.String("out", "", "output file, default is stdout")
+	)
+	fs.Parse(args)
+
+	if *types == "" {
+		fs.Usage()
+		return fmt.Errorf("-types is required")
+	}
+
+	importPath, name, err := goList(*pkg)
+	if err != nil {
+		return err
+	}
+
+	// the generated file will be placed in the same package as the entities by default
+	outputPath, outputName := importPath, name
+	if *out != "" {
+		dir := filepath.Dir(*out)
+		if !filepath.IsAbs(dir) {
+			dir = "./" + dir
+		}
+		outputPath, outputName, err = goList(dir)
+		if err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0)
+	for _, t := range strings.Split(*types, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			names = append(names, t)
+		}
+	}
+
+	// temporary directory must be within the module, so the package can be resolved
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	dir, err := ioutil.TempDir(wd, "sqlike_gen_")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	src := new(bytes.Buffer)
+	if err := columnsProgram.Execute(src, map[string]interface{}{
+		"ImportPath": importPath,
+		"Types":      names,
+		"Package":    outputName,
+		"OutputPath": outputPath,
+	}); err != nil {
+		return err
+	}
+	program := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(program, src.Bytes(), 0644); err != nil {
+		return err
+	}
+
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command("go", "run", program)
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(stdout.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(*out, stdout.Bytes(), 0644)
+}
+
+// goList : return the import path and package name of the package pattern
+func goList(pattern string) (importPath, name string, err error) {
+	b, err := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", pattern).Output()
+	if err != nil {
+		if ee, ok := err.(*exec.ExitError); ok {
+			return "", "", fmt.Errorf("go list %s: %s", strconv.Quote(pattern), bytes.TrimSpace(ee.Stderr))
+		}
+		return "", "", err
+	}
+	paths := strings.Fields(string(b))
+	if len(paths) != 2 {
+		return "", "", fmt.Errorf("unexpected package %s", strconv.Quote(pattern))
+	}
+	return paths[0], paths[1], nil
+}
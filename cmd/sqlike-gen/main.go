@@ -0,0 +1,104 @@
+// Command sqlike-gen generates go source code for sqlike.
+//
+// Generate the go structs from the existing tables of the database :
+//
+//	sqlike-gen struct -uri "root:abcd1234@tcp(127.0.0.1:3306)/" -db sqlike -tables "User*" -pkg model -out model/table.go
+//
+// Generate the typed column descriptors of the entity structs, eg. `UserCols.Email.Equal(v)` :
+//
+//	//go:generate sqlike-gen columns -types User,Post -out user_cols.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Oskang09/sqlike/codegen"
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/options"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+const usage = `Usage: sqlike-gen <command> [flags]
+
+Commands:
+  struct    generate the go structs from the existing tables
+  columns   generate the typed column descriptors of the entity structs
+
+Use "sqlike-gen <command> -h" for more information about a command.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "struct":
+		err = runStruct(os.Args[2:])
+	case "columns":
+		err = runColumns(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "sqlike-gen: unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sqlike-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func runStruct(args []string) error {
+	fs := flag.NewFlagSet("struct", flag.ExitOnError)
+	var (
+		uri    = fs.String("uri", os.Getenv("SQLIKE_URI"), "connection string of the sql server, default is $SQLIKE_URI")
+		driver = fs.String("driver", "mysql", "sql driver name")
+		dbName = fs.String("db", "", "database name (required)")
+		tables = fs.String("tables", "", "comma separated table name patterns, all tables will be generated if it's empty")
+		pkg    = fs.String("pkg", "model", "package name of the generated file")
+		out    = fs.String("out", "", "output file, default is stdout")
+	)
+	fs.Parse(args)
+
+	if *uri == "" || *dbName == "" {
+		fs.Usage()
+		return fmt.Errorf("-uri and -db are required")
+	}
+
+	ctx := context.Background()
+	client, err := sqlike.Connect(
+		ctx,
+		*driver,
+		options.Connect().
+			ApplyURI(*uri),
+	)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	opts := []codegen.Option{codegen.WithPackage(*pkg)}
+	if *tables != "" {
+		opts = append(opts, codegen.WithTables(strings.Split(*tables, ",")...))
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return codegen.Generate(ctx, client.Database(*dbName), w, opts...)
+}
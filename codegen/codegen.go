@@ -0,0 +1,326 @@
+package codegen
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go/format"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+)
+
+// Schema : is the existing table which will be generated as go struct
+type Schema struct {
+	Name    string
+	Columns []sqlike.Column
+	Indexes []sqlike.IndexDetail
+}
+
+// Options :
+type Options struct {
+	// Package is the package name of the generated file, default is `model`
+	Package string
+
+	// Tables is the table name patterns which should be generated, all tables will be generated if it's empty
+	Tables []string
+
+	// ImportPath is the import path of the generated file, the types of this package will not be qualified,
+	// default is the package of the first entity
+	ImportPath string
+
+	// Mapper is used to walk the fields of the entities, default is `reflext.DefaultMapper`
+	Mapper reflext.StructMapper
+}
+
+// Option :
+type Option func(*Options)
+
+// WithPackage : set the package name of the generated file
+func WithPackage(pkg string) Option {
+	return func(opt *Options) {
+		opt.Package = pkg
+	}
+}
+
+// WithTables : only generate the tables which match any of the patterns, the syntax of the pattern is same as `path.Match`
+func WithTables(patterns ...string) Option {
+	return func(opt *Options) {
+		opt.Tables = append(opt.Tables, patterns...)
+	}
+}
+
+// WithImportPath : set the import path of the generated file
+func WithImportPath(path string) Option {
+	return func(opt *Options) {
+		opt.ImportPath = path
+	}
+}
+
+// WithMapper : set the struct mapper to walk the fields of the entities
+func WithMapper(mapper reflext.StructMapper) Option {
+	return func(opt *Options) {
+		opt.Mapper = mapper
+	}
+}
+
+// Generate : read the columns and indexes of the tables, and write the go structs into the writer.
+// The generated struct will produce the same table definition when it's migrated using `Table.Migrate`.
+func Generate(ctx context.Context, db *sqlike.Database, w io.Writer, opts ...Option) error {
+	opt := Options{Package: "model"}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	tables, err := db.ListTables(ctx)
+	if err != nil {
+		return err
+	}
+
+	schemas := make([]Schema, 0, len(tables))
+	for _, table := range tables {
+		if len(opt.Tables) > 0 && !matchAny(opt.Tables, table) {
+			continue
+		}
+
+		tb := db.Table(table)
+		columns, err := tb.ListColumns(ctx)
+		if err != nil {
+			return err
+		}
+		idxs, err := tb.Indexes().ListDetails(ctx)
+		if err != nil {
+			return err
+		}
+		schemas = append(schemas, Schema{Name: table, Columns: columns, Indexes: idxs})
+	}
+	if len(schemas) == 0 {
+		return errors.New("codegen: no table is matched")
+	}
+
+	b, err := Render(opt.Package, schemas...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Render : render the go source code of the schemas, the output is formatted using `gofmt`
+func Render(pkg string, schemas ...Schema) ([]byte, error) {
+	imports := make(map[string]string)
+	body := new(bytes.Buffer)
+	for _, schema := range schemas {
+		renderStruct(body, schema, imports)
+	}
+
+	return source(pkg, imports, body.Bytes())
+}
+
+// source : assemble the generated file, the imports is the map of import path and alias
+func source(pkg string, imports map[string]string, body []byte) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("// Code generated by sqlike-gen. DO NOT EDIT.\n\n")
+	buf.WriteString("package " + pkg + "\n\n")
+	if len(imports) > 0 {
+		// standard packages come first, follows `goimports`
+		std, others := make([]string, 0), make([]string, 0)
+		for p := range imports {
+			if strings.Contains(strings.SplitN(p, "/", 2)[0], ".") {
+				others = append(others, p)
+			} else {
+				std = append(std, p)
+			}
+		}
+		sort.Strings(std)
+		sort.Strings(others)
+		buf.WriteString("import (\n")
+		for _, p := range std {
+			buf.WriteString(imports[p] + " " + strconv.Quote(p) + "\n")
+		}
+		if len(std) > 0 && len(others) > 0 {
+			buf.WriteByte('\n')
+		}
+		for _, p := range others {
+			buf.WriteString(imports[p] + " " + strconv.Quote(p) + "\n")
+		}
+		buf.WriteString(")\n\n")
+	}
+	buf.Write(body)
+	return format.Source(buf.Bytes())
+}
+
+func renderStruct(w *bytes.Buffer, schema Schema, imports map[string]string) {
+	name := GoName(schema.Name)
+
+	// single column primary key and unique index can be declared using struct tag
+	var (
+		pk      string
+		uniques = make(map[string]bool)
+		idxs    = make([]sqlike.IndexDetail, 0, len(schema.Indexes))
+	)
+	for _, idx := range schema.Indexes {
+		if idx.Name == "PRIMARY" {
+			if len(idx.Columns) == 1 && idx.Columns[0].Length == 0 && !idx.Functional {
+				pk = idx.Columns[0].Name
+			}
+			continue
+		}
+		if isUniqueTag(idx) {
+			uniques[idx.Columns[0].Name] = true
+			continue
+		}
+		idxs = append(idxs, idx)
+	}
+
+	w.WriteString("// " + name + " : is the table `" + schema.Name + "`\n")
+	w.WriteString("type " + name + " struct {\n")
+	for _, col := range schema.Columns {
+		f := buildField(col, imports)
+		// auto increment column will be primary key and unique index implicitly
+		if col.Name == pk && !hasTag(f, "auto_increment") {
+			f.Tag = append(f.Tag, "primary_key")
+		}
+		if uniques[col.Name] && !hasTag(f, "auto_increment") {
+			f.Tag = append(f.Tag, "unique_index")
+		}
+		w.WriteString(f.Name + " " + f.Type)
+		if tag := f.StructTag(col.Name); tag != "" {
+			w.WriteString(" " + tag)
+		}
+		if f.Comment != "" {
+			w.WriteString(" // " + f.Comment)
+		}
+		w.WriteByte('\n')
+	}
+	w.WriteString("}\n\n")
+
+	if len(idxs) == 0 {
+		return
+	}
+
+	imports["github.com/Oskang09/sqlike/sqlike/indexes"] = ""
+	w.WriteString("// Indexes : is the indexes of the table `" + schema.Name + "`\n")
+	w.WriteString("func (" + name + ") Indexes() []indexes.Index {\n")
+	w.WriteString("return []indexes.Index{\n")
+	for _, idx := range idxs {
+		if idx.Functional && !hasExpressions(idx) {
+			// the expression of functional key part is only available on 8.0.13 and above
+			w.WriteString("// " + idx.Name + " : functional index is not supported\n")
+			continue
+		}
+		w.WriteString("{Name: " + strconv.Quote(idx.Name))
+		if typ := indexType(idx); typ != "" {
+			w.WriteString(", Type: indexes." + typ)
+		}
+		if idx.Functional {
+			w.WriteString(", Columns: []indexes.Col{")
+			for i, col := range idx.Columns {
+				if i > 0 {
+					w.WriteString(", ")
+				}
+				w.WriteString(keyPart(col))
+			}
+			w.WriteString("}")
+		} else {
+			w.WriteString(", Columns: indexes.Columns(")
+			for i, col := range idx.Columns {
+				if i > 0 {
+					w.WriteString(", ")
+				}
+				w.WriteString(strconv.Quote(columnName(col)))
+			}
+			w.WriteString(")")
+		}
+		if idx.Comment != "" {
+			w.WriteString(", Comment: " + strconv.Quote(idx.Comment))
+		}
+		w.WriteString("},\n")
+	}
+	w.WriteString("}\n}\n\n")
+}
+
+// isUniqueTag : whether the index is created by `unique_index` tag
+func isUniqueTag(idx sqlike.IndexDetail) bool {
+	if !idx.IsUnique || idx.Functional || len(idx.Columns) != 1 {
+		return false
+	}
+	col := idx.Columns[0]
+	if col.Length > 0 || col.Direction == indexes.Descending {
+		return false
+	}
+	return idx.Name == indexes.Index{Columns: indexes.Columns(col.Name)}.GetName()
+}
+
+func indexType(idx sqlike.IndexDetail) string {
+	switch idx.Type {
+	case "FULLTEXT":
+		return "FullText"
+	case "SPATIAL":
+		return "Spatial"
+	}
+	if idx.IsUnique {
+		return "Unique"
+	}
+	return ""
+}
+
+// hasExpressions : whether the expressions of all functional key parts are known
+func hasExpressions(idx sqlike.IndexDetail) bool {
+	for _, col := range idx.Columns {
+		if col.Name == "" && col.Expr == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// keyPart : the key part of functional index, eg. `indexes.Expr("lower(`Email`)")` or `indexes.Column("Name")`
+func keyPart(col indexes.Col) string {
+	if col.Expr == "" {
+		return "indexes.Column(" + strconv.Quote(columnName(col)) + ")"
+	}
+	if col.Direction == indexes.Descending {
+		return "indexes.Expr(" + strconv.Quote(col.Expr) + ", indexes.Descending)"
+	}
+	return "indexes.Expr(" + strconv.Quote(col.Expr) + ")"
+}
+
+// columnName : the key part in `indexes.Columns` syntax, eg. `-Age` or `Bio(20)`
+func columnName(col indexes.Col) string {
+	name := col.Name
+	if col.Length > 0 {
+		name += "(" + strconv.FormatUint(uint64(col.Length), 10) + ")"
+	}
+	if col.Direction == indexes.Descending {
+		name = "-" + name
+	}
+	return name
+}
+
+func hasTag(f field, tag string) bool {
+	for _, t := range f.Tag {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if strings.EqualFold(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
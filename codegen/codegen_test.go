@@ -0,0 +1,122 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoName(t *testing.T) {
+	require.Equal(t, "User", GoName("User"))
+	require.Equal(t, "UserID", GoName("user_id"))
+	require.Equal(t, "HTTPURL", GoName("http-url"))
+	require.Equal(t, "CreatedAt", GoName("created at"))
+	require.Equal(t, "X1stName", GoName("1st_name"))
+	require.Equal(t, "X", GoName("_"))
+}
+
+func TestRender(t *testing.T) {
+	str := func(v string) *string {
+		return &v
+	}
+	srid := uint32(4326)
+
+	b, err := Render("model", Schema{
+		Name: "user_profile",
+		Columns: []sqlike.Column{
+			{Name: "ID", Type: "BIGINT UNSIGNED", DataType: "BIGINT", Extra: "auto_increment"},
+			{Name: "Email", Type: "VARCHAR(191)", DataType: "VARCHAR", DefaultValue: str(""), Charset: str("utf8mb4")},
+			{Name: "code", Type: "CHAR(2)", DataType: "CHAR", DefaultValue: str(""), Charset: str("latin1")},
+			{Name: "Age", Type: "TINYINT UNSIGNED", DataType: "TINYINT", DefaultValue: str("18")},
+			{Name: "Active", Type: "TINYINT(1)", DataType: "TINYINT", DefaultValue: str("0")},
+			{Name: "Status", Type: "ENUM('ACTIVE','DISABLED')", DataType: "ENUM", Charset: str("utf8mb4")},
+			{Name: "Flags", Type: "SET('A','B')", DataType: "SET", Charset: str("utf8mb4")},
+			{Name: "Bio", Type: "TEXT", DataType: "TEXT", IsNullable: true, Comment: "about me"},
+			{Name: "Birthday", Type: "DATE", DataType: "DATE", IsNullable: true},
+			{Name: "Meta", Type: "JSON", DataType: "JSON", IsNullable: true},
+			{Name: "Location", Type: "POINT", DataType: "POINT", SRID: &srid},
+			{Name: "Total", Type: "INT", DataType: "INT", DefaultValue: str("0"), Expression: "`Age` * 2"},
+			{Name: "UpdatedAt", Type: "DATETIME(6)", DataType: "DATETIME", Extra: "DEFAULT_GENERATED on update CURRENT_TIMESTAMP(6)"},
+			{Name: "Hash", Type: "BINARY(16)", DataType: "BINARY"},
+			{Name: "Token", Type: "VARBINARY(255)", DataType: "VARBINARY", IsNullable: true},
+		},
+		Indexes: []sqlike.IndexDetail{
+			{Index: sqlike.Index{Name: "PRIMARY", Type: "BTREE", IsUnique: true}, Columns: indexes.Columns("ID")},
+			{Index: sqlike.Index{Name: indexes.Index{Columns: indexes.Columns("Email")}.GetName(), Type: "BTREE", IsUnique: true}, Columns: indexes.Columns("Email")},
+			{Index: sqlike.Index{Name: "IX_Age_Bio", Type: "BTREE"}, Columns: indexes.Columns("-Age", "Bio(20)")},
+			{Index: sqlike.Index{Name: "FTX_Bio", Type: "FULLTEXT"}, Columns: indexes.Columns("Bio")},
+			{Index: sqlike.Index{Name: "UX_Email_Lower", Type: "BTREE", IsUnique: true}, Columns: []indexes.Col{indexes.Expr("lower(`Email`)", indexes.Descending), indexes.Column("Age")}, Functional: true},
+			// the expression is unknown before 8.0.13
+			{Index: sqlike.Index{Name: "IX_Legacy", Type: "BTREE"}, Columns: []indexes.Col{{}}, Functional: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by sqlike-gen. DO NOT EDIT.
+
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"cloud.google.com/go/civil"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/Oskang09/sqlike/types"
+	"github.com/paulmach/orb"
+)
+
+// UserProfile : is the table `+"`user_profile`"+`
+type UserProfile struct {
+	ID        uint64 `+"`sqlike:\",auto_increment\"`"+`
+	Email     string `+"`sqlike:\",unique_index\"`"+`
+	Code      string `+"`sqlike:\"code,char=2,charset=latin1\"`"+`
+	Age       uint8  `+"`sqlike:\",default=18\"`"+`
+	Active    bool
+	Status    string    `+"`sqlike:\",enum=ACTIVE|DISABLED\"`"+`
+	Flags     types.Set `+"`sqlike:\",set=A|B\"`"+`
+	Bio       *string   `+"`sqlike:\",text,comment=about me\"`"+`
+	Birthday  *civil.Date
+	Meta      json.RawMessage
+	Location  orb.Point `+"`sqlike:\",srid=4326\"`"+`
+	Total     int       `+"`sqlike:\",generated_column\"`"+`
+	UpdatedAt time.Time `+"`sqlike:\",on_update\"`"+`
+	Hash      []byte    `+"`sqlike:\",char=16\"`"+`
+	Token     []byte    `+"`sqlike:\",size=255\"`"+`
+}
+
+// Indexes : is the indexes of the table `+"`user_profile`"+`
+func (UserProfile) Indexes() []indexes.Index {
+	return []indexes.Index{
+		{Name: "IX_Age_Bio", Columns: indexes.Columns("-Age", "Bio(20)")},
+		{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio")},
+		{Name: "UX_Email_Lower", Type: indexes.Unique, Columns: []indexes.Col{indexes.Expr("lower(`+"`Email`"+`)", indexes.Descending), indexes.Column("Age")}},
+		// IX_Legacy : functional index is not supported
+	}
+}
+`, string(b))
+}
+
+func TestBuildField(t *testing.T) {
+	imports := make(map[string]string)
+	dflt := "1.50"
+	for _, c := range []struct {
+		col  sqlike.Column
+		typ  string
+		tags []string
+	}{
+		{sqlike.Column{Name: "Amount", Type: "decimal(10,2)", DataType: "DECIMAL", DefaultValue: &dflt}, "types.Decimal", []string{"precision=10", "scale=2", "default=1.50"}},
+		{sqlike.Column{Name: "Rate", Type: "decimal(65,30) unsigned", DataType: "DECIMAL", IsNullable: true}, "*types.Decimal", []string{"unsigned"}},
+		{sqlike.Column{Name: "Count", Type: "numeric(20,0)", DataType: "NUMERIC"}, "types.Decimal", []string{"precision=20"}},
+		{sqlike.Column{Name: "Score", Type: "double", DataType: "DOUBLE"}, "float64", nil},
+		{sqlike.Column{Name: "Note", Type: "tinytext", DataType: "TINYTEXT"}, "string", []string{"text=tiny"}},
+		{sqlike.Column{Name: "Body", Type: "mediumtext", DataType: "MEDIUMTEXT"}, "string", []string{"text=medium"}},
+		{sqlike.Column{Name: "Raw", Type: "longtext", DataType: "LONGTEXT"}, "string", []string{"text=long"}},
+	} {
+		f := buildField(c.col, imports)
+		require.Equal(t, c.typ, f.Type, c.col.Name)
+		require.Equal(t, c.tags, f.Tag, c.col.Name)
+	}
+	require.Contains(t, imports, "github.com/Oskang09/sqlike/types")
+}
@@ -0,0 +1,253 @@
+package codegen
+
+import (
+	"bytes"
+	"errors"
+	"go/token"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Oskang09/sqlike/reflext"
+)
+
+// GenerateColumns : walk the fields of the entities and write the typed column descriptors into the writer,
+// so the column name will be checked on compile time, eg. `UserCols.Email.Equal("a@b.com")`.
+//
+// It's designed to be used with `go generate`, see `sqlike-gen columns`.
+func GenerateColumns(w io.Writer, entities []interface{}, opts ...Option) error {
+	b, err := RenderColumns(entities, opts...)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// RenderColumns : render the go source code of the typed column descriptors of the entities. Every
+// entity will have a `<Name>Cols` variable, and every column descriptor is typed by the field type, eg.
+//
+//	var UserCols = struct {
+//		ID    colInt64
+//		Email colString
+//	}{...}
+//
+// The descriptors produce the same `primitive.C`, `primitive.Sort` and `primitive.KV`
+// as the functions of package `expr`.
+func RenderColumns(entities []interface{}, opts ...Option) ([]byte, error) {
+	opt := Options{Mapper: reflext.DefaultMapper}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if len(entities) == 0 {
+		return nil, errors.New("codegen: no entity is provided")
+	}
+
+	types := make([]reflect.Type, 0, len(entities))
+	for _, it := range entities {
+		t := reflext.Deref(reflext.TypeOf(it))
+		if t == nil || t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, errors.New("codegen: expected named struct as entity")
+		}
+		types = append(types, t)
+	}
+	if opt.ImportPath == "" {
+		opt.ImportPath = types[0].PkgPath()
+	}
+	if opt.Package == "" {
+		opt.Package = opt.ImportPath[strings.LastIndex(opt.ImportPath, "/")+1:]
+	}
+
+	g := &colGen{
+		importPath: opt.ImportPath,
+		imports: map[string]string{
+			"github.com/Oskang09/sqlike/sql/expr":         "",
+			"github.com/Oskang09/sqlike/sqlike/primitive": "",
+		},
+		aliases: map[string]string{
+			"expr":      "github.com/Oskang09/sqlike/sql/expr",
+			"primitive": "github.com/Oskang09/sqlike/sqlike/primitive",
+		},
+		descs: make(map[string]bool),
+		body:  new(bytes.Buffer),
+		types: new(bytes.Buffer),
+	}
+	for _, t := range types {
+		g.entity(t, opt.Mapper.CodecByType(t))
+	}
+
+	g.body.Write(g.types.Bytes())
+	return source(opt.Package, g.imports, g.body.Bytes())
+}
+
+type colGen struct {
+	importPath string
+	imports    map[string]string
+	aliases    map[string]string
+	descs      map[string]bool
+	body       *bytes.Buffer
+	types      *bytes.Buffer
+}
+
+func (g *colGen) entity(t reflect.Type, codec reflext.Structer) {
+	name := t.Name()
+	if t.PkgPath() != g.importPath {
+		name = g.qualifier(t) + "." + name
+	}
+
+	fields := codec.Properties()
+	names := fieldNames(t, fields)
+	w := g.body
+	w.WriteString("// " + t.Name() + "Cols : is the typed columns of `" + name + "`\n")
+	w.WriteString("var " + t.Name() + "Cols = struct {\n")
+	for i, sf := range fields {
+		w.WriteString(names[i] + " " + g.descriptor(sf.Type()) + "\n")
+	}
+	w.WriteString("}{\n")
+	for i, sf := range fields {
+		w.WriteString(names[i] + ": " + strconv.Quote(sf.Name()) + ",\n")
+	}
+	w.WriteString("}\n\n")
+}
+
+// fieldNames : the descriptor names of the columns, the go field name is used unless it's
+// declared by more than one embedded struct, then it's named by the column name instead
+func fieldNames(t reflect.Type, fields []reflext.StructFielder) []string {
+	names := make([]string, len(fields))
+	count := make(map[string]int, len(fields))
+	for i, sf := range fields {
+		names[i] = t.FieldByIndex(sf.Index()).Name
+		count[names[i]]++
+	}
+
+	used := make(map[string]bool, len(fields))
+	for _, name := range names {
+		if count[name] == 1 {
+			used[name] = true
+		}
+	}
+	for i, sf := range fields {
+		if count[names[i]] == 1 {
+			continue
+		}
+		name := GoName(sf.Name())
+		for n := 2; used[name]; n++ {
+			name = GoName(sf.Name()) + strconv.Itoa(n)
+		}
+		used[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// descriptor : declare the descriptor type of the field type if it's not declared yet
+func (g *colGen) descriptor(t reflect.Type) string {
+	typ := g.typeName(t)
+	name := "col" + descName(typ)
+	if g.descs[name] {
+		return name
+	}
+	g.descs[name] = true
+
+	w := g.types
+	w.WriteString("// " + name + " : is the column of `" + typ + "`\n")
+	w.WriteString("type " + name + " string\n\n")
+	method := func(doc, sig, body string) {
+		w.WriteString("// " + doc + "\n")
+		w.WriteString("func (c " + name + ") " + sig + " {\n" + body + "\n}\n\n")
+	}
+	method("Name : return the column name", "Name() string", "return string(c)")
+	method("Equal :", "Equal(v "+typ+") primitive.C", "return expr.Equal(string(c), v)")
+	method("NotEqual :", "NotEqual(v "+typ+") primitive.C", "return expr.NotEqual(string(c), v)")
+	method("In :", "In(values ..."+typ+") primitive.C", "return expr.In(string(c), values)")
+	method("NotIn :", "NotIn(values ..."+typ+") primitive.C", "return expr.NotIn(string(c), values)")
+	method("GreaterThan :", "GreaterThan(v "+typ+") primitive.C", "return expr.GreaterThan(string(c), v)")
+	method("GreaterOrEqual :", "GreaterOrEqual(v "+typ+") primitive.C", "return expr.GreaterOrEqual(string(c), v)")
+	method("LesserThan :", "LesserThan(v "+typ+") primitive.C", "return expr.LesserThan(string(c), v)")
+	method("LesserOrEqual :", "LesserOrEqual(v "+typ+") primitive.C", "return expr.LesserOrEqual(string(c), v)")
+	method("Between :", "Between(from, to "+typ+") primitive.C", "return expr.Between(string(c), from, to)")
+	if reflext.Deref(t).Kind() == reflect.String {
+		method("Like :", "Like(pattern string) primitive.L", "return expr.Like(string(c), pattern)")
+		method("NotLike :", "NotLike(pattern string) primitive.L", "return expr.NotLike(string(c), pattern)")
+	}
+	if reflext.IsNullable(t) {
+		method("IsNull :", "IsNull() primitive.Nil", "return expr.IsNull(string(c))")
+		method("NotNull :", "NotNull() primitive.Nil", "return expr.NotNull(string(c))")
+	}
+	method("Asc :", "Asc() primitive.Sort", "return expr.Asc(string(c))")
+	method("Desc :", "Desc() primitive.Sort", "return expr.Desc(string(c))")
+	method("Set : set the column value on update", "Set(v "+typ+") primitive.KV", "return expr.ColumnValue(string(c), v)")
+	return name
+}
+
+// typeName : return the type expression of the type in the generated file, the package will be imported if it's necessary
+func (g *colGen) typeName(t reflect.Type) string {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name()
+		}
+		if t.PkgPath() == g.importPath {
+			return t.Name()
+		}
+		// unexported type cannot be referenced by other package
+		if !token.IsExported(t.Name()) {
+			return "interface{}"
+		}
+		return g.qualifier(t) + "." + t.Name()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return "*" + g.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeName(t.Elem())
+	case reflect.Array:
+		return "[" + strconv.Itoa(t.Len()) + "]" + g.typeName(t.Elem())
+	case reflect.Map:
+		return "map[" + g.typeName(t.Key()) + "]" + g.typeName(t.Elem())
+	default:
+		// anonymous struct, func, chan and interface
+		return "interface{}"
+	}
+}
+
+// qualifier : import the package of the type, and return the package name or alias
+func (g *colGen) qualifier(t reflect.Type) string {
+	path := t.PkgPath()
+	// the package name might be different with the last element of import path, eg. `gopkg.in/yaml.v3`
+	name := strings.TrimSuffix(t.String(), "."+t.Name())
+	alias := name
+	for i := 2; ; i++ {
+		if p, ok := g.aliases[alias]; !ok || p == path {
+			break
+		}
+		alias = name + strconv.Itoa(i)
+	}
+	g.aliases[alias] = path
+	g.imports[path] = ""
+	if alias != name {
+		g.imports[path] = alias
+	}
+	return alias
+}
+
+// descName : convert the type expression to identifier, eg. `*time.Time` => `PtrTimeTime`
+func descName(typ string) string {
+	replacer := strings.NewReplacer(
+		"*", " Ptr ",
+		"[]", " Slice ",
+		"map[", " Map ",
+		"interface{}", " Any ",
+		"[", " Array ",
+		"]", " ",
+		".", " ",
+	)
+	blr := new(strings.Builder)
+	for _, p := range strings.Fields(replacer.Replace(typ)) {
+		runes := []rune(p)
+		runes[0] = []rune(strings.ToUpper(string(runes[0])))[0]
+		blr.WriteString(string(runes))
+	}
+	return blr.String()
+}
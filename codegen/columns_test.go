@@ -0,0 +1,137 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	gotypes "go/types"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Oskang09/sqlike/types"
+	"github.com/stretchr/testify/require"
+)
+
+type colsEntity struct {
+	ID        int64
+	Email     string `sqlike:"email_address"`
+	Tags      []string
+	Key       *types.Key
+	CreatedAt time.Time
+	Skip      string `sqlike:"-"`
+}
+
+type colsAudit struct {
+	Title     string `sqlike:"audit_title"`
+	UpdatedAt time.Time
+}
+
+type colsMeta struct {
+	Title string `sqlike:"meta_title"`
+	Note  string
+}
+
+type colsEmbedded struct {
+	ID int64
+	colsAudit
+	colsMeta
+}
+
+// the imported packages are type checked from source once and shared by the tests
+var (
+	checkFset     = token.NewFileSet()
+	checkImporter = importer.ForCompiler(checkFset, "source", nil)
+)
+
+// typeCheck : the generated source must be formatted and compiled
+func typeCheck(t *testing.T, src []byte) {
+	b, err := format.Source(src)
+	require.NoError(t, err)
+	require.Equal(t, string(b), string(src))
+
+	// resolve the imports of the generated file from the module of this package
+	dir, err := os.Getwd()
+	require.NoError(t, err)
+	f, err := parser.ParseFile(checkFset, filepath.Join(dir, "generated.go"), src, 0)
+	require.NoError(t, err)
+	conf := gotypes.Config{Importer: checkImporter}
+	_, err = conf.Check(f.Name.Name, checkFset, []*ast.File{f}, nil)
+	require.NoError(t, err)
+}
+
+func TestRenderColumns(t *testing.T) {
+	b, err := RenderColumns([]interface{}{colsEntity{}}, WithPackage("model"))
+	require.NoError(t, err)
+	typeCheck(t, b)
+
+	src := string(b)
+	require.Contains(t, src, `package model
+
+import (
+	"time"
+
+	"github.com/Oskang09/sqlike/sql/expr"
+	"github.com/Oskang09/sqlike/sqlike/primitive"
+	"github.com/Oskang09/sqlike/types"
+)`)
+	require.Contains(t, src, `var colsEntityCols = struct {
+	ID        colInt64
+	Email     colString
+	Tags      colSliceString
+	Key       colPtrTypesKey
+	CreatedAt colTimeTime
+}{
+	ID:        "ID",
+	Email:     "email_address",
+	Tags:      "Tags",
+	Key:       "Key",
+	CreatedAt: "CreatedAt",
+}`)
+	require.Contains(t, src, `func (c colString) Equal(v string) primitive.C {
+	return expr.Equal(string(c), v)
+}`)
+	require.Contains(t, src, `func (c colInt64) In(values ...int64) primitive.C {
+	return expr.In(string(c), values)
+}`)
+	require.Contains(t, src, `func (c colTimeTime) Desc() primitive.Sort {
+	return expr.Desc(string(c))
+}`)
+	require.Contains(t, src, `func (c colPtrTypesKey) IsNull() primitive.Nil {`)
+	require.Contains(t, src, `func (c colString) Like(pattern string) primitive.L {`)
+	require.NotContains(t, src, `func (c colInt64) Like(`)
+	require.NotContains(t, src, `func (c colInt64) IsNull(`)
+	require.NotContains(t, src, "Skip")
+}
+
+func TestRenderColumnsEmbedded(t *testing.T) {
+	b, err := RenderColumns([]interface{}{colsEmbedded{}}, WithPackage("model"))
+	require.NoError(t, err)
+	typeCheck(t, b)
+
+	src := string(b)
+	require.Contains(t, src, `var colsEmbeddedCols = struct {
+	ID         colInt64
+	AuditTitle colString
+	UpdatedAt  colTimeTime
+	MetaTitle  colString
+	Note       colString
+}{
+	ID:         "ID",
+	AuditTitle: "audit_title",
+	UpdatedAt:  "UpdatedAt",
+	MetaTitle:  "meta_title",
+	Note:       "Note",
+}`)
+}
+
+func TestDescName(t *testing.T) {
+	require.Equal(t, "String", descName("string"))
+	require.Equal(t, "PtrTimeTime", descName("*time.Time"))
+	require.Equal(t, "SliceByte", descName("[]byte"))
+	require.Equal(t, "MapStringAny", descName("map[string]interface{}"))
+	require.Equal(t, "Array4Uint8", descName("[4]uint8"))
+}
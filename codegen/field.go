@@ -0,0 +1,299 @@
+package codegen
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/types"
+)
+
+var (
+	precisionRegexp = regexp.MustCompile(`\((\d+)\)`)
+	decimalRegexp   = regexp.MustCompile(`\((\d+)(?:,(\d+))?\)`)
+	valuesRegexp    = regexp.MustCompile(`'((?:[^']|'')*)'`)
+)
+
+// field : is the struct field of the column
+type field struct {
+	Name    string
+	Type    string
+	Tag     []string
+	Comment string
+}
+
+// StructTag : return the struct tag of the field, it will be empty if there is no option
+func (f field) StructTag(column string) string {
+	name := ""
+	if column != f.Name {
+		name = column
+	}
+	if name == "" && len(f.Tag) == 0 {
+		return ""
+	}
+	return "`sqlike:\"" + strings.Join(append([]string{name}, f.Tag...), ",") + "\"`"
+}
+
+// buildField : map the column to the go type and `sqlike` tag, so it will produce the same
+// column definition when it's migrated using `Table.Migrate`
+func buildField(col sqlike.Column, imports map[string]string) field {
+	var (
+		f         = field{Name: GoName(col.Name)}
+		dataType  = strings.ToUpper(col.DataType)
+		colType   = strings.ToLower(col.Type)
+		unsigned  = strings.Contains(colType, "unsigned")
+		nullable  = bool(col.IsNullable)
+		extra     = strings.ToLower(col.Extra)
+		dflt      string
+		hasDflt   = col.DefaultValue != nil
+		pointable = true
+	)
+	if hasDflt {
+		dflt = *col.DefaultValue
+	}
+
+	if col.Expression != "" {
+		f.Tag = append(f.Tag, "generated_column")
+	}
+
+	switch dataType {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT":
+		if dataType == "TINYINT" && strings.HasPrefix(colType, "tinyint(1)") {
+			f.Type = "bool"
+			break
+		}
+		f.Type = intType(dataType, unsigned)
+		if strings.Contains(extra, "auto_increment") {
+			f.Tag = append(f.Tag, "auto_increment")
+		} else if hasDflt && dflt != "0" {
+			f.Tag = append(f.Tag, "default="+dflt)
+		}
+
+	case "FLOAT", "DOUBLE", "REAL":
+		f.Type = "float64"
+		if dataType == "FLOAT" {
+			f.Type = "float32"
+		}
+		if unsigned {
+			f.Tag = append(f.Tag, "unsigned")
+		}
+		if hasDflt {
+			if v, err := strconv.ParseFloat(dflt, 64); err == nil && v != 0 {
+				f.Tag = append(f.Tag, "default="+dflt)
+			}
+		}
+
+	case "DECIMAL", "NUMERIC":
+		imports["github.com/Oskang09/sqlike/types"] = ""
+		f.Type = "types.Decimal"
+		f.Tag = append(f.Tag, decimalTag(colType)...)
+		if unsigned {
+			f.Tag = append(f.Tag, "unsigned")
+		}
+		if hasDflt {
+			if v, err := types.ParseDecimal(dflt); err == nil && !v.IsZero() {
+				f.Tag = append(f.Tag, "default="+dflt)
+			}
+		}
+
+	case "CHAR", "VARCHAR":
+		f.Type = "string"
+		size := parsePrecision(colType)
+		if dataType == "CHAR" {
+			f.Tag = append(f.Tag, "char="+strconv.Itoa(size))
+		} else if size != 191 {
+			f.Tag = append(f.Tag, "size="+strconv.Itoa(size))
+		}
+		f.Tag = append(f.Tag, charsetTag(col)...)
+		if hasDflt && dflt != "" && isTagValue(dflt) {
+			f.Tag = append(f.Tag, "default="+dflt)
+		}
+
+	case "TINYTEXT", "TEXT", "MEDIUMTEXT", "LONGTEXT":
+		f.Type = "string"
+		f.Tag = append(f.Tag, textTag(dataType))
+		f.Tag = append(f.Tag, charsetTag(col)...)
+
+	case "ENUM":
+		f.Type = "string"
+		values := parseValues(col.Type)
+		if !isTagValue(values...) {
+			f.Comment = col.Type
+			break
+		}
+		f.Tag = append(f.Tag, "enum="+strings.Join(values, "|"))
+		f.Tag = append(f.Tag, charsetTag(col)...)
+
+	case "SET":
+		imports["github.com/Oskang09/sqlike/types"] = ""
+		f.Type = "types.Set"
+		pointable = false
+		values := parseValues(col.Type)
+		if !isTagValue(values...) {
+			f.Comment = col.Type
+			break
+		}
+		f.Tag = append(f.Tag, "set="+strings.Join(values, "|"))
+
+	case "DATE":
+		imports["cloud.google.com/go/civil"] = ""
+		f.Type = "civil.Date"
+
+	case "TIME":
+		imports["cloud.google.com/go/civil"] = ""
+		f.Type = "civil.Time"
+		if size := parsePrecision(colType); size != 6 {
+			f.Tag = append(f.Tag, "size="+strconv.Itoa(size))
+		}
+
+	case "DATETIME", "TIMESTAMP":
+		imports["time"] = ""
+		f.Type = "time.Time"
+		if size := parsePrecision(colType); size != 6 {
+			f.Tag = append(f.Tag, "size="+strconv.Itoa(size))
+		}
+		if strings.Contains(extra, "on update") {
+			f.Tag = append(f.Tag, "on_update")
+		}
+
+	case "JSON":
+		imports["encoding/json"] = ""
+		f.Type = "json.RawMessage"
+		pointable = false
+
+	case "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING", "MULTIPOLYGON":
+		imports["github.com/paulmach/orb"] = ""
+		f.Type = "orb." + spatialType(dataType)
+		if col.SRID != nil {
+			f.Tag = append(f.Tag, "srid="+strconv.FormatUint(uint64(*col.SRID), 10))
+		}
+
+	case "BINARY", "VARBINARY":
+		f.Type = "[]byte"
+		pointable = false
+		if dataType == "BINARY" {
+			f.Tag = append(f.Tag, "char="+strconv.Itoa(parsePrecision(colType)))
+		} else {
+			f.Tag = append(f.Tag, "size="+strconv.Itoa(parsePrecision(colType)))
+		}
+
+	default:
+		// BLOB, GEOMETRY and any other unknown data type
+		f.Type = "[]byte"
+		f.Comment = col.Type
+		pointable = false
+	}
+
+	if nullable && pointable {
+		f.Type = "*" + f.Type
+	}
+
+	if col.Comment != "" && len(col.Comment) <= 60 && isTagValue(col.Comment) {
+		f.Tag = append(f.Tag, "comment="+col.Comment)
+	}
+	return f
+}
+
+func intType(dataType string, unsigned bool) string {
+	typ := "int"
+	switch dataType {
+	case "TINYINT":
+		typ = "int8"
+	case "SMALLINT":
+		typ = "int16"
+	case "MEDIUMINT":
+		typ = "int32"
+	case "BIGINT":
+		typ = "int64"
+	}
+	if unsigned {
+		typ = "u" + typ
+	}
+	return typ
+}
+
+// decimalTag : the `DECIMAL(65,30)` is the default of `types.Decimal`, and the scale is 0
+// when only the precision is tagged
+func decimalTag(colType string) []string {
+	paths := decimalRegexp.FindStringSubmatch(colType)
+	if paths == nil {
+		// `DECIMAL` is `DECIMAL(10,0)`
+		return []string{"precision=10"}
+	}
+	if paths[1] == "65" && paths[2] == "30" {
+		return nil
+	}
+	tag := []string{"precision=" + paths[1]}
+	if paths[2] != "" && paths[2] != "0" {
+		tag = append(tag, "scale="+paths[2])
+	}
+	return tag
+}
+
+func textTag(dataType string) string {
+	switch dataType {
+	case "TINYTEXT":
+		return "text=tiny"
+	case "MEDIUMTEXT":
+		return "text=medium"
+	case "LONGTEXT":
+		return "text=long"
+	default:
+		return "text"
+	}
+}
+
+func spatialType(dataType string) string {
+	switch dataType {
+	case "LINESTRING":
+		return "LineString"
+	case "POLYGON":
+		return "Polygon"
+	case "MULTIPOINT":
+		return "MultiPoint"
+	case "MULTILINESTRING":
+		return "MultiLineString"
+	case "MULTIPOLYGON":
+		return "MultiPolygon"
+	default:
+		return "Point"
+	}
+}
+
+func charsetTag(col sqlike.Column) []string {
+	if col.Charset == nil || strings.EqualFold(*col.Charset, "utf8mb4") {
+		return nil
+	}
+	return []string{"charset=" + strings.ToLower(*col.Charset)}
+}
+
+// parsePrecision : return the first number in parentheses, eg. `varchar(20)` => 20, `datetime` => 0
+func parsePrecision(colType string) int {
+	paths := precisionRegexp.FindStringSubmatch(colType)
+	if paths == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(paths[1])
+	return n
+}
+
+// parseValues : return the values of `ENUM` or `SET`, eg. `enum('a','b')` => [a b]
+func parseValues(colType string) []string {
+	matches := valuesRegexp.FindAllStringSubmatch(colType, -1)
+	values := make([]string, 0, len(matches))
+	for _, m := range matches {
+		values = append(values, strings.ReplaceAll(m[1], "''", "'"))
+	}
+	return values
+}
+
+// isTagValue : the value cannot contains the separators of struct tag
+func isTagValue(values ...string) bool {
+	for _, v := range values {
+		if strings.ContainsAny(v, ",|\"`") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package codegen
+
+import (
+	"go/token"
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms : is the initialisms which should be upper case, follows `golint`
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"UUID":  true,
+	"VM":    true,
+	"XML":   true,
+}
+
+// GoName : convert the sql name to exported go identifier, the name will be kept
+// if it's already an exported identifier, eg. `user_id` => `UserID`
+func GoName(name string) string {
+	if token.IsIdentifier(name) && token.IsExported(name) {
+		return name
+	}
+
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	blr := new(strings.Builder)
+	for _, p := range parts {
+		upper := strings.ToUpper(p)
+		if commonInitialisms[upper] {
+			blr.WriteString(upper)
+			continue
+		}
+		runes := []rune(p)
+		runes[0] = unicode.ToUpper(runes[0])
+		blr.WriteString(string(runes))
+	}
+
+	str := blr.String()
+	if str == "" {
+		return "X"
+	}
+	if unicode.IsDigit([]rune(str)[0]) {
+		str = "X" + str
+	}
+	return str
+}
@@ -3,10 +3,13 @@ package examples
 import (
 	"context"
 	"testing"
+	"time"
 
 	plugin "github.com/Oskang09/sqlike/plugin/casbin"
 	"github.com/Oskang09/sqlike/sql/expr"
 	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/sqlike/options"
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/persist"
 	"github.com/stretchr/testify/require"
@@ -104,9 +107,123 @@ func CasbinExamples(ctx context.Context, t *testing.T, db *sqlike.Database) {
 		require.NoError(t, err)
 	}
 
-	// Remove Policy
+	// Batch and update policies
 	{
+		supportRules := [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "POST"},
+		}
+		ok, err = e.AddPolicies(supportRules)
+		require.True(t, ok)
+		require.NoError(t, err)
+
+		ok, err = e.UpdatePolicy(supportRules[1], []string{"support", "/tickets", "PUT"})
+		require.True(t, ok)
+		require.NoError(t, err)
+
+		ok, err = e.Enforce("support", "/tickets", "PUT")
+		require.True(t, ok)
+		require.NoError(t, err)
+
+		ok, err = e.RemovePolicies([][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "PUT"},
+		})
+		require.True(t, ok)
+		require.NoError(t, err)
+		require.Empty(t, e.GetFilteredPolicy(0, "support"))
+	}
+
+	// Batch and update policies with the adapter
+	{
+		batchTable := db.Table("AccessPolicyBatch")
+		err = batchTable.DropIfExists(ctx)
+		require.NoError(t, err)
+
+		ba := plugin.MustNew(ctx, batchTable)
+		batch := ba.(persist.BatchAdapter)
+		updatable := ba.(persist.UpdatableAdapter)
+
+		stored := func() [][]string {
+			result, err := batchTable.Find(
+				ctx,
+				actions.Find().
+					OrderBy(expr.Asc("V0"), expr.Asc("V1"), expr.Asc("V2")),
+				options.Find().
+					SetNoLimit(true),
+			)
+			require.NoError(t, err)
+			policies := make([]plugin.Policy, 0)
+			err = result.All(&policies)
+			require.NoError(t, err)
+			rules := make([][]string, 0, len(policies))
+			for _, p := range policies {
+				rules = append(rules, []string{p.V0, p.V1, p.V2})
+			}
+			return rules
+		}
+
+		// the duplicate rule is ignored
+		err = batch.AddPolicies("p", "p", [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "POST"},
+			{"viewer", "/tickets", "GET"},
+		})
+		require.NoError(t, err)
+		err = batch.AddPolicies("p", "p", [][]string{
+			{"support", "/tickets", "GET"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "POST"},
+			{"viewer", "/tickets", "GET"},
+		}, stored())
+
+		err = updatable.UpdatePolicies("p", "p",
+			[][]string{{"support", "/tickets", "POST"}},
+			[][]string{{"support", "/tickets", "PUT"}},
+		)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "PUT"},
+			{"viewer", "/tickets", "GET"},
+		}, stored())
+
+		// the whole update is rolled back when any of the old rules doesn't exist
+		err = updatable.UpdatePolicies("p", "p",
+			[][]string{{"support", "/tickets", "GET"}, {"support", "/tickets", "DELETE"}},
+			[][]string{{"support", "/tickets", "HEAD"}, {"support", "/tickets", "PATCH"}},
+		)
+		require.Equal(t, sqlike.ErrNoRecordAffected, err)
+		require.Equal(t, [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "PUT"},
+			{"viewer", "/tickets", "GET"},
+		}, stored())
+
+		oldRules, err := updatable.UpdateFilteredPolicies("p", "p",
+			[][]string{{"support", "/reports", "GET"}},
+			0, "support",
+		)
+		require.NoError(t, err)
+		require.ElementsMatch(t, [][]string{
+			{"support", "/tickets", "GET"},
+			{"support", "/tickets", "PUT"},
+		}, oldRules)
+		require.Equal(t, [][]string{
+			{"support", "/reports", "GET"},
+			{"viewer", "/tickets", "GET"},
+		}, stored())
 
+		err = batch.RemovePolicies("p", "p", [][]string{
+			{"support", "/reports", "GET"},
+			{"viewer", "/tickets", "GET"},
+			{"unknown", "/tickets", "GET"},
+		})
+		require.NoError(t, err)
+		require.Empty(t, stored())
 	}
 
 	// Query Policy with where conditions
@@ -129,4 +246,71 @@ func CasbinExamples(ctx context.Context, t *testing.T, db *sqlike.Database) {
 		require.ElementsMatch(t, marketingRules, e.GetPolicy())
 	}
 
+	// Watch policy changes from other instances
+	{
+		revisionTable := db.Table("AccessPolicyRevision")
+		err = revisionTable.DropIfExists(ctx)
+		require.NoError(t, err)
+
+		w1 := plugin.MustNewWatcher(ctx, revisionTable, plugin.WithInterval(100*time.Millisecond))
+		defer w1.Close()
+		w2 := plugin.MustNewWatcher(ctx, revisionTable, plugin.WithInterval(100*time.Millisecond))
+		defer w2.Close()
+
+		updated := make(chan string, 1)
+		err = w2.SetUpdateCallback(func(rev string) {
+			updated <- rev
+		})
+		require.NoError(t, err)
+
+		err = w1.Update()
+		require.NoError(t, err)
+
+		select {
+		case rev := <-updated:
+			require.Equal(t, "1", rev)
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher is not notified")
+		}
+
+		// the revision is increased within the adapter write
+		linked := plugin.MustNew(ctx, table, plugin.WithWatcher(w1))
+		err = linked.AddPolicy("p", "p", []string{"auditor", "/reports", "GET"})
+		require.NoError(t, err)
+
+		select {
+		case rev := <-updated:
+			require.Equal(t, "2", rev)
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher is not notified")
+		}
+
+		// it's a no-op since the watcher is linked to the adapter
+		err = w1.Update()
+		require.NoError(t, err)
+
+		// the revision 3 is changed by the other instance before the adapter write, so the
+		// current instance should be notified as well
+		notified := make(chan string, 2)
+		err = w1.SetUpdateCallback(func(rev string) {
+			notified <- rev
+		})
+		require.NoError(t, err)
+		err = w2.Update()
+		require.NoError(t, err)
+		err = linked.AddPolicy("p", "p", []string{"auditor", "/reports", "POST"})
+		require.NoError(t, err)
+
+		select {
+		case <-notified:
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher is not notified")
+		}
+		select {
+		case rev := <-updated:
+			require.Equal(t, "4", rev)
+		case <-time.After(5 * time.Second):
+			t.Fatal("watcher is not notified")
+		}
+	}
 }
@@ -125,24 +125,34 @@ func SQLDumpExamples(ctx context.Context, t *testing.T, client *sqlike.Client) {
 		err = table.Truncate(ctx)
 		require.NoError(t, err)
 
-		// b, err := ioutil.ReadFile(file.Name())
-		// if err != nil {
-		// 	panic(err)
-		// }
-
-		// log.Println(string(b))
-		// result, err := client.Exec(string(b))
-		// require.NoError(t, err)
-		// affected, err := result.RowsAffected()
-		// require.NoError(t, err)
-		// log.Println(affected)
+		file, err = os.Open(file.Name())
+		require.NoError(t, err)
+		defer file.Close()
+
+		affected, err := dumper.RestoreFrom(ctx, file)
+		require.NoError(t, err)
+		require.True(t, affected > 0)
+
+		// binary and multibyte string should be restored byte by byte
+		var o dumpStruct
+		err = table.FindOne(
+			ctx,
+			actions.FindOne().
+				Where(
+					expr.Equal("UUID", data[0].UUID),
+				),
+		).Decode(&o)
+		require.NoError(t, err)
+		require.Equal(t, data[0].String, o.String)
+		require.Equal(t, data[0].Byte, o.Byte)
 	}
 }
 
 func newDumpStruct() (o dumpStruct) {
 	date := gofakeit.Date()
 	o.UUID = uuid.New()
-	o.String = gofakeit.Name()
+	o.String = gofakeit.Name() + " 'é' \\ 世界 \u200b"
+	o.Byte = []byte{0x00, 0xff, '\'', '\\', '\n', 0x1a}
 	o.Int = int(gofakeit.Int32())
 	o.Int64 = gofakeit.Int64()
 	o.Uint = uint(gofakeit.Uint32())
@@ -0,0 +1,138 @@
+package jsonb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/Oskang09/sqlike/reflext"
+)
+
+var defaultCodec = NewCodec(Config{SortMapKeys: true})
+
+// Config : the options of json encoding and decoding
+type Config struct {
+	// NameFunc is the naming strategy of the struct field which doesn't have a name on `sqlike` tag
+	NameFunc func(string) string
+
+	// OmitEmpty will skip the struct field with zero value
+	OmitEmpty bool
+
+	// EscapeHTML will escape <, > and & as \u003c, \u003e and \u0026 in string
+	EscapeHTML bool
+
+	// SortMapKeys will sort the keys of the map, so the output is deterministic
+	SortMapKeys bool
+
+	// TimeFormat is the layout of `time.Time`, default is `time.RFC3339Nano` in UTC,
+	// the time is formatted in its own location when it's set
+	TimeFormat string
+
+	// Indent will indent the output with the string if it's not empty
+	Indent string
+
+	// DecimalAsNumber will marshal the arbitrary precision number which implements `NumberMarshaler`,
+	// eg. `types.Decimal`, as json number instead of json string
+	DecimalAsNumber bool
+}
+
+// Codec : codec carries its own registry and options, so the type coders registered on the codec
+// won't affect the others
+type Codec struct {
+	cfg      Config
+	registry *Registry
+	mapper   reflext.StructMapper
+}
+
+// NewCodec :
+func NewCodec(cfg Config) *Codec {
+	c := &Codec{cfg: cfg}
+	c.mapper = reflext.DefaultMapper
+	if cfg.NameFunc != nil {
+		c.mapper = reflext.NewMapperFunc("sqlike", cfg.NameFunc)
+	}
+	c.registry = buildRegistry(c)
+	return c
+}
+
+// DefaultCodec : return the codec used by the package level functions
+func DefaultCodec() *Codec {
+	return defaultCodec
+}
+
+// Config :
+func (c *Codec) Config() Config {
+	return c.cfg
+}
+
+// Registry : return the registry of the codec, use it to register the custom type coders
+func (c *Codec) Registry() *Registry {
+	return c.registry
+}
+
+// Marshal :
+func (c *Codec) Marshal(src interface{}) (b []byte, err error) {
+	v := reflext.ValueOf(src)
+	if src == nil || !v.IsValid() || reflext.IsNull(v) {
+		b = []byte(null)
+		return
+	}
+
+	encoder, err := c.registry.LookupEncoder(v)
+	if err != nil {
+		return nil, err
+	}
+
+	w := NewWriter()
+	if err := encoder(w, v); err != nil {
+		return nil, err
+	}
+	b = w.Bytes()
+	if c.cfg.Indent != "" {
+		buf := new(bytes.Buffer)
+		if err := json.Indent(buf, b, "", c.cfg.Indent); err != nil {
+			return nil, err
+		}
+		b = buf.Bytes()
+	}
+	return
+}
+
+// Unmarshal :
+func (c *Codec) Unmarshal(data []byte, dst interface{}) error {
+	return unmarshal(c.registry, data, dst)
+}
+
+// UnmarshalValue :
+func (c *Codec) UnmarshalValue(data []byte, v reflect.Value) error {
+	return unmarshalValue(c.registry, data, v)
+}
+
+// NewEncoder :
+func (c *Codec) NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w), buf: NewWriter(), codec: c}
+}
+
+// NewDecoder :
+func (c *Codec) NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r), codec: c}
+}
+
+// options : return the options of the codec, the default options will be returned
+// if the coder is not created by codec
+func options(c *Codec) *Config {
+	if c == nil {
+		return &Config{SortMapKeys: true}
+	}
+	return &c.cfg
+}
+
+// mapper :
+func mapper(c *Codec) reflext.StructMapper {
+	if c == nil {
+		return reflext.DefaultMapper
+	}
+	return c.mapper
+}
@@ -0,0 +1,125 @@
+package jsonb
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Oskang09/sqlike/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec(t *testing.T) {
+	type item struct {
+		ID        int
+		Name      string
+		Tag       string `sqlike:"tag_name"`
+		CreatedAt time.Time
+	}
+
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Default codec", func(t *testing.T) {
+		b, err := DefaultCodec().Marshal(item{ID: 1, Name: "<a>", CreatedAt: now})
+		require.NoError(t, err)
+		require.Equal(t, `{"ID":1,"Name":"<a>","tag_name":"","CreatedAt":"2020-01-02T03:04:05Z"}`, string(b))
+
+		// the time is always converted to UTC by default
+		local := now.In(time.FixedZone("UTC+8", 8*60*60))
+		b, err = Marshal(item{ID: 1, CreatedAt: local})
+		require.NoError(t, err)
+		require.Equal(t, `{"ID":1,"Name":"","tag_name":"","CreatedAt":"2020-01-02T03:04:05Z"}`, string(b))
+
+		// the location is kept when the time format is set
+		b, err = NewCodec(Config{TimeFormat: time.RFC3339}).Marshal(item{CreatedAt: local})
+		require.NoError(t, err)
+		require.Equal(t, `{"ID":0,"Name":"","tag_name":"","CreatedAt":"2020-01-02T11:04:05+08:00"}`, string(b))
+	})
+
+	t.Run("Options", func(t *testing.T) {
+		c := NewCodec(Config{
+			NameFunc:   strings.ToLower,
+			OmitEmpty:  true,
+			EscapeHTML: true,
+			TimeFormat: "2006-01-02 15:04:05",
+		})
+		b, err := c.Marshal(item{ID: 1, Name: "<a> & b", CreatedAt: now})
+		require.NoError(t, err)
+		require.Equal(t, `{"id":1,"name":"\u003ca\u003e \u0026 b","createdat":"2020-01-02 03:04:05"}`, string(b))
+
+		var x item
+		require.NoError(t, c.Unmarshal(b, &x))
+		require.Equal(t, item{ID: 1, Name: "<a> & b", CreatedAt: now}, x)
+
+		// field name of default codec doesn't match
+		x = item{}
+		require.NoError(t, Unmarshal(b, &x))
+		require.Equal(t, item{}, x)
+	})
+
+	t.Run("Indent", func(t *testing.T) {
+		c := NewCodec(Config{Indent: "  ", SortMapKeys: true})
+		b, err := c.Marshal(map[string]int{"b": 2, "a": 1})
+		require.NoError(t, err)
+		require.Equal(t, "{\n  \"a\": 1,\n  \"b\": 2\n}", string(b))
+	})
+
+	t.Run("DecimalAsNumber", func(t *testing.T) {
+		src := map[string]interface{}{"amount": types.MustParseDecimal("10.50")}
+		b, err := Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"amount":"10.50"}`, string(b))
+
+		b, err = NewCodec(Config{DecimalAsNumber: true}).Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"amount":10.50}`, string(b))
+	})
+
+	t.Run("Isolated registry", func(t *testing.T) {
+		type flag bool
+
+		c1 := NewCodec(Config{})
+		c1.Registry().SetTypeCoder(reflect.TypeOf(flag(false)), func(w *Writer, v reflect.Value) error {
+			if v.Bool() {
+				w.WriteString(`"Y"`)
+			} else {
+				w.WriteString(`"N"`)
+			}
+			return nil
+		}, func(r *Reader, v reflect.Value) error {
+			str, err := r.ReadString()
+			v.SetBool(str == "Y")
+			return err
+		})
+		c2 := NewCodec(Config{})
+
+		b, err := c1.Marshal([]flag{true, false})
+		require.NoError(t, err)
+		require.Equal(t, `["Y","N"]`, string(b))
+
+		var flags []flag
+		require.NoError(t, c1.Unmarshal(b, &flags))
+		require.Equal(t, []flag{true, false}, flags)
+
+		b, err = c2.Marshal([]flag{true, false})
+		require.NoError(t, err)
+		require.Equal(t, `[true,false]`, string(b))
+
+		b, err = Marshal([]flag{true})
+		require.NoError(t, err)
+		require.Equal(t, `[true]`, string(b))
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		c := NewCodec(Config{NameFunc: strings.ToLower})
+		w := new(bytes.Buffer)
+		require.NoError(t, c.NewEncoder(w).Encode(item{ID: 2}))
+		require.Equal(t, "{\"id\":2,\"name\":\"\",\"tag_name\":\"\",\"createdat\":\"0001-01-01T00:00:00Z\"}\n", w.String())
+
+		var x item
+		require.NoError(t, c.NewDecoder(w).Decode(&x))
+		require.Equal(t, item{ID: 2}, x)
+	})
+}
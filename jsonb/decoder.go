@@ -25,6 +25,7 @@ var (
 // DefaultDecoder :
 type DefaultDecoder struct {
 	registry *Registry
+	codec    *Codec
 }
 
 // DecodeByte :
@@ -101,6 +102,12 @@ func (dec DefaultDecoder) DecodeTime(r *Reader, v reflect.Value) error {
 	}
 	str = string(b[1 : len(b)-1])
 	var x time.Time
+	if format := options(dec.codec).TimeFormat; format != "" {
+		if x, err = time.Parse(format, str); err == nil {
+			v.Set(reflect.ValueOf(x))
+			return nil
+		}
+	}
 	x, err = DecodeTime(str)
 	if err != nil {
 		return err
@@ -251,7 +258,7 @@ func (dec *DefaultDecoder) DecodePtr(r *Reader, v reflect.Value) error {
 
 // DecodeStruct :
 func (dec *DefaultDecoder) DecodeStruct(r *Reader, v reflect.Value) error {
-	mapper := reflext.DefaultMapper
+	mapper := mapper(dec.codec)
 	if r.IsNull() {
 		v.Set(reflect.Zero(v.Type()))
 		return r.skipNull()
@@ -0,0 +1,203 @@
+package jsonb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+)
+
+// PatchOp :
+type PatchOp int
+
+// patch operations :
+const (
+	// PatchSet will add the value which is not exists, same as mysql `JSON_SET`
+	PatchSet PatchOp = iota + 1
+	// PatchReplace will replace the existing value, same as mysql `JSON_REPLACE`
+	PatchReplace
+	// PatchRemove will remove the existing value, same as mysql `JSON_REMOVE`
+	PatchRemove
+)
+
+func (op PatchOp) String() string {
+	switch op {
+	case PatchSet:
+		return "set"
+	case PatchReplace:
+		return "replace"
+	case PatchRemove:
+		return "remove"
+	default:
+		return "unknown"
+	}
+}
+
+// Patch : the change of the json document on the path
+type Patch struct {
+	Op    PatchOp
+	Path  string
+	Value json.RawMessage
+}
+
+// Diff : return the minimal patches which will turn the old json document into the new one,
+// the patches should be applied in order of remove, replace and set. The whole document
+// will be replaced on path `$` if both of them are not object or array of the same type
+func Diff(old, new []byte) ([]Patch, error) {
+	var patches []Patch
+	if err := diffValue("$", old, new, &patches); err != nil {
+		return nil, err
+	}
+	return patches, nil
+}
+
+func diffValue(path string, old, new []byte, patches *[]Patch) error {
+	a, err := compactJSON(old)
+	if err != nil {
+		return err
+	}
+	b, err := compactJSON(new)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(a, b) {
+		return nil
+	}
+
+	typ := NewReader(a).peekType()
+	if typ != NewReader(b).peekType() {
+		*patches = append(*patches, Patch{Op: PatchReplace, Path: path, Value: b})
+		return nil
+	}
+
+	switch typ {
+	case jsonObject:
+		return diffObject(path, a, b, patches)
+	case jsonArray:
+		return diffArray(path, a, b, patches)
+	}
+	*patches = append(*patches, Patch{Op: PatchReplace, Path: path, Value: b})
+	return nil
+}
+
+func diffObject(path string, old, new []byte, patches *[]Patch) error {
+	oldKeys, oldValues, err := readMembers(old)
+	if err != nil {
+		return err
+	}
+	newKeys, newValues, err := readMembers(new)
+	if err != nil {
+		return err
+	}
+
+	for _, k := range oldKeys {
+		if _, ok := newValues[k]; !ok {
+			*patches = append(*patches, Patch{Op: PatchRemove, Path: memberPath(path, k)})
+		}
+	}
+	for _, k := range newKeys {
+		v, ok := oldValues[k]
+		if !ok {
+			*patches = append(*patches, Patch{Op: PatchSet, Path: memberPath(path, k), Value: newValues[k]})
+			continue
+		}
+		if err := diffValue(memberPath(path, k), v, newValues[k], patches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func diffArray(path string, old, new []byte, patches *[]Patch) error {
+	oldElems, err := readElements(old)
+	if err != nil {
+		return err
+	}
+	newElems, err := readElements(new)
+	if err != nil {
+		return err
+	}
+
+	// remove from the tail, so the index of the remaining elements won't be shifted
+	for i := len(oldElems) - 1; i >= len(newElems); i-- {
+		*patches = append(*patches, Patch{Op: PatchRemove, Path: path + "[" + strconv.Itoa(i) + "]"})
+	}
+	for i, v := range newElems {
+		elemPath := path + "[" + strconv.Itoa(i) + "]"
+		if i >= len(oldElems) {
+			*patches = append(*patches, Patch{Op: PatchSet, Path: elemPath, Value: v})
+			continue
+		}
+		if err := diffValue(elemPath, oldElems[i], v, patches); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readMembers(b []byte) ([]string, map[string][]byte, error) {
+	keys := make([]string, 0)
+	values := make(map[string][]byte)
+	if err := NewReader(b).ReadObject(func(it *Reader, k string) error {
+		if _, ok := values[k]; !ok {
+			keys = append(keys, k)
+		}
+		values[k] = it.Bytes()
+		return nil
+	}); err != nil {
+		return nil, nil, err
+	}
+	return keys, values, nil
+}
+
+func readElements(b []byte) ([][]byte, error) {
+	elems := make([][]byte, 0)
+	if err := NewReader(b).ReadArray(func(it *Reader) error {
+		elems = append(elems, it.Bytes())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return elems, nil
+}
+
+// memberPath : the member name will be quoted if it's not an identifier
+func memberPath(path, k string) string {
+	if isIdentifier(k) {
+		return path + "." + k
+	}
+	w := NewWriter()
+	w.WriteString(path + `."`)
+	escapeString(w, k)
+	w.WriteByte('"')
+	return w.String()
+}
+
+func isIdentifier(k string) bool {
+	if k == "" {
+		return false
+	}
+	for i, c := range k {
+		switch {
+		case c == '_' || c == '$':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9':
+			if i == 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func compactJSON(b []byte) ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(null), nil
+	}
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, b); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,67 @@
+package jsonb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	t.Run("Equal", func(t *testing.T) {
+		patches, err := Diff([]byte(`{"a": 1, "b": [1, 2]}`), []byte(`{"a":1,"b":[1,2]}`))
+		require.NoError(t, err)
+		require.Empty(t, patches)
+	})
+
+	t.Run("Object", func(t *testing.T) {
+		patches, err := Diff(
+			[]byte(`{"a":1,"b":{"c":"x","d":true},"e":null,"1st":0}`),
+			[]byte(`{"a":2,"b":{"c":"x","f":[1]},"g b":"new","1st":0}`),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []Patch{
+			{Op: PatchRemove, Path: `$.e`},
+			{Op: PatchReplace, Path: `$.a`, Value: json.RawMessage(`2`)},
+			{Op: PatchRemove, Path: `$.b.d`},
+			{Op: PatchSet, Path: `$.b.f`, Value: json.RawMessage(`[1]`)},
+			{Op: PatchSet, Path: `$."g b"`, Value: json.RawMessage(`"new"`)},
+		}, patches)
+	})
+
+	t.Run("Array", func(t *testing.T) {
+		patches, err := Diff([]byte(`{"a":[1,{"b":1},3,4]}`), []byte(`{"a":[1,{"b":2}]}`))
+		require.NoError(t, err)
+		require.Equal(t, []Patch{
+			{Op: PatchRemove, Path: `$.a[3]`},
+			{Op: PatchRemove, Path: `$.a[2]`},
+			{Op: PatchReplace, Path: `$.a[1].b`, Value: json.RawMessage(`2`)},
+		}, patches)
+
+		patches, err = Diff([]byte(`[1]`), []byte(`[1,"a",{}]`))
+		require.NoError(t, err)
+		require.Equal(t, []Patch{
+			{Op: PatchSet, Path: `$[1]`, Value: json.RawMessage(`"a"`)},
+			{Op: PatchSet, Path: `$[2]`, Value: json.RawMessage(`{}`)},
+		}, patches)
+	})
+
+	t.Run("Replace document", func(t *testing.T) {
+		patches, err := Diff([]byte(`null`), []byte(`{"a":1}`))
+		require.NoError(t, err)
+		require.Equal(t, []Patch{
+			{Op: PatchReplace, Path: `$`, Value: json.RawMessage(`{"a":1}`)},
+		}, patches)
+
+		patches, err = Diff(nil, []byte(`"abc"`))
+		require.NoError(t, err)
+		require.Equal(t, []Patch{
+			{Op: PatchReplace, Path: `$`, Value: json.RawMessage(`"abc"`)},
+		}, patches)
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		_, err := Diff([]byte(`{"a":`), []byte(`{}`))
+		require.Error(t, err)
+	})
+}
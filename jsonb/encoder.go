@@ -17,6 +17,7 @@ import (
 // DefaultEncoder :
 type DefaultEncoder struct {
 	registry *Registry
+	codec    *Codec
 }
 
 // EncodeByte :
@@ -56,10 +57,15 @@ func (enc DefaultEncoder) EncodeJSONRaw(w *Writer, v reflect.Value) error {
 	return nil
 }
 
-// EncodeTime :
+// EncodeTime : the time is converted to UTC and formatted as `time.RFC3339Nano` by default,
+// it keeps its own location only when `Config.TimeFormat` is set
 func (enc DefaultEncoder) EncodeTime(w *Writer, v reflect.Value) error {
-	var temp [20]byte
+	var temp [40]byte
 	x := v.Interface().(time.Time)
+	if format := options(enc.codec).TimeFormat; format != "" {
+		w.Write(x.AppendFormat(temp[:0], `"`+format+`"`))
+		return nil
+	}
 	w.Write(x.UTC().AppendFormat(temp[:0], `"`+time.RFC3339Nano+`"`))
 	return nil
 }
@@ -67,7 +73,11 @@ func (enc DefaultEncoder) EncodeTime(w *Writer, v reflect.Value) error {
 // EncodeString :
 func (enc DefaultEncoder) EncodeString(w *Writer, v reflect.Value) error {
 	w.WriteRune('"')
-	escapeString(w, v.String())
+	if options(enc.codec).EscapeHTML {
+		escapeHTMLString(w, v.String())
+	} else {
+		escapeString(w, v.String())
+	}
 	w.WriteRune('"')
 	return nil
 }
@@ -122,15 +132,21 @@ func (enc *DefaultEncoder) EncodePtr(w *Writer, v reflect.Value) error {
 // EncodeStruct :
 func (enc *DefaultEncoder) EncodeStruct(w *Writer, v reflect.Value) error {
 	w.WriteRune('{')
-	mapper := reflext.DefaultMapper
+	mapper := mapper(enc.codec)
+	omitEmpty := options(enc.codec).OmitEmpty
 	cdc := mapper.CodecByType(v.Type())
-	for i, sf := range cdc.Properties() {
+	i := 0
+	for _, sf := range cdc.Properties() {
+		fv := mapper.FieldByIndexesReadOnly(v, sf.Index())
+		if omitEmpty && reflext.IsZero(fv) {
+			continue
+		}
 		if i > 0 {
 			w.WriteRune(',')
 		}
+		i++
 		w.WriteString(strconv.Quote(sf.Name()))
 		w.WriteRune(':')
-		fv := mapper.FieldByIndexesReadOnly(v, sf.Index())
 		encoder, err := enc.registry.LookupEncoder(fv)
 		if err != nil {
 			return err
@@ -199,6 +215,7 @@ func (enc *DefaultEncoder) EncodeMap(w *Writer, v reflect.Value) error {
 	}
 
 	keys := v.MapKeys()
+	sortKeys := options(enc.codec).SortMapKeys
 	var encode ValueEncoder
 	if k.Implements(textMarshaler) {
 		encode = func(wr *Writer, vi reflect.Value) error {
@@ -216,14 +233,18 @@ func (enc *DefaultEncoder) EncodeMap(w *Writer, v reflect.Value) error {
 	} else {
 		switch k.Kind() {
 		case reflect.String:
-			sort.SliceStable(keys, func(i, j int) bool {
-				return keys[i].String() < keys[j].String()
-			})
+			if sortKeys {
+				sort.SliceStable(keys, func(i, j int) bool {
+					return keys[i].String() < keys[j].String()
+				})
+			}
 			encode = enc.registry.kindEncoders[reflect.String]
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			sort.SliceStable(keys, func(i, j int) bool {
-				return keys[i].Int() < keys[j].Int()
-			})
+			if sortKeys {
+				sort.SliceStable(keys, func(i, j int) bool {
+					return keys[i].Int() < keys[j].Int()
+				})
+			}
 			encode = func(wr *Writer, vi reflect.Value) error {
 				wr.WriteByte('"')
 				wr.WriteString(strconv.FormatInt(vi.Int(), 10))
@@ -231,9 +252,11 @@ func (enc *DefaultEncoder) EncodeMap(w *Writer, v reflect.Value) error {
 				return nil
 			}
 		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			sort.SliceStable(keys, func(i, j int) bool {
-				return keys[i].Uint() < keys[j].Uint()
-			})
+			if sortKeys {
+				sort.SliceStable(keys, func(i, j int) bool {
+					return keys[i].Uint() < keys[j].Uint()
+				})
+			}
 			encode = func(wr *Writer, vi reflect.Value) error {
 				wr.WriteByte('"')
 				wr.WriteString(strconv.FormatUint(vi.Uint(), 10))
@@ -246,8 +269,6 @@ func (enc *DefaultEncoder) EncodeMap(w *Writer, v reflect.Value) error {
 
 	}
 
-	// Question: do we really need to sort the key before encode?
-
 	length := len(keys)
 	for i := 0; i < length; i++ {
 		if i > 0 {
@@ -4,8 +4,6 @@ import (
 	"encoding"
 	"encoding/json"
 	"reflect"
-
-	"github.com/Oskang09/sqlike/reflext"
 )
 
 // Marshaler :
@@ -13,25 +11,16 @@ type Marshaler interface {
 	MarshalJSONB() ([]byte, error)
 }
 
+// NumberMarshaler : is the arbitrary precision number, which is marshalled as json string by `MarshalJSONB`
+// because most of the json parsers decode number as float, unless `DecimalAsNumber` is enabled
+type NumberMarshaler interface {
+	Marshaler
+	MarshalJSONBNumber() ([]byte, error)
+}
+
 // Marshal :
 func Marshal(src interface{}) (b []byte, err error) {
-	v := reflext.ValueOf(src)
-	if src == nil || !v.IsValid() || reflext.IsNull(v) {
-		b = []byte(null)
-		return
-	}
-
-	encoder, err := registry.LookupEncoder(v)
-	if err != nil {
-		return nil, err
-	}
-
-	w := NewWriter()
-	if err := encoder(w, v); err != nil {
-		return nil, err
-	}
-	b = w.Bytes()
-	return
+	return defaultCodec.Marshal(src)
 }
 
 // marshalerEncoder
@@ -47,6 +36,18 @@ func marshalerEncoder() ValueEncoder {
 	}
 }
 
+func numberMarshalerEncoder() ValueEncoder {
+	return func(w *Writer, v reflect.Value) error {
+		x := v.Interface().(NumberMarshaler)
+		b, err := x.MarshalJSONBNumber()
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+		return nil
+	}
+}
+
 func jsonMarshalerEncoder() ValueEncoder {
 	return func(w *Writer, v reflect.Value) error {
 		x := v.Interface().(json.Marshaler)
@@ -0,0 +1,397 @@
+package jsonb
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidPath :
+type ErrInvalidPath struct {
+	Path    string
+	message string
+}
+
+func (e ErrInvalidPath) Error() string {
+	return "jsonb: invalid json path " + strconv.Quote(e.Path) + ": " + e.message
+}
+
+type legType int
+
+const (
+	legMember legType = iota + 1
+	legMemberWildcard
+	legIndex
+	legIndexWildcard
+	legDoubleWildcard
+)
+
+// arrayIndex : the index of array, it's counting from the end when `last` is true, eg. [last-1]
+type arrayIndex struct {
+	last bool
+	n    int
+}
+
+func (idx arrayIndex) resolve(length int) int {
+	if idx.last {
+		return length - 1 - idx.n
+	}
+	return idx.n
+}
+
+type pathLeg struct {
+	typ  legType
+	key  string
+	from arrayIndex
+	to   arrayIndex
+	rng  bool
+}
+
+// Path : the json path which is compatible with mysql, such as `$.a.b[0]`, `$**.x`, `$.*`, `$[*]`,
+// `$[last]`, `$[last-1]` and `$[1 to 3]`
+type Path struct {
+	raw  string
+	legs []pathLeg
+
+	// the path may match more than one value
+	wildcard bool
+}
+
+// ParsePath :
+func ParsePath(path string) (*Path, error) {
+	p := &Path{raw: path}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// MustParsePath :
+func MustParsePath(path string) *Path {
+	p, err := ParsePath(path)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// String :
+func (p *Path) String() string {
+	return p.raw
+}
+
+// Match : return the compacted raw values matched by the path without decoding them, the values
+// matched by `**` are ordered from the ancestor to the descendant
+func (p *Path) Match(data []byte) ([][]byte, error) {
+	result := make([][]byte, 0)
+	if err := evalPath(data, p.legs, func(b []byte) error {
+		buf := new(bytes.Buffer)
+		if err := json.Compact(buf, b); err != nil {
+			return err
+		}
+		result = append(result, buf.Bytes())
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Extract : same as mysql `JSON_EXTRACT`, it returns nil if nothing matched, the matched values
+// will be wrapped within array if the path contains wildcard or range
+func (p *Path) Extract(data []byte) ([]byte, error) {
+	return extract(data, []*Path{p})
+}
+
+// Extract : same as `expr.JSON_EXTRACT`, extract the raw values of the current json value
+// by paths, it returns nil if nothing matched
+func (r *Reader) Extract(path string, otherPaths ...string) ([]byte, error) {
+	paths := make([]*Path, 0, len(otherPaths)+1)
+	for _, p := range append([]string{path}, otherPaths...) {
+		x, err := ParsePath(p)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, x)
+	}
+	b, err := r.ReadBytes()
+	if err != nil {
+		return nil, err
+	}
+	return extract(b, paths)
+}
+
+func extract(data []byte, paths []*Path) ([]byte, error) {
+	var (
+		values   [][]byte
+		wildcard = len(paths) > 1
+	)
+	for _, p := range paths {
+		matches, err := p.Match(data)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, matches...)
+		wildcard = wildcard || p.wildcard
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	if !wildcard {
+		return values[0], nil
+	}
+
+	w := NewWriter()
+	w.WriteByte('[')
+	for i, v := range values {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.Write(v)
+	}
+	w.WriteByte(']')
+	return w.Bytes(), nil
+}
+
+func evalPath(b []byte, legs []pathLeg, cb func([]byte) error) error {
+	if len(legs) == 0 {
+		return cb(b)
+	}
+
+	leg, next := legs[0], legs[1:]
+	typ := NewReader(b).peekType()
+	switch leg.typ {
+	case legMember, legMemberWildcard:
+		if typ != jsonObject {
+			return nil
+		}
+		return NewReader(b).ReadObject(func(it *Reader, k string) error {
+			if leg.typ == legMember && k != leg.key {
+				return nil
+			}
+			return evalPath(it.Bytes(), next, cb)
+		})
+
+	case legIndexWildcard:
+		if typ != jsonArray {
+			return nil
+		}
+		return NewReader(b).ReadArray(func(it *Reader) error {
+			return evalPath(it.Bytes(), next, cb)
+		})
+
+	case legIndex:
+		// non array value will be treated as the array with single element
+		elems := [][]byte{b}
+		if typ == jsonArray {
+			elems = elems[:0]
+			if err := NewReader(b).ReadArray(func(it *Reader) error {
+				elems = append(elems, it.Bytes())
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		from, to := leg.from.resolve(len(elems)), leg.to.resolve(len(elems))
+		if from < 0 {
+			from = 0
+		}
+		if to >= len(elems) {
+			to = len(elems) - 1
+		}
+		for i := from; i <= to; i++ {
+			if err := evalPath(elems[i], next, cb); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case legDoubleWildcard:
+		// match the value itself and all of its descendants
+		if err := evalPath(b, next, cb); err != nil {
+			return err
+		}
+		switch typ {
+		case jsonObject:
+			return NewReader(b).ReadObject(func(it *Reader, _ string) error {
+				return evalPath(it.Bytes(), legs, cb)
+			})
+		case jsonArray:
+			return NewReader(b).ReadArray(func(it *Reader) error {
+				return evalPath(it.Bytes(), legs, cb)
+			})
+		}
+	}
+	return nil
+}
+
+func (p *Path) parse() error {
+	s := strings.TrimSpace(p.raw)
+	if s == "" || s[0] != '$' {
+		return p.error("path must start with $")
+	}
+
+	i := 1
+	for {
+		i = skipPathSpace(s, i)
+		if i >= len(s) {
+			break
+		}
+
+		var (
+			leg pathLeg
+			err error
+		)
+		switch s[i] {
+		case '.':
+			leg, i, err = p.parseMember(s, i+1)
+		case '[':
+			leg, i, err = p.parseIndex(s, i+1)
+		case '*':
+			if i+1 >= len(s) || s[i+1] != '*' {
+				return p.error("unexpected * at position " + strconv.Itoa(i))
+			}
+			if n := len(p.legs); n > 0 && p.legs[n-1].typ == legDoubleWildcard {
+				return p.error("** cannot be followed by **")
+			}
+			leg, i = pathLeg{typ: legDoubleWildcard}, i+2
+		default:
+			return p.error("unexpected " + string(s[i]) + " at position " + strconv.Itoa(i))
+		}
+		if err != nil {
+			return err
+		}
+
+		switch leg.typ {
+		case legMemberWildcard, legIndexWildcard, legDoubleWildcard:
+			p.wildcard = true
+		case legIndex:
+			p.wildcard = p.wildcard || leg.rng
+		}
+		p.legs = append(p.legs, leg)
+	}
+
+	if n := len(p.legs); n > 0 && p.legs[n-1].typ == legDoubleWildcard {
+		return p.error("path cannot end with **")
+	}
+	return nil
+}
+
+func (p *Path) parseMember(s string, i int) (pathLeg, int, error) {
+	i = skipPathSpace(s, i)
+	if i >= len(s) {
+		return pathLeg{}, i, p.error("missing member name")
+	}
+
+	switch s[i] {
+	case '*':
+		return pathLeg{typ: legMemberWildcard}, i + 1, nil
+
+	case '"':
+		j := i + 1
+		for ; j < len(s); j++ {
+			if s[j] == '\\' {
+				j++
+				continue
+			}
+			if s[j] == '"' {
+				break
+			}
+		}
+		if j >= len(s) {
+			return pathLeg{}, j, p.error("unterminated quoted member name")
+		}
+		var key string
+		if err := json.Unmarshal([]byte(s[i:j+1]), &key); err != nil {
+			return pathLeg{}, j, p.error("invalid quoted member name " + s[i:j+1])
+		}
+		return pathLeg{typ: legMember, key: key}, j + 1, nil
+	}
+
+	j := i
+	for ; j < len(s); j++ {
+		if c := s[j]; c == '.' || c == '[' || c == '*' || c == '"' || whiteSpaceMap[c] {
+			break
+		}
+	}
+	if j == i {
+		return pathLeg{}, j, p.error("missing member name")
+	}
+	return pathLeg{typ: legMember, key: s[i:j]}, j, nil
+}
+
+func (p *Path) parseIndex(s string, i int) (pathLeg, int, error) {
+	i = skipPathSpace(s, i)
+	if i < len(s) && s[i] == '*' {
+		i = skipPathSpace(s, i+1)
+		if i >= len(s) || s[i] != ']' {
+			return pathLeg{}, i, p.error("expect ] after *")
+		}
+		return pathLeg{typ: legIndexWildcard}, i + 1, nil
+	}
+
+	from, i, err := p.parseArrayIndex(s, i)
+	if err != nil {
+		return pathLeg{}, i, err
+	}
+	leg := pathLeg{typ: legIndex, from: from, to: from}
+
+	i = skipPathSpace(s, i)
+	if strings.HasPrefix(s[i:], "to") {
+		leg.rng = true
+		leg.to, i, err = p.parseArrayIndex(s, i+2)
+		if err != nil {
+			return pathLeg{}, i, err
+		}
+		// the range of mixed index, eg. [1 to last], can only be validated on evaluation
+		if (!from.last && !leg.to.last && from.n > leg.to.n) ||
+			(from.last && leg.to.last && from.n < leg.to.n) {
+			return pathLeg{}, i, p.error("invalid array range")
+		}
+		i = skipPathSpace(s, i)
+	}
+
+	if i >= len(s) || s[i] != ']' {
+		return pathLeg{}, i, p.error("expect ] for array index")
+	}
+	return leg, i + 1, nil
+}
+
+func (p *Path) parseArrayIndex(s string, i int) (arrayIndex, int, error) {
+	var idx arrayIndex
+	i = skipPathSpace(s, i)
+	if strings.HasPrefix(s[i:], "last") {
+		idx.last = true
+		i = skipPathSpace(s, i+4)
+		if i >= len(s) || s[i] != '-' {
+			return idx, i, nil
+		}
+		i = skipPathSpace(s, i+1)
+	}
+
+	j := i
+	for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+		j++
+	}
+	if j == i {
+		return idx, j, p.error("invalid array index at position " + strconv.Itoa(i))
+	}
+	n, err := strconv.Atoi(s[i:j])
+	if err != nil {
+		return idx, j, p.error("invalid array index " + s[i:j])
+	}
+	idx.n = n
+	return idx, j, nil
+}
+
+func (p *Path) error(msg string) error {
+	return ErrInvalidPath{Path: p.raw, message: msg}
+}
+
+func skipPathSpace(s string, i int) int {
+	for i < len(s) && whiteSpaceMap[s[i]] {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,123 @@
+package jsonb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePath(t *testing.T) {
+	for _, p := range []string{
+		`$`,
+		`$.a`,
+		`$.a.b[0]`,
+		` $ . a [ 1 ] `,
+		`$."a b".c`,
+		`$.*`,
+		`$[*]`,
+		`$**.x`,
+		`$.a**[0]`,
+		`$[last]`,
+		`$[last-1]`,
+		`$[0 to 2]`,
+		`$[last-2 to last]`,
+	} {
+		_, err := ParsePath(p)
+		require.NoError(t, err, p)
+	}
+
+	for _, p := range []string{
+		``,
+		`a.b`,
+		`$.`,
+		`$a`,
+		`$[`,
+		`$[a]`,
+		`$[1`,
+		`$[*`,
+		`$**`,
+		`$***.a`,
+		`$.a*`,
+		`$."a`,
+		`$[2 to 1]`,
+		`$[last to last-1]`,
+	} {
+		_, err := ParsePath(p)
+		require.Error(t, err, p)
+	}
+
+	require.Panics(t, func() {
+		MustParsePath(`$.`)
+	})
+	require.Equal(t, `$.a[0]`, MustParsePath(`$.a[0]`).String())
+}
+
+func TestPathExtract(t *testing.T) {
+	doc := []byte(`{
+		"a": {"b": [10, {"x": "y]"}, 30], "c": true},
+		"a b": {"c": null},
+		"x": 1,
+		"list": [{"x": 2}, {"x": {"x": 3}}]
+	}`)
+
+	for _, tc := range []struct {
+		path   string
+		result string
+	}{
+		{`$`, `{"a":{"b":[10,{"x":"y]"},30],"c":true},"a b":{"c":null},"x":1,"list":[{"x":2},{"x":{"x":3}}]}`},
+		{`$.a.b[0]`, `10`},
+		{`$.a.b[1].x`, `"y]"`},
+		{`$.a.b[last]`, `30`},
+		{`$.a.b[last-1]`, `{"x":"y]"}`},
+		{`$.a.b[0 to 1]`, `[10,{"x":"y]"}]`},
+		{`$.a.b[1 to last]`, `[{"x":"y]"},30]`},
+		{`$.a.b[*]`, `[10,{"x":"y]"},30]`},
+		{`$.a.*`, `[[10,{"x":"y]"},30],true]`},
+		{`$."a b".c`, `null`},
+		{`$.x[0]`, `1`},
+		{`$.list[*].x`, `[2,{"x":3}]`},
+		{`$**.x`, `[1,"y]",2,{"x":3},3]`},
+		{`$.list**.x`, `[2,{"x":3},3]`},
+		{`$.a.c`, `true`},
+		{`$.a.b[3]`, ``},
+		{`$.x[1]`, ``},
+		{`$.unknown`, ``},
+		{`$.a.c.d`, ``},
+		{`$**.unknown`, ``},
+	} {
+		b, err := MustParsePath(tc.path).Extract(doc)
+		require.NoError(t, err, tc.path)
+		require.Equal(t, tc.result, string(b), tc.path)
+	}
+
+	t.Run("Match", func(t *testing.T) {
+		values, err := MustParsePath(`$.list[*].x`).Match(doc)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{[]byte(`2`), []byte(`{"x":3}`)}, values)
+
+		values, err = MustParsePath(`$.unknown`).Match(doc)
+		require.NoError(t, err)
+		require.Empty(t, values)
+	})
+
+	t.Run("Extract with Reader", func(t *testing.T) {
+		r := NewReader([]byte(`[{"a": 1, "b": [1, 2]}, {"a": 3}]`))
+		var result []string
+		require.NoError(t, r.ReadArray(func(it *Reader) error {
+			b, err := it.Extract(`$.a`, `$.b[last]`)
+			if err != nil {
+				return err
+			}
+			result = append(result, string(b))
+			return nil
+		}))
+		require.Equal(t, []string{`[1,2]`, `[3]`}, result)
+
+		b, err := NewReader([]byte(`{"a": 1}`)).Extract(`$.b`)
+		require.NoError(t, err)
+		require.Nil(t, b)
+
+		_, err = NewReader([]byte(`{"a": 1}`)).Extract(`$.`)
+		require.Error(t, err)
+	})
+}
@@ -94,9 +94,11 @@ func (r *Reader) skipArray() {
 	for i := r.pos; i < r.len; i++ {
 		switch r.b[i] {
 		case '"': // If inside string, skip it
-			// iter.head = i + 1
-			// iter.skipString()
-			// i = iter.head - 1 // it will be i++ soon
+			r.pos = i
+			if err := r.skipString(); err != nil {
+				return
+			}
+			i = r.pos - 1 // it will be i++ soon
 		case '[': // If open symbol, increase level
 			level++
 		case ']': // If close symbol, increase level
@@ -28,3 +28,46 @@ func TestReader(t *testing.T) {
 		require.Equal(t, nil, v)
 	}
 }
+
+func TestSkipArray(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		rest string
+	}{
+		{`[]`, ``},
+		{`[1,2,3],"x"`, `,"x"`},
+		{`["]"],1`, `,1`},
+		{`["[", "]]", "a\"]"] ,true`, ` ,true`},
+		{`[[1,["]"]],{"k":"]"}]}`, `}`},
+	} {
+		r := NewReader([]byte(tc.data))
+		r.skipArray()
+		require.Equal(t, tc.rest, string(r.b[r.pos:]), tc.data)
+	}
+}
+
+func TestSkipObject(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		rest string
+	}{
+		{`{}`, ``},
+		{`{"a":1}`, ``},
+		{`{"a":{"b":"}"}}`, ``},
+		{`{"a":["}",{}],"b":null},2`, `,2`},
+	} {
+		r := NewReader([]byte(tc.data))
+		require.NoError(t, r.skipObject(), tc.data)
+		require.Equal(t, tc.rest, string(r.b[r.pos:]), tc.data)
+	}
+
+	for _, data := range []string{
+		`{"a":1`,
+		`{"a":1]`,
+		`{"a" 1}`,
+		`{1:1}`,
+	} {
+		r := NewReader([]byte(data))
+		require.Error(t, r.skipObject(), data)
+	}
+}
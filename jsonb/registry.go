@@ -13,8 +13,6 @@ import (
 )
 
 var (
-	registry = buildDefaultRegistry()
-
 	jsonbUnmarshaler = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
 	jsonUnmarshaler  = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
 	textUnmarshaler  = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
@@ -30,6 +28,7 @@ type ValueEncoder func(*Writer, reflect.Value) error
 // Registry :
 type Registry struct {
 	mutex        *sync.Mutex
+	number       bool
 	typeEncoders map[reflect.Type]ValueEncoder
 	typeDecoders map[reflect.Type]ValueDecoder
 	kindEncoders map[reflect.Kind]ValueEncoder
@@ -37,9 +36,14 @@ type Registry struct {
 }
 
 func buildDefaultRegistry() *Registry {
+	return buildRegistry(nil)
+}
+
+func buildRegistry(c *Codec) *Registry {
 	rg := NewRegistry()
-	enc := DefaultEncoder{rg}
-	dec := DefaultDecoder{rg}
+	rg.number = options(c).DecimalAsNumber
+	enc := DefaultEncoder{registry: rg, codec: c}
+	dec := DefaultDecoder{registry: rg, codec: c}
 	rg.SetTypeCoder(reflect.TypeOf([]byte{}), enc.EncodeByte, dec.DecodeByte)
 	rg.SetTypeCoder(reflect.TypeOf(language.Tag{}), enc.EncodeStringer, dec.DecodeLanguage)
 	rg.SetTypeCoder(reflect.TypeOf(currency.Unit{}), enc.EncodeStringer, dec.DecodeCurrency)
@@ -123,10 +127,20 @@ func (r *Registry) LookupEncoder(v reflect.Value) (ValueEncoder, error) {
 	}
 
 	it := v.Interface()
+	if _, ok := it.(NumberMarshaler); ok && r.number {
+		return numberMarshalerEncoder(), nil
+	}
 	if _, ok := it.(Marshaler); ok {
 		return marshalerEncoder(), nil
 	}
 
+	// same as decoder, the type encoder has higher priority than `json.Marshaler`
+	t := v.Type()
+	enc, ok = r.typeEncoders[t]
+	if ok {
+		return enc, nil
+	}
+
 	if _, ok := it.(json.Marshaler); ok {
 		return jsonMarshalerEncoder(), nil
 	}
@@ -135,12 +149,6 @@ func (r *Registry) LookupEncoder(v reflect.Value) (ValueEncoder, error) {
 		return textMarshalerEncoder(), nil
 	}
 
-	t := v.Type()
-	enc, ok = r.typeEncoders[t]
-	if ok {
-		return enc, nil
-	}
-
 	enc, ok = r.kindEncoders[t.Kind()]
 	if ok {
 		return enc, nil
@@ -0,0 +1,712 @@
+package jsonb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	schemas       sync.Map // registered schemas, key by name
+	inlineSchemas sync.Map // compiled inline schemas, key by the schema itself
+)
+
+// RegisterSchema : register the json schema with name, so it can be referenced by `json_schema` tag,
+// eg. `sqlike:",json_schema=profile"`
+func RegisterSchema(name string, schema []byte) error {
+	s, err := CompileSchema(schema)
+	if err != nil {
+		return err
+	}
+	schemas.Store(name, s)
+	return nil
+}
+
+// MustRegisterSchema :
+func MustRegisterSchema(name string, schema []byte) {
+	if err := RegisterSchema(name, schema); err != nil {
+		panic(err)
+	}
+}
+
+// SchemaOf : return the schema of `json_schema` tag, the tag can be the name of registered schema
+// or the inline schema
+func SchemaOf(tag string) (*Schema, error) {
+	tag = strings.TrimSpace(tag)
+	if strings.HasPrefix(tag, "{") {
+		if s, ok := inlineSchemas.Load(tag); ok {
+			return s.(*Schema), nil
+		}
+		s, err := CompileSchema([]byte(tag))
+		if err != nil {
+			return nil, err
+		}
+		inlineSchemas.Store(tag, s)
+		return s, nil
+	}
+	if s, ok := schemas.Load(tag); ok {
+		return s.(*Schema), nil
+	}
+	return nil, fmt.Errorf("jsonb: json schema %q is not registered", tag)
+}
+
+// SchemaError : the json document doesn't match the schema
+type SchemaError struct {
+	// json path of the invalid value, eg. $.tags[0]
+	Path    string
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	return "jsonb: invalid json on " + e.Path + ": " + e.Message
+}
+
+// Schema : the compiled json schema, it supports the keywords of draft 4 which are supported by
+// mysql `JSON_SCHEMA_VALID`, the `format` keyword and remote `$ref` are not supported
+type Schema struct {
+	raw  []byte
+	root *schemaNode
+}
+
+// CompileSchema :
+func CompileSchema(schema []byte) (*Schema, error) {
+	buf := new(bytes.Buffer)
+	if err := json.Compact(buf, schema); err != nil {
+		return nil, err
+	}
+	doc, err := decodeJSON(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	c := &schemaCompiler{doc: doc, nodes: make(map[string]*schemaNode)}
+	root, err := c.compile(doc, "#")
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{raw: buf.Bytes(), root: root}, nil
+}
+
+// String : return the compacted schema
+func (s *Schema) String() string {
+	return string(s.raw)
+}
+
+// Validate : validate the json document, it returns `*SchemaError` if the document doesn't match
+func (s *Schema) Validate(data []byte) error {
+	v, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+	return s.root.validate(v, "$")
+}
+
+type schemaNode struct {
+	ref     string
+	refNode *schemaNode
+
+	types []string
+	enum  []interface{}
+
+	multipleOf       *big.Rat
+	maximum          *big.Rat
+	minimum          *big.Rat
+	exclusiveMaximum bool
+	exclusiveMinimum bool
+
+	maxLength int
+	minLength int
+	pattern   *regexp.Regexp
+
+	items           *schemaNode
+	tupleItems      []*schemaNode
+	additionalItems *schemaNode
+	noMoreItems     bool
+	maxItems        int
+	minItems        int
+	uniqueItems     bool
+
+	maxProperties        int
+	minProperties        int
+	required             []string
+	properties           map[string]*schemaNode
+	patternProperties    map[*regexp.Regexp]*schemaNode
+	additionalProperties *schemaNode
+	noMoreProperties     bool
+	dependencies         map[string]interface{} // []string or *schemaNode
+
+	allOf []*schemaNode
+	anyOf []*schemaNode
+	oneOf []*schemaNode
+	not   *schemaNode
+}
+
+type schemaCompiler struct {
+	doc   interface{}
+	nodes map[string]*schemaNode
+	refs  []*schemaNode
+}
+
+func (c *schemaCompiler) compile(v interface{}, ptr string) (*schemaNode, error) {
+	if n, ok := c.nodes[ptr]; ok {
+		return n, nil
+	}
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, c.error(ptr, "schema must be an object")
+	}
+
+	n := &schemaNode{maxLength: -1, maxItems: -1, maxProperties: -1}
+	c.nodes[ptr] = n
+
+	// other keywords will be ignored when there is $ref
+	if ref, ok := m["$ref"]; ok {
+		s, ok := ref.(string)
+		if !ok || !strings.HasPrefix(s, "#") {
+			return nil, c.error(ptr, "only local $ref is supported")
+		}
+		n.ref = s
+		target, err := c.resolve(s)
+		if err != nil {
+			return nil, c.error(ptr, err.Error())
+		}
+		n.refNode, err = c.compile(target, s)
+		return n, err
+	}
+
+	var err error
+	for k, x := range m {
+		p := ptr + "/" + k
+		switch k {
+		case "type":
+			switch vi := x.(type) {
+			case string:
+				n.types = []string{vi}
+			case []interface{}:
+				for _, t := range vi {
+					s, ok := t.(string)
+					if !ok {
+						return nil, c.error(p, "type must be string")
+					}
+					n.types = append(n.types, s)
+				}
+			default:
+				return nil, c.error(p, "type must be string or array")
+			}
+		case "enum":
+			arr, ok := x.([]interface{})
+			if !ok {
+				return nil, c.error(p, "enum must be array")
+			}
+			n.enum = arr
+		case "multipleOf":
+			n.multipleOf, err = c.number(p, x)
+		case "maximum":
+			n.maximum, err = c.number(p, x)
+		case "minimum":
+			n.minimum, err = c.number(p, x)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = c.boolean(p, x)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = c.boolean(p, x)
+		case "maxLength":
+			n.maxLength, err = c.integer(p, x)
+		case "minLength":
+			n.minLength, err = c.integer(p, x)
+		case "pattern":
+			n.pattern, err = c.regexp(p, x)
+		case "items":
+			switch vi := x.(type) {
+			case []interface{}:
+				for i, item := range vi {
+					child, err := c.compile(item, p+"/"+strconv.Itoa(i))
+					if err != nil {
+						return nil, err
+					}
+					n.tupleItems = append(n.tupleItems, child)
+				}
+			default:
+				n.items, err = c.compile(vi, p)
+			}
+		case "additionalItems":
+			n.additionalItems, n.noMoreItems, err = c.additional(p, x)
+		case "maxItems":
+			n.maxItems, err = c.integer(p, x)
+		case "minItems":
+			n.minItems, err = c.integer(p, x)
+		case "uniqueItems":
+			n.uniqueItems, err = c.boolean(p, x)
+		case "maxProperties":
+			n.maxProperties, err = c.integer(p, x)
+		case "minProperties":
+			n.minProperties, err = c.integer(p, x)
+		case "required":
+			n.required, err = c.strings(p, x)
+		case "properties":
+			n.properties, err = c.schemaMap(p, x)
+		case "patternProperties":
+			var props map[string]*schemaNode
+			props, err = c.schemaMap(p, x)
+			n.patternProperties = make(map[*regexp.Regexp]*schemaNode)
+			for k, child := range props {
+				re, err := c.regexp(p+"/"+k, k)
+				if err != nil {
+					return nil, err
+				}
+				n.patternProperties[re] = child
+			}
+		case "additionalProperties":
+			n.additionalProperties, n.noMoreProperties, err = c.additional(p, x)
+		case "dependencies":
+			deps, ok := x.(map[string]interface{})
+			if !ok {
+				return nil, c.error(p, "dependencies must be object")
+			}
+			n.dependencies = make(map[string]interface{})
+			for k, dep := range deps {
+				if _, ok := dep.([]interface{}); ok {
+					n.dependencies[k], err = c.strings(p+"/"+k, dep)
+				} else {
+					n.dependencies[k], err = c.compile(dep, p+"/"+k)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		case "allOf":
+			n.allOf, err = c.schemaList(p, x)
+		case "anyOf":
+			n.anyOf, err = c.schemaList(p, x)
+		case "oneOf":
+			n.oneOf, err = c.schemaList(p, x)
+		case "not":
+			n.not, err = c.compile(x, p)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return n, nil
+}
+
+// resolve : resolve the json pointer of local $ref, eg. #/definitions/address
+func (c *schemaCompiler) resolve(ref string) (interface{}, error) {
+	v := c.doc
+	ptr := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	if ptr == "" {
+		return v, nil
+	}
+	for _, token := range strings.Split(ptr, "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch vi := v.(type) {
+		case map[string]interface{}:
+			x, ok := vi[token]
+			if !ok {
+				return nil, errors.New("unresolvable $ref " + ref)
+			}
+			v = x
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(vi) {
+				return nil, errors.New("unresolvable $ref " + ref)
+			}
+			v = vi[i]
+		default:
+			return nil, errors.New("unresolvable $ref " + ref)
+		}
+	}
+	return v, nil
+}
+
+func (c *schemaCompiler) additional(ptr string, v interface{}) (*schemaNode, bool, error) {
+	if b, ok := v.(bool); ok {
+		return nil, !b, nil
+	}
+	n, err := c.compile(v, ptr)
+	return n, false, err
+}
+
+func (c *schemaCompiler) schemaMap(ptr string, v interface{}) (map[string]*schemaNode, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, c.error(ptr, "must be object")
+	}
+	nodes := make(map[string]*schemaNode)
+	for k, x := range m {
+		n, err := c.compile(x, ptr+"/"+k)
+		if err != nil {
+			return nil, err
+		}
+		nodes[k] = n
+	}
+	return nodes, nil
+}
+
+func (c *schemaCompiler) schemaList(ptr string, v interface{}) ([]*schemaNode, error) {
+	arr, ok := v.([]interface{})
+	if !ok || len(arr) == 0 {
+		return nil, c.error(ptr, "must be non-empty array")
+	}
+	nodes := make([]*schemaNode, len(arr))
+	for i, x := range arr {
+		n, err := c.compile(x, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = n
+	}
+	return nodes, nil
+}
+
+func (c *schemaCompiler) number(ptr string, v interface{}) (*big.Rat, error) {
+	num, ok := v.(json.Number)
+	if !ok {
+		return nil, c.error(ptr, "must be number")
+	}
+	r, ok := new(big.Rat).SetString(num.String())
+	if !ok {
+		return nil, c.error(ptr, "invalid number "+num.String())
+	}
+	return r, nil
+}
+
+func (c *schemaCompiler) integer(ptr string, v interface{}) (int, error) {
+	num, ok := v.(json.Number)
+	if !ok {
+		return 0, c.error(ptr, "must be integer")
+	}
+	i, err := strconv.Atoi(num.String())
+	if err != nil || i < 0 {
+		return 0, c.error(ptr, "must be non-negative integer")
+	}
+	return i, nil
+}
+
+func (c *schemaCompiler) boolean(ptr string, v interface{}) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, c.error(ptr, "must be boolean")
+	}
+	return b, nil
+}
+
+func (c *schemaCompiler) strings(ptr string, v interface{}) ([]string, error) {
+	arr, ok := v.([]interface{})
+	if !ok {
+		return nil, c.error(ptr, "must be array of string")
+	}
+	result := make([]string, len(arr))
+	for i, x := range arr {
+		s, ok := x.(string)
+		if !ok {
+			return nil, c.error(ptr, "must be array of string")
+		}
+		result[i] = s
+	}
+	return result, nil
+}
+
+func (c *schemaCompiler) regexp(ptr string, v interface{}) (*regexp.Regexp, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, c.error(ptr, "pattern must be string")
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, c.error(ptr, err.Error())
+	}
+	return re, nil
+}
+
+func (c *schemaCompiler) error(ptr, msg string) error {
+	return fmt.Errorf("jsonb: invalid json schema on %s: %s", ptr, msg)
+}
+
+func (n *schemaNode) validate(v interface{}, path string) error {
+	if n.refNode != nil {
+		return n.refNode.validate(v, path)
+	}
+
+	invalid := func(format string, args ...interface{}) error {
+		return &SchemaError{Path: path, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if len(n.types) > 0 {
+		matched := false
+		for _, t := range n.types {
+			if isType(v, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return invalid("expected %s but got %s", strings.Join(n.types, " or "), typeOf(v))
+		}
+	}
+
+	if len(n.enum) > 0 {
+		matched := false
+		for _, x := range n.enum {
+			if jsonEqual(v, x) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return invalid("value is not one of the enum")
+		}
+	}
+
+	switch vi := v.(type) {
+	case json.Number:
+		r, ok := new(big.Rat).SetString(vi.String())
+		if !ok {
+			return invalid("invalid number %s", vi)
+		}
+		if n.multipleOf != nil && n.multipleOf.Sign() != 0 && !new(big.Rat).Quo(r, n.multipleOf).IsInt() {
+			return invalid("%s is not multiple of %s", vi, n.multipleOf.RatString())
+		}
+		if n.maximum != nil {
+			if c := r.Cmp(n.maximum); c > 0 || (c == 0 && n.exclusiveMaximum) {
+				return invalid("%s exceeds the maximum %s", vi, n.maximum.RatString())
+			}
+		}
+		if n.minimum != nil {
+			if c := r.Cmp(n.minimum); c < 0 || (c == 0 && n.exclusiveMinimum) {
+				return invalid("%s is less than the minimum %s", vi, n.minimum.RatString())
+			}
+		}
+
+	case string:
+		length := utf8.RuneCountInString(vi)
+		if n.maxLength >= 0 && length > n.maxLength {
+			return invalid("length %d exceeds the maxLength %d", length, n.maxLength)
+		}
+		if length < n.minLength {
+			return invalid("length %d is less than the minLength %d", length, n.minLength)
+		}
+		if n.pattern != nil && !n.pattern.MatchString(vi) {
+			return invalid("%q doesn't match the pattern %s", vi, n.pattern)
+		}
+
+	case []interface{}:
+		if n.maxItems >= 0 && len(vi) > n.maxItems {
+			return invalid("items %d exceeds the maxItems %d", len(vi), n.maxItems)
+		}
+		if len(vi) < n.minItems {
+			return invalid("items %d is less than the minItems %d", len(vi), n.minItems)
+		}
+		if n.uniqueItems {
+			for i := range vi {
+				for j := i + 1; j < len(vi); j++ {
+					if jsonEqual(vi[i], vi[j]) {
+						return invalid("items %d and %d are not unique", i, j)
+					}
+				}
+			}
+		}
+		for i, item := range vi {
+			var child *schemaNode
+			switch {
+			case n.items != nil:
+				child = n.items
+			case i < len(n.tupleItems):
+				child = n.tupleItems[i]
+			case n.tupleItems != nil && n.noMoreItems:
+				return invalid("additional item %d is not allowed", i)
+			case n.tupleItems != nil:
+				child = n.additionalItems
+			}
+			if child == nil {
+				continue
+			}
+			if err := child.validate(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+
+	case map[string]interface{}:
+		if n.maxProperties >= 0 && len(vi) > n.maxProperties {
+			return invalid("properties %d exceeds the maxProperties %d", len(vi), n.maxProperties)
+		}
+		if len(vi) < n.minProperties {
+			return invalid("properties %d is less than the minProperties %d", len(vi), n.minProperties)
+		}
+		for _, k := range n.required {
+			if _, ok := vi[k]; !ok {
+				return invalid("missing required property %q", k)
+			}
+		}
+
+		keys := make([]string, 0, len(vi))
+		for k := range vi {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			matched := false
+			if child, ok := n.properties[k]; ok {
+				matched = true
+				if err := child.validate(vi[k], memberPath(path, k)); err != nil {
+					return err
+				}
+			}
+			for re, child := range n.patternProperties {
+				if !re.MatchString(k) {
+					continue
+				}
+				matched = true
+				if err := child.validate(vi[k], memberPath(path, k)); err != nil {
+					return err
+				}
+			}
+			if matched {
+				continue
+			}
+			if n.noMoreProperties {
+				return invalid("additional property %q is not allowed", k)
+			}
+			if n.additionalProperties != nil {
+				if err := n.additionalProperties.validate(vi[k], memberPath(path, k)); err != nil {
+					return err
+				}
+			}
+		}
+
+		for k, dep := range n.dependencies {
+			if _, ok := vi[k]; !ok {
+				continue
+			}
+			switch d := dep.(type) {
+			case []string:
+				for _, name := range d {
+					if _, ok := vi[name]; !ok {
+						return invalid("property %q is required by %q", name, k)
+					}
+				}
+			case *schemaNode:
+				if err := d.validate(v, path); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, child := range n.allOf {
+		if err := child.validate(v, path); err != nil {
+			return err
+		}
+	}
+	if len(n.anyOf) > 0 {
+		matched := false
+		for _, child := range n.anyOf {
+			if child.validate(v, path) == nil {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return invalid("value doesn't match any of the schemas")
+		}
+	}
+	if len(n.oneOf) > 0 {
+		count := 0
+		for _, child := range n.oneOf {
+			if child.validate(v, path) == nil {
+				count++
+			}
+		}
+		if count != 1 {
+			return invalid("value must match exactly one of the schemas, but matched %d", count)
+		}
+	}
+	if n.not != nil && n.not.validate(v, path) == nil {
+		return invalid("value must not match the schema")
+	}
+	return nil
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func typeOf(v interface{}) string {
+	switch vi := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if isInteger(vi) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func isType(v interface{}, typ string) bool {
+	t := typeOf(v)
+	return t == typ || (typ == "number" && t == "integer")
+}
+
+// isInteger : the number without fraction and exponent
+func isInteger(num json.Number) bool {
+	return !strings.ContainsAny(num.String(), ".eE")
+}
+
+func jsonEqual(a, b interface{}) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		r1, ok1 := new(big.Rat).SetString(x.String())
+		r2, ok2 := new(big.Rat).SetString(y.String())
+		return ok1 && ok2 && r1.Cmp(r2) == 0
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !jsonEqual(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
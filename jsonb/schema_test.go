@@ -0,0 +1,137 @@
+package jsonb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	schema, err := CompileSchema([]byte(`{
+		"type": "object",
+		"required": ["name", "age"],
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "maxLength": 5},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150, "exclusiveMaximum": true},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"ratio": {"type": "number", "multipleOf": 0.5},
+			"kind": {"enum": ["a", "b", 1]},
+			"address": {"$ref": "#/definitions/address"}
+		},
+		"patternProperties": {"^x-": {"type": "boolean"}},
+		"additionalProperties": false,
+		"definitions": {
+			"address": {
+				"type": "object",
+				"properties": {"postcode": {"type": "string", "pattern": "^[0-9]{5}$"}},
+				"dependencies": {"line2": ["line1"]}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+	require.Contains(t, schema.String(), `"required":["name","age"]`)
+
+	for _, data := range []string{
+		`{"name":"John","age":20}`,
+		`{"name":"John","age":20,"tags":["a","b"],"ratio":1.5,"kind":1.0,"x-flag":true}`,
+		`{"name":"John","age":0,"address":{"postcode":"12345","line1":"x","line2":"y"}}`,
+	} {
+		require.NoError(t, schema.Validate([]byte(data)), data)
+	}
+
+	for data, path := range map[string]string{
+		`[]`:                                                      `$`,
+		`{"name":"John"}`:                                         `$`,
+		`{"name":"","age":1}`:                                     `$.name`,
+		`{"name":"Johnny","age":1}`:                               `$.name`,
+		`{"name":"John","age":1.5}`:                               `$.age`,
+		`{"name":"John","age":150}`:                               `$.age`,
+		`{"name":"John","age":-1}`:                                `$.age`,
+		`{"name":"John","age":1,"o":1}`:                           `$`,
+		`{"name":"John","age":1,"x-a":1}`:                         `$."x-a"`,
+		`{"name":"John","age":1,"tags":["a","a"]}`:                `$.tags`,
+		`{"name":"John","age":1,"tags":["a",1]}`:                  `$.tags[1]`,
+		`{"name":"John","age":1,"tags":["a","b","c","d"]}`:        `$.tags`,
+		`{"name":"John","age":1,"ratio":1.2}`:                     `$.ratio`,
+		`{"name":"John","age":1,"kind":"c"}`:                      `$.kind`,
+		`{"name":"John","age":1,"address":{"postcode":"1234"}}`:   `$.address.postcode`,
+		`{"name":"John","age":1,"address":{"line2":"y"}}`:         `$.address`,
+		`{"name":"John","age":1,"address":{"postcode":"12345a"}}`: `$.address.postcode`,
+	} {
+		err := schema.Validate([]byte(data))
+		require.Error(t, err, data)
+		require.IsType(t, &SchemaError{}, err)
+		require.Equal(t, path, err.(*SchemaError).Path, data)
+	}
+
+	t.Run("Combination", func(t *testing.T) {
+		schema, err := CompileSchema([]byte(`{
+			"anyOf": [{"type": "string"}, {"type": "null"}],
+			"not": {"enum": ["x"]},
+			"oneOf": [{"type": "string", "maxLength": 2}, {"minLength": 1}]
+		}`))
+		require.NoError(t, err)
+		require.NoError(t, schema.Validate([]byte(`"abc"`)))
+		require.NoError(t, schema.Validate([]byte(`null`)))
+		require.Error(t, schema.Validate([]byte(`"x"`)))
+		require.Error(t, schema.Validate([]byte(`"ab"`)))
+		require.Error(t, schema.Validate([]byte(`1`)))
+	})
+
+	t.Run("Tuple items", func(t *testing.T) {
+		schema, err := CompileSchema([]byte(`{"items": [{"type": "integer"}, {"type": "string"}], "additionalItems": false}`))
+		require.NoError(t, err)
+		require.NoError(t, schema.Validate([]byte(`[1,"a"]`)))
+		require.Error(t, schema.Validate([]byte(`["a",1]`)))
+		require.Error(t, schema.Validate([]byte(`[1,"a",true]`)))
+	})
+
+	t.Run("Recursive $ref", func(t *testing.T) {
+		schema, err := CompileSchema([]byte(`{
+			"type": "object",
+			"properties": {"children": {"type": "array", "items": {"$ref": "#"}}}
+		}`))
+		require.NoError(t, err)
+		require.NoError(t, schema.Validate([]byte(`{"children":[{"children":[{}]}]}`)))
+		err = schema.Validate([]byte(`{"children":[{"children":[1]}]}`))
+		require.Error(t, err)
+		require.Equal(t, `$.children[0].children[0]`, err.(*SchemaError).Path)
+	})
+
+	t.Run("Invalid schema", func(t *testing.T) {
+		for _, s := range []string{
+			`[]`,
+			`{"type": 1}`,
+			`{"minLength": -1}`,
+			`{"pattern": "("}`,
+			`{"$ref": "http://example.com/schema"}`,
+			`{"$ref": "#/definitions/unknown"}`,
+			`{"anyOf": []}`,
+			`{`,
+		} {
+			_, err := CompileSchema([]byte(s))
+			require.Error(t, err, s)
+		}
+	})
+
+	t.Run("SchemaOf", func(t *testing.T) {
+		_, err := SchemaOf("unknown")
+		require.Error(t, err)
+
+		MustRegisterSchema("test.tags", []byte(`{"type":"array","items":{"type":"string"}}`))
+		schema, err := SchemaOf("test.tags")
+		require.NoError(t, err)
+		require.NoError(t, schema.Validate([]byte(`["a"]`)))
+
+		inline, err := SchemaOf(`{"type": "string"}`)
+		require.NoError(t, err)
+		require.Equal(t, `{"type":"string"}`, inline.String())
+		cached, err := SchemaOf(`{"type": "string"}`)
+		require.NoError(t, err)
+		require.Same(t, inline, cached)
+
+		require.Panics(t, func() {
+			MustRegisterSchema("test.invalid", []byte(`{`))
+		})
+	})
+}
@@ -114,7 +114,7 @@ loop:
 		}
 	}
 
-	if r.b[r.pos] != '}' {
+	if r.prevToken() != '}' {
 		return errors.New("invalid char on end of object")
 	}
 
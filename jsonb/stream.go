@@ -0,0 +1,468 @@
+package jsonb
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// scope : the array or object which is being written by the encoder
+type scope struct {
+	typ   byte
+	count int
+	key   bool
+}
+
+// Encoder : encoder writes json values to an output stream, values can be written as a whole by `Encode`,
+// or element by element within `BeginArray` and `EndArray`, so a large array is not required to be
+// buffered in memory. The output is buffered, and it's flushed when the top level value is completed,
+// use `Flush` to write the incomplete value to the output stream
+type Encoder struct {
+	w      *bufio.Writer
+	buf    *Writer
+	codec  *Codec
+	scopes []scope
+}
+
+// NewEncoder :
+func NewEncoder(w io.Writer) *Encoder {
+	return defaultCodec.NewEncoder(w)
+}
+
+// Encode : write the json value of v, top level value will be followed by a newline
+func (enc *Encoder) Encode(v interface{}) error {
+	b, err := enc.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return enc.writeValue(b)
+}
+
+// EncodeRaw : write the raw json value without validation
+func (enc *Encoder) EncodeRaw(b []byte) error {
+	return enc.writeValue(b)
+}
+
+// BeginArray : start an array, the values encoded afterwards will be the elements of the array
+func (enc *Encoder) BeginArray() error {
+	return enc.begin('[')
+}
+
+// EndArray :
+func (enc *Encoder) EndArray() error {
+	return enc.end('[', ']')
+}
+
+// BeginObject : start an object, every value encoded afterwards must be preceded by `Key`
+func (enc *Encoder) BeginObject() error {
+	return enc.begin('{')
+}
+
+// EndObject :
+func (enc *Encoder) EndObject() error {
+	return enc.end('{', '}')
+}
+
+// Key : write the key of the next object value
+func (enc *Encoder) Key(k string) error {
+	if len(enc.scopes) < 1 || enc.top().typ != '{' || enc.top().key {
+		return ErrInvalidJSON{
+			callback: "Encoder.Key",
+			message:  "key must be written within object before value",
+		}
+	}
+	sc := enc.top()
+	enc.buf.Reset()
+	if sc.count > 0 {
+		enc.buf.WriteByte(',')
+	}
+	enc.buf.WriteByte('"')
+	escapeString(enc.buf, k)
+	enc.buf.WriteString(`":`)
+	sc.key = true
+	return enc.flush()
+}
+
+func (enc *Encoder) top() *scope {
+	return &enc.scopes[len(enc.scopes)-1]
+}
+
+// prefix : write the separator before the value
+func (enc *Encoder) prefix() error {
+	enc.buf.Reset()
+	if len(enc.scopes) < 1 {
+		return nil
+	}
+	sc := enc.top()
+	switch sc.typ {
+	case '[':
+		if sc.count > 0 {
+			enc.buf.WriteByte(',')
+		}
+	case '{':
+		if !sc.key {
+			return ErrInvalidJSON{
+				callback: "Encoder.Encode",
+				message:  "missing key for object value",
+			}
+		}
+		sc.key = false
+	}
+	sc.count++
+	return nil
+}
+
+func (enc *Encoder) writeValue(b []byte) error {
+	if err := enc.prefix(); err != nil {
+		return err
+	}
+	enc.buf.Write(b)
+	if len(enc.scopes) < 1 {
+		enc.buf.WriteByte('\n')
+	}
+	return enc.flush()
+}
+
+func (enc *Encoder) begin(c byte) error {
+	if err := enc.prefix(); err != nil {
+		return err
+	}
+	enc.buf.WriteByte(c)
+	enc.scopes = append(enc.scopes, scope{typ: c})
+	return enc.flush()
+}
+
+func (enc *Encoder) end(open, c byte) error {
+	if len(enc.scopes) < 1 || enc.top().typ != open || enc.top().key {
+		return ErrInvalidJSON{
+			callback: "Encoder.End",
+			message:  "unexpected end of " + string(c),
+		}
+	}
+	enc.scopes = enc.scopes[:len(enc.scopes)-1]
+	enc.buf.Reset()
+	enc.buf.WriteByte(c)
+	if len(enc.scopes) < 1 {
+		enc.buf.WriteByte('\n')
+	}
+	return enc.flush()
+}
+
+// Flush : write the buffered data to the output stream
+func (enc *Encoder) Flush() error {
+	return enc.w.Flush()
+}
+
+func (enc *Encoder) flush() error {
+	if _, err := enc.w.Write(enc.buf.Bytes()); err != nil {
+		return err
+	}
+	if len(enc.scopes) < 1 {
+		return enc.w.Flush()
+	}
+	return nil
+}
+
+// Decoder : decoder reads json values from an input stream, only the value being decoded
+// (or the element of the array on `ReadArray`) will be buffered in memory
+type Decoder struct {
+	r     *bufio.Reader
+	codec *Codec
+}
+
+// NewDecoder :
+func NewDecoder(r io.Reader) *Decoder {
+	return defaultCodec.NewDecoder(r)
+}
+
+// Decode : decode the next json value into v, it will return `io.EOF` when there is no more value
+func (dec *Decoder) Decode(v interface{}) error {
+	b, err := dec.readValue()
+	if err != nil {
+		return err
+	}
+	return dec.codec.Unmarshal(b, v)
+}
+
+// More : whether there is another value in the stream
+func (dec *Decoder) More() bool {
+	c, err := dec.peek()
+	return err == nil && c != ']' && c != '}'
+}
+
+// ReadArray : read the next json array element by element, null will be treated as empty array
+func (dec *Decoder) ReadArray(cb func(r *Reader) error) error {
+	c, err := dec.next()
+	if err != nil {
+		return err
+	}
+	if c == 'n' {
+		return dec.expect("ull")
+	}
+	if c != '[' {
+		return ErrInvalidJSON{
+			callback: "Decoder.ReadArray",
+			message:  "expect start with [ for array",
+		}
+	}
+
+	c, err = dec.peek()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if c == ']' {
+		dec.r.ReadByte()
+		return nil
+	}
+
+	for {
+		b, err := dec.readValue()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if cb != nil {
+			if err := cb(NewReader(b)); err != nil {
+				return err
+			}
+		}
+
+		c, err = dec.next()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if c != ',' {
+			break
+		}
+	}
+
+	if c != ']' {
+		return ErrInvalidJSON{
+			callback: "Decoder.ReadArray",
+			message:  "expect end with ] for array",
+		}
+	}
+	return nil
+}
+
+// ReadObject : read the next json object key by key, null will be treated as empty object. The value of the
+// key can be read by `Decode`, `ReadArray`, `ReadObject` or `Skip` within the callback, otherwise it will be skipped,
+// eg. descend into `{"rows":[...]}` and read the rows by `ReadArray`
+func (dec *Decoder) ReadObject(cb func(key string) error) error {
+	c, err := dec.next()
+	if err != nil {
+		return err
+	}
+	if c == 'n' {
+		return dec.expect("ull")
+	}
+	if c != '{' {
+		return ErrInvalidJSON{
+			callback: "Decoder.ReadObject",
+			message:  "expect start with { for object",
+		}
+	}
+
+	c, err = dec.peek()
+	if err != nil {
+		return unexpectedEOF(err)
+	}
+	if c == '}' {
+		dec.r.ReadByte()
+		return nil
+	}
+
+	for {
+		b, err := dec.readValue()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		var key string
+		if valueMap[b[0]] != jsonString {
+			return ErrInvalidJSON{
+				callback: "Decoder.ReadObject",
+				message:  "expect string for object key",
+			}
+		}
+		if err := dec.codec.Unmarshal(b, &key); err != nil {
+			return err
+		}
+		if c, err = dec.next(); err != nil {
+			return unexpectedEOF(err)
+		}
+		if c != ':' {
+			return ErrInvalidJSON{
+				callback: "Decoder.ReadObject",
+				message:  "expect : after object key",
+			}
+		}
+
+		if cb != nil {
+			if err := cb(key); err != nil {
+				return err
+			}
+		}
+		// the value is not read by the callback
+		if c, err = dec.peek(); err != nil {
+			return unexpectedEOF(err)
+		}
+		if c != ',' && c != '}' {
+			if err := dec.Skip(); err != nil {
+				return err
+			}
+		}
+
+		c, err = dec.next()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if c != ',' {
+			break
+		}
+	}
+
+	if c != '}' {
+		return ErrInvalidJSON{
+			callback: "Decoder.ReadObject",
+			message:  "expect end with } for object",
+		}
+	}
+	return nil
+}
+
+// Skip : discard the next json value
+func (dec *Decoder) Skip() error {
+	_, err := dec.readValue()
+	return err
+}
+
+// next : return the next non whitespace byte
+func (dec *Decoder) next() (byte, error) {
+	for {
+		c, err := dec.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if valueMap[c] != jsonWhitespace {
+			return c, nil
+		}
+	}
+}
+
+// peek : return the next non whitespace byte without consuming it
+func (dec *Decoder) peek() (byte, error) {
+	c, err := dec.next()
+	if err != nil {
+		return 0, err
+	}
+	return c, dec.r.UnreadByte()
+}
+
+func (dec *Decoder) expect(s string) error {
+	for i := 0; i < len(s); i++ {
+		c, err := dec.r.ReadByte()
+		if err != nil {
+			return unexpectedEOF(err)
+		}
+		if c != s[i] {
+			return ErrInvalidJSON{
+				callback: "Decoder",
+				message:  "unexpected char " + string(c),
+			}
+		}
+	}
+	return nil
+}
+
+// readValue : read the bytes of the next json value
+func (dec *Decoder) readValue() ([]byte, error) {
+	c, err := dec.next()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteByte(c)
+	switch valueMap[c] {
+	case jsonString:
+		err = dec.readString(buf)
+	case jsonArray, jsonObject:
+		err = dec.readComposite(buf)
+	case jsonNumber, jsonBoolean, jsonNull:
+		err = dec.readLiteral(buf)
+	default:
+		return nil, ErrInvalidJSON{
+			callback: "Decoder",
+			message:  "invalid char " + string(c),
+		}
+	}
+	if err != nil {
+		return nil, unexpectedEOF(err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (dec *Decoder) readString(buf *bytes.Buffer) error {
+	escaped := false
+	for {
+		c, err := dec.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(c)
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			return nil
+		}
+	}
+}
+
+func (dec *Decoder) readComposite(buf *bytes.Buffer) error {
+	level := 1
+	for level > 0 {
+		c, err := dec.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(c)
+		switch c {
+		case '"':
+			if err := dec.readString(buf); err != nil {
+				return err
+			}
+		case '[', '{':
+			level++
+		case ']', '}':
+			level--
+		}
+	}
+	return nil
+}
+
+func (dec *Decoder) readLiteral(buf *bytes.Buffer) error {
+	for {
+		c, err := dec.r.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch c {
+		case ',', ']', '}', ' ', '\t', '\r', '\n':
+			return dec.r.UnreadByte()
+		}
+		buf.WriteByte(c)
+	}
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package jsonb
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder(t *testing.T) {
+	type row struct {
+		ID   int
+		Name string
+	}
+
+	t.Run("Encode", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		enc := NewEncoder(w)
+		require.NoError(t, enc.Encode(row{ID: 1, Name: "a"}))
+		require.NoError(t, enc.Encode("b"))
+		require.Equal(t, "{\"ID\":1,\"Name\":\"a\"}\n\"b\"\n", w.String())
+	})
+
+	t.Run("Stream array and object", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		enc := NewEncoder(w)
+		require.NoError(t, enc.BeginObject())
+		require.NoError(t, enc.Key("total"))
+		require.NoError(t, enc.Encode(2))
+		require.NoError(t, enc.Key(`rows"`))
+		require.NoError(t, enc.BeginArray())
+		for i := 1; i <= 2; i++ {
+			require.NoError(t, enc.Encode(row{ID: i}))
+		}
+		require.NoError(t, enc.EncodeRaw([]byte(`null`)))
+		require.NoError(t, enc.EndArray())
+		require.NoError(t, enc.EndObject())
+		require.Equal(t, "{\"total\":2,\"rows\\\"\":[{\"ID\":1,\"Name\":\"\"},{\"ID\":2,\"Name\":\"\"},null]}\n", w.String())
+	})
+
+	t.Run("Buffered until the top level value is completed", func(t *testing.T) {
+		w := &countWriter{}
+		enc := NewEncoder(w)
+		require.NoError(t, enc.BeginArray())
+		for i := 1; i <= 100; i++ {
+			require.NoError(t, enc.Encode(row{ID: i}))
+		}
+		require.Zero(t, w.writes)
+		require.NoError(t, enc.Flush())
+		require.Equal(t, 1, w.writes)
+		require.NoError(t, enc.EndArray())
+		require.Equal(t, 2, w.writes)
+		require.True(t, strings.HasSuffix(w.String(), "{\"ID\":100,\"Name\":\"\"}]\n"))
+	})
+
+	t.Run("Invalid token", func(t *testing.T) {
+		enc := NewEncoder(io.Discard)
+		require.Error(t, enc.EndArray())
+		require.Error(t, enc.Key("a"))
+		require.NoError(t, enc.BeginObject())
+		require.Error(t, enc.Encode(1))
+		require.Error(t, enc.EndArray())
+	})
+}
+
+type countWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countWriter) Write(b []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(b)
+}
+
+func TestDecoder(t *testing.T) {
+	type row struct {
+		ID   int
+		Name string
+	}
+
+	t.Run("Decode", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"ID":1,"Name":"a, \"b\"]"} 10 "x"
+		[1, 2] true null`))
+
+		var r row
+		require.NoError(t, dec.Decode(&r))
+		require.Equal(t, row{ID: 1, Name: `a, "b"]`}, r)
+
+		var i int
+		require.NoError(t, dec.Decode(&i))
+		require.Equal(t, 10, i)
+
+		var s string
+		require.NoError(t, dec.Decode(&s))
+		require.Equal(t, "x", s)
+
+		var ints []int
+		require.NoError(t, dec.Decode(&ints))
+		require.Equal(t, []int{1, 2}, ints)
+
+		var flag bool
+		require.NoError(t, dec.Decode(&flag))
+		require.True(t, flag)
+
+		var ptr *int
+		require.True(t, dec.More())
+		require.NoError(t, dec.Decode(&ptr))
+		require.Nil(t, ptr)
+
+		require.False(t, dec.More())
+		require.Equal(t, io.EOF, dec.Decode(&i))
+	})
+
+	t.Run("ReadArray", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(` [ {"ID":1,"Name":"a"} , {"ID":2,"Name":"[b]"},{"ID":3} ] `))
+		rows := make([]row, 0)
+		require.NoError(t, dec.ReadArray(func(r *Reader) error {
+			var x row
+			if err := Unmarshal(r.Bytes(), &x); err != nil {
+				return err
+			}
+			rows = append(rows, x)
+			return nil
+		}))
+		require.Equal(t, []row{{ID: 1, Name: "a"}, {ID: 2, Name: "[b]"}, {ID: 3}}, rows)
+	})
+
+	t.Run("ReadArray with null and empty array", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`null []`))
+		require.NoError(t, dec.ReadArray(nil))
+		require.NoError(t, dec.ReadArray(func(r *Reader) error {
+			return io.ErrUnexpectedEOF
+		}))
+		require.Equal(t, io.EOF, dec.ReadArray(nil))
+	})
+
+	t.Run("ReadArray with invalid json", func(t *testing.T) {
+		require.Error(t, NewDecoder(strings.NewReader(`{}`)).ReadArray(nil))
+		require.Equal(t, io.ErrUnexpectedEOF, NewDecoder(strings.NewReader(`[1,2`)).ReadArray(nil))
+		require.Error(t, NewDecoder(strings.NewReader(`[1 2]`)).ReadArray(nil))
+	})
+
+	t.Run("ReadObject", func(t *testing.T) {
+		dec := NewDecoder(strings.NewReader(`{"total": 3, "meta": {"page": 1, "tags": ["a"]}, "rows\n": [{"ID":1},{"ID":2,"Name":"}"}], "next": null} {}`))
+		var (
+			total int
+			page  int
+			keys  []string
+			rows  []row
+		)
+		require.NoError(t, dec.ReadObject(func(key string) error {
+			keys = append(keys, key)
+			switch key {
+			case "total":
+				return dec.Decode(&total)
+			case "meta":
+				return dec.ReadObject(func(key string) error {
+					if key == "page" {
+						return dec.Decode(&page)
+					}
+					// the value which is not read will be skipped
+					return nil
+				})
+			case "rows\n":
+				return dec.ReadArray(func(r *Reader) error {
+					var x row
+					if err := Unmarshal(r.Bytes(), &x); err != nil {
+						return err
+					}
+					rows = append(rows, x)
+					return nil
+				})
+			}
+			return dec.Skip()
+		}))
+		require.Equal(t, []string{"total", "meta", "rows\n", "next"}, keys)
+		require.Equal(t, 3, total)
+		require.Equal(t, 1, page)
+		require.Equal(t, []row{{ID: 1}, {ID: 2, Name: "}"}}, rows)
+
+		require.NoError(t, dec.ReadObject(func(key string) error {
+			return io.ErrUnexpectedEOF
+		}))
+		require.Equal(t, io.EOF, dec.ReadObject(nil))
+	})
+
+	t.Run("ReadObject with invalid json", func(t *testing.T) {
+		require.NoError(t, NewDecoder(strings.NewReader(`null`)).ReadObject(nil))
+		require.Error(t, NewDecoder(strings.NewReader(`[]`)).ReadObject(nil))
+		require.Error(t, NewDecoder(strings.NewReader(`{1:2}`)).ReadObject(nil))
+		require.Error(t, NewDecoder(strings.NewReader(`{"a" 1}`)).ReadObject(nil))
+		require.Error(t, NewDecoder(strings.NewReader(`{"a":1,}`)).ReadObject(nil))
+		require.Equal(t, io.ErrUnexpectedEOF, NewDecoder(strings.NewReader(`{"a":1`)).ReadObject(nil))
+	})
+}
@@ -1,6 +1,9 @@
 package jsonb
 
 import (
+	"unicode"
+	"unicode/utf16"
+
 	"github.com/Oskang09/sqlike/util"
 )
 
@@ -90,9 +93,15 @@ func (r *Reader) ReadString() (string, error) {
 				blr.WriteRune('/')
 				i += 2
 			case 'u':
-				// TODO: unicode
-				blr.WriteRune('u')
-				i += 2
+				r1, n := readRune(r.b[i:])
+				if n < 0 {
+					return "", ErrInvalidJSON{
+						callback: "ReadString",
+						message:  "invalid unicode escape",
+					}
+				}
+				blr.WriteRune(r1)
+				i += n
 			default:
 				blr.WriteByte(c)
 			}
@@ -192,3 +201,67 @@ func escapeString(w *Writer, str string) {
 		w.WriteByte(b)
 	}
 }
+
+var htmlCharMap = map[byte][]byte{
+	'<': []byte(`\u003c`),
+	'>': []byte(`\u003e`),
+	'&': []byte(`\u0026`),
+}
+
+func escapeHTMLString(w *Writer, str string) {
+	length := len(str)
+	for i := 0; i < length; i++ {
+		b := str[i]
+		if x, ok := escapeCharMap[b]; ok {
+			w.Write(x)
+			continue
+		}
+		if x, ok := htmlCharMap[b]; ok {
+			w.Write(x)
+			continue
+		}
+		w.WriteByte(b)
+	}
+}
+
+// readRune : decode the unicode escape sequence at the beginning of b, eg. \u00e9,
+// it returns the rune and the number of bytes consumed, or -1 if it's invalid
+func readRune(b []byte) (rune, int) {
+	r1 := hexRune(b)
+	if r1 < 0 {
+		return -1, -1
+	}
+	if utf16.IsSurrogate(r1) {
+		r2 := hexRune(b[6:])
+		if r2 < 0 {
+			return unicode.ReplacementChar, 6
+		}
+		if r := utf16.DecodeRune(r1, r2); r != unicode.ReplacementChar {
+			return r, 12
+		}
+		return unicode.ReplacementChar, 6
+	}
+	return r1, 6
+}
+
+// hexRune : parse the \uXXXX sequence
+func hexRune(b []byte) rune {
+	if len(b) < 6 || b[0] != '\\' || b[1] != 'u' {
+		return -1
+	}
+	var r rune
+	for _, c := range b[2:6] {
+		switch {
+		case '0' <= c && c <= '9':
+			c = c - '0'
+		case 'a' <= c && c <= 'f':
+			c = c - 'a' + 10
+		case 'A' <= c && c <= 'F':
+			c = c - 'A' + 10
+		default:
+			return -1
+		}
+		r = r*16 + rune(c)
+	}
+	return r
+}
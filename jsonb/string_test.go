@@ -64,6 +64,17 @@ func TestReadString(t *testing.T) {
 		require.Empty(t, str)
 	})
 
+	t.Run("ReadString with unicode escape", func(t *testing.T) {
+		r := NewReader([]byte(`"<a> é 😀"`))
+		str, err := r.ReadString()
+		require.NoError(t, err)
+		require.Equal(t, "<a> é 😀", str)
+
+		r = NewReader([]byte(`"\u12"`))
+		_, err = r.ReadString()
+		require.Error(t, err)
+	})
+
 	t.Run("ReadString with null", func(t *testing.T) {
 		r := NewReader([]byte(`null`))
 		str, err := r.ReadString()
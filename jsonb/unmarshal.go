@@ -19,6 +19,10 @@ type Unmarshaler interface {
 
 // Unmarshal :
 func Unmarshal(data []byte, dst interface{}) error {
+	return defaultCodec.Unmarshal(data, dst)
+}
+
+func unmarshal(registry *Registry, data []byte, dst interface{}) error {
 	v := reflext.ValueOf(dst)
 	if !v.IsValid() {
 		return errors.New("invalid value for Unmarshal")
@@ -59,6 +63,10 @@ func Unmarshal(data []byte, dst interface{}) error {
 
 // UnmarshalValue :
 func UnmarshalValue(data []byte, v reflect.Value) error {
+	return defaultCodec.UnmarshalValue(data, v)
+}
+
+func unmarshalValue(registry *Registry, data []byte, v reflect.Value) error {
 	if data == nil {
 		v.Set(reflect.Zero(v.Type()))
 		return nil
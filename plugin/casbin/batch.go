@@ -0,0 +1,55 @@
+package casbin
+
+import (
+	"github.com/Oskang09/sqlike/sql/expr"
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/sqlike/options"
+)
+
+// AddPolicies : adds policy rules to the storage in a single statement. This is part of the Auto-Save feature.
+func (a *Adapter) AddPolicies(sec string, ptype string, rules [][]string) error {
+	if len(rules) < 1 {
+		return nil
+	}
+
+	policies := make([]*Policy, 0, len(rules))
+	for _, r := range rules {
+		policies = append(policies, toPolicy(ptype, r))
+	}
+
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		if _, err := table.Insert(
+			ctx,
+			&policies,
+			options.Insert().
+				SetMode(options.InsertIgnore),
+		); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// RemovePolicies : removes policy rules from the storage in a single statement. This is part of the Auto-Save feature.
+func (a *Adapter) RemovePolicies(sec string, ptype string, rules [][]string) error {
+	if len(rules) < 1 {
+		return nil
+	}
+
+	filters := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		filters = append(filters, policyFilter(toPolicy(ptype, r)))
+	}
+
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		if _, err := table.Delete(
+			ctx,
+			actions.Delete().
+				Where(expr.Or(filters...)),
+		); err != nil {
+			return err
+		}
+		return nil
+	})
+}
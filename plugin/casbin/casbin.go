@@ -3,6 +3,7 @@ package casbin
 import (
 	"context"
 	"errors"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -20,16 +21,32 @@ import (
 type Adapter struct {
 	ctx      context.Context
 	table    *sqlike.Table
+	watcher  *Watcher
 	filtered bool
 }
 
-var _ persist.FilteredAdapter = new(Adapter)
+// AdapterOption :
+type AdapterOption func(*Adapter)
+
+// WithWatcher : increase the revision of the watcher within the transaction of every adapter write,
+// the watcher should be on the same client as the adapter
+func WithWatcher(w *Watcher) AdapterOption {
+	return func(a *Adapter) {
+		a.watcher = w
+	}
+}
+
+var (
+	_ persist.FilteredAdapter  = new(Adapter)
+	_ persist.BatchAdapter     = new(Adapter)
+	_ persist.UpdatableAdapter = new(Adapter)
+)
 
 var mutex = &sync.Mutex{}
 
 // MustNew :
-func MustNew(ctx context.Context, table *sqlike.Table) persist.FilteredAdapter {
-	a, err := New(ctx, table)
+func MustNew(ctx context.Context, table *sqlike.Table, opts ...AdapterOption) persist.FilteredAdapter {
+	a, err := New(ctx, table, opts...)
 	if err != nil {
 		panic(err)
 	}
@@ -37,7 +54,7 @@ func MustNew(ctx context.Context, table *sqlike.Table) persist.FilteredAdapter {
 }
 
 // New :
-func New(ctx context.Context, table *sqlike.Table) (persist.FilteredAdapter, error) {
+func New(ctx context.Context, table *sqlike.Table, opts ...AdapterOption) (persist.FilteredAdapter, error) {
 	if table == nil {
 		return nil, errors.New("invalid <nil> table")
 	}
@@ -45,6 +62,12 @@ func New(ctx context.Context, table *sqlike.Table) (persist.FilteredAdapter, err
 		ctx:   ctx,
 		table: table,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.watcher != nil {
+		a.watcher.link()
+	}
 	if err := a.createTable(); err != nil {
 		return nil, err
 	}
@@ -129,51 +152,49 @@ func (a *Adapter) SavePolicy(model model.Model) error {
 		}
 	}
 
-	if len(policies) > 0 {
-		if _, err := a.table.Insert(
-			a.ctx,
+	if len(policies) < 1 {
+		return nil
+	}
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		if _, err := table.Insert(
+			ctx,
 			&policies,
 			options.Insert().
 				SetMode(options.InsertOnDuplicate),
 		); err != nil {
 			return err
 		}
-	}
-	return nil
+		return nil
+	})
 }
 
 // AddPolicy : adds a policy policy to the storage. This is part of the Auto-Save feature.
 func (a *Adapter) AddPolicy(sec string, ptype string, rules []string) error {
-	if _, err := a.table.InsertOne(
-		a.ctx,
-		toPolicy(ptype, rules),
-		options.InsertOne().
-			SetMode(options.InsertIgnore),
-	); err != nil {
-		return err
-	}
-	return nil
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		if _, err := table.InsertOne(
+			ctx,
+			toPolicy(ptype, rules),
+			options.InsertOne().
+				SetMode(options.InsertIgnore),
+		); err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
 // RemovePolicy : removes a policy policy from the storage. This is part of the Auto-Save feature.
 func (a *Adapter) RemovePolicy(sec string, ptype string, rules []string) error {
-	policy := toPolicy(ptype, rules)
-	if _, err := a.table.DeleteOne(
-		a.ctx,
-		actions.DeleteOne().
-			Where(
-				expr.Equal("PType", policy.PType),
-				expr.Equal("V0", policy.V0),
-				expr.Equal("V1", policy.V1),
-				expr.Equal("V2", policy.V2),
-				expr.Equal("V3", policy.V3),
-				expr.Equal("V4", policy.V4),
-				expr.Equal("V5", policy.V5),
-			),
-	); err != nil {
-		return err
-	}
-	return nil
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		if _, err := table.DeleteOne(
+			ctx,
+			actions.DeleteOne().
+				Where(policyFilter(toPolicy(ptype, rules))),
+		); err != nil {
+			return err
+		}
+		return nil
+	})
 }
 
 // RemoveFilteredPolicy : removes policy rules that match the filter from the storage. This is part of the Auto-Save feature.
@@ -264,6 +285,83 @@ func toPolicy(ptype string, rules []string) *Policy {
 	return policy
 }
 
+// policyFilter : is the condition which match the exact policy
+func policyFilter(policy *Policy) primitive.Group {
+	return expr.And(
+		expr.Equal("PType", policy.PType),
+		expr.Equal("V0", policy.V0),
+		expr.Equal("V1", policy.V1),
+		expr.Equal("V2", policy.V2),
+		expr.Equal("V3", policy.V3),
+		expr.Equal("V4", policy.V4),
+		expr.Equal("V5", policy.V5),
+	)
+}
+
+// fieldFilter : is the condition which match the field values start from the field index,
+// empty value means any value
+func fieldFilter(ptype string, idx int, values ...string) primitive.Group {
+	conds := []interface{}{expr.Equal("PType", ptype)}
+	for i, v := range values {
+		field := idx + i
+		if v == "" || field < 0 {
+			continue
+		}
+		if field > 5 {
+			break
+		}
+		conds = append(conds, expr.Equal("V"+strconv.Itoa(field), v))
+	}
+	return expr.And(conds...)
+}
+
+// runInTransaction : run the callback in a new transaction, the tables within the transaction are passed to the callback
+func runInTransaction(ctx context.Context, tables []*sqlike.Table, cb func(ctx sqlike.SessionContext, tables []*sqlike.Table) error) error {
+	return tables[0].RunInTransaction(ctx, func(sess sqlike.SessionContext) error {
+		bound := make([]*sqlike.Table, len(tables))
+		for i, tb := range tables {
+			table, err := tb.Bind(sess)
+			if err != nil {
+				return err
+			}
+			bound[i] = table
+		}
+		return cb(sess, bound)
+	})
+}
+
+// write : run the callback in a new transaction, the revision of the watcher will be increased
+// within the same transaction, so the other instances won't miss the change
+func (a *Adapter) write(cb func(ctx sqlike.SessionContext, table *sqlike.Table) error) error {
+	tables := []*sqlike.Table{a.table}
+	if a.watcher != nil {
+		tables = append(tables, a.watcher.table)
+	}
+
+	var revision int64
+	if err := runInTransaction(a.ctx, tables, func(ctx sqlike.SessionContext, tables []*sqlike.Table) error {
+		if err := cb(ctx, tables[0]); err != nil {
+			return err
+		}
+		if a.watcher == nil {
+			return nil
+		}
+		rev, err := increaseRevision(ctx, tables[1])
+		if err != nil {
+			return err
+		}
+		revision = rev
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if a.watcher != nil {
+		a.watcher.setRevision(revision)
+	}
+	return nil
+}
+
 func (a *Adapter) createTable() error {
 	return a.table.UnsafeMigrate(a.ctx, Policy{})
 }
@@ -0,0 +1,132 @@
+package casbin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Oskang09/sqlike/sql/expr"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/stretchr/testify/require"
+)
+
+const rbacModel = `
+[request_definition]
+r = sub, obj, act
+
+[policy_definition]
+p = sub, obj, act
+
+[role_definition]
+g = _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
+`
+
+func TestPolicy(t *testing.T) {
+	policy := toPolicy("p", []string{"admin", "/users", "GET"})
+	require.Equal(t, &Policy{PType: "p", V0: "admin", V1: "/users", V2: "GET"}, policy)
+	require.Equal(t, []string{"admin", "/users", "GET"}, policy.rules())
+	require.Equal(t, []string{}, toPolicy("p", nil).rules())
+
+	policy = toPolicy("p", []string{"a", "b", "c", "d", "e", "f", "g"})
+	require.Equal(t, "f", policy.V5)
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f"}, policy.rules())
+}
+
+func TestLoadPolicy(t *testing.T) {
+	m, err := model.NewModelFromString(rbacModel)
+	require.NoError(t, err)
+
+	loadPolicy(toPolicy("p", []string{"admin", "/users", "GET"}), m)
+	loadPolicy(toPolicy("g", []string{"alice", "admin"}), m)
+	require.Equal(t, [][]string{{"admin", "/users", "GET"}}, m["p"]["p"].Policy)
+	require.Equal(t, [][]string{{"alice", "admin"}}, m["g"]["g"].Policy)
+	require.Equal(t, 0, m["p"]["p"].PolicyMap["admin,/users,GET"])
+}
+
+func TestFilters(t *testing.T) {
+	require.Equal(t, expr.And(
+		expr.Equal("PType", "p"),
+		expr.Equal("V0", "admin"),
+		expr.Equal("V1", "/users"),
+		expr.Equal("V2", "GET"),
+		expr.Equal("V3", ""),
+		expr.Equal("V4", ""),
+		expr.Equal("V5", ""),
+	), policyFilter(toPolicy("p", []string{"admin", "/users", "GET"})))
+
+	// empty value matches any value
+	require.Equal(t, expr.And(
+		expr.Equal("PType", "p"),
+		expr.Equal("V1", "/users"),
+		expr.Equal("V3", "x"),
+	), fieldFilter("p", 1, "/users", "", "x"))
+
+	// the fields out of range are skipped
+	require.Equal(t, expr.And(
+		expr.Equal("PType", "p"),
+		expr.Equal("V5", "f"),
+	), fieldFilter("p", 5, "f", "g"))
+	require.Equal(t, expr.And(
+		expr.Equal("PType", "p"),
+		expr.Equal("V0", "a"),
+	), fieldFilter("p", -1, "x", "a"))
+}
+
+func TestAdapter(t *testing.T) {
+	_, err := New(context.Background(), nil)
+	require.Error(t, err)
+
+	a := &Adapter{ctx: context.Background()}
+	require.False(t, a.IsFiltered())
+
+	// removing the filtered policy is a no-op
+	require.NoError(t, a.RemoveFilteredPolicy("p", "p", 0, "admin"))
+
+	// nothing to write
+	require.NoError(t, a.AddPolicies("p", "p", nil))
+	require.NoError(t, a.RemovePolicies("p", "p", nil))
+
+	err = a.UpdatePolicies("p", "p", [][]string{{"a"}}, nil)
+	require.Error(t, err)
+}
+
+func TestWatcherOptions(t *testing.T) {
+	_, err := NewWatcher(context.Background(), nil)
+	require.Error(t, err)
+
+	var called bool
+	opts := WatcherOptions{}
+	WithInterval(time.Second)(&opts)
+	WithErrorHandler(func(error) { called = true })(&opts)
+	require.Equal(t, time.Second, opts.Interval)
+	opts.OnError(nil)
+	require.True(t, called)
+}
+
+func TestWatcherRevision(t *testing.T) {
+	w := &Watcher{revision: 3}
+	w.setRevision(2)
+	require.Equal(t, int64(3), w.revision)
+	w.setRevision(4)
+	require.Equal(t, int64(4), w.revision)
+
+	// the revision 5 is changed by the other instance, so the callback should be called
+	w.setRevision(6)
+	require.Equal(t, int64(4), w.revision)
+
+	// the revision is increased by the adapter when it's linked
+	_, err := New(context.Background(), nil, WithWatcher(w))
+	require.Error(t, err)
+	require.False(t, w.linked)
+	a := &Adapter{}
+	WithWatcher(w)(a)
+	require.Equal(t, w, a.watcher)
+	w.link()
+	require.NoError(t, w.Update())
+}
@@ -10,3 +10,12 @@ type Policy struct {
 	V4    string `sqlike:",size=50"`
 	V5    string `sqlike:",size=50"`
 }
+
+// rules : is the policy values without the trailing empty values
+func (p *Policy) rules() []string {
+	rules := []string{p.V0, p.V1, p.V2, p.V3, p.V4, p.V5}
+	for len(rules) > 0 && rules[len(rules)-1] == "" {
+		rules = rules[:len(rules)-1]
+	}
+	return rules
+}
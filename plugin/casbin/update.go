@@ -0,0 +1,117 @@
+package casbin
+
+import (
+	"errors"
+
+	"github.com/Oskang09/sqlike/sql/expr"
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/sqlike/options"
+)
+
+// UpdatePolicy : updates a policy rule from the storage, `sqlike.ErrNoRecordAffected` will be returned
+// if the old rule doesn't exist. This is part of the Auto-Save feature.
+func (a *Adapter) UpdatePolicy(sec string, ptype string, oldRule, newRule []string) error {
+	return a.UpdatePolicies(sec, ptype, [][]string{oldRule}, [][]string{newRule})
+}
+
+// UpdatePolicies : updates the policy rules in a transaction, the old rules and new rules are
+// matched by position. This is part of the Auto-Save feature.
+func (a *Adapter) UpdatePolicies(sec string, ptype string, oldRules, newRules [][]string) error {
+	if len(oldRules) != len(newRules) {
+		return errors.New("casbin: the length of old rules and new rules should be the same")
+	}
+
+	return a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		for i := range oldRules {
+			if err := updatePolicy(ctx, table, toPolicy(ptype, oldRules[i]), toPolicy(ptype, newRules[i])); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// UpdateFilteredPolicies : deletes the policy rules that match the filter and adds the new rules
+// in a transaction, it will return the deleted rules. This is part of the Auto-Save feature.
+func (a *Adapter) UpdateFilteredPolicies(sec string, ptype string, newRules [][]string, idx int, values ...string) ([][]string, error) {
+	var (
+		filter   = fieldFilter(ptype, idx, values...)
+		policies = make([]*Policy, 0)
+	)
+
+	if err := a.write(func(ctx sqlike.SessionContext, table *sqlike.Table) error {
+		result, err := table.Find(
+			ctx,
+			actions.Find().
+				Where(filter),
+			options.Find().
+				SetNoLimit(true).
+				SetLockMode(options.LockForUpdate),
+		)
+		if err != nil {
+			return err
+		}
+		if err := result.All(&policies); err != nil {
+			return err
+		}
+
+		if _, err := table.Delete(
+			ctx,
+			actions.Delete().
+				Where(filter),
+		); err != nil {
+			return err
+		}
+
+		if len(newRules) < 1 {
+			return nil
+		}
+
+		inserts := make([]*Policy, 0, len(newRules))
+		for _, r := range newRules {
+			inserts = append(inserts, toPolicy(ptype, r))
+		}
+		if _, err := table.Insert(
+			ctx,
+			&inserts,
+			options.Insert().
+				SetMode(options.InsertIgnore),
+		); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	oldRules := make([][]string, 0, len(policies))
+	for _, policy := range policies {
+		oldRules = append(oldRules, policy.rules())
+	}
+	return oldRules, nil
+}
+
+func updatePolicy(ctx sqlike.SessionContext, table *sqlike.Table, oldPolicy, newPolicy *Policy) error {
+	affected, err := table.UpdateOne(
+		ctx,
+		actions.UpdateOne().
+			Where(policyFilter(oldPolicy)).
+			Set(
+				expr.ColumnValue("PType", newPolicy.PType),
+				expr.ColumnValue("V0", newPolicy.V0),
+				expr.ColumnValue("V1", newPolicy.V1),
+				expr.ColumnValue("V2", newPolicy.V2),
+				expr.ColumnValue("V3", newPolicy.V3),
+				expr.ColumnValue("V4", newPolicy.V4),
+				expr.ColumnValue("V5", newPolicy.V5),
+			),
+	)
+	if err != nil {
+		return err
+	}
+	if affected < 1 {
+		return sqlike.ErrNoRecordAffected
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+package casbin
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Oskang09/sqlike/sql/expr"
+	"github.com/Oskang09/sqlike/sqlike"
+	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/sqlike/options"
+	"github.com/casbin/casbin/v2/persist"
+)
+
+// DefaultPollInterval : is the default interval to check the revision
+const DefaultPollInterval = 5 * time.Second
+
+// revisionID : the companion table only has a single row
+const revisionID = 1
+
+// Revision : is the row of the companion table, the revision will be increased on every policy change
+type Revision struct {
+	ID        uint8 `sqlike:",primary_key"`
+	Revision  int64
+	UpdatedAt time.Time
+}
+
+// WatcherOptions :
+type WatcherOptions struct {
+	// Interval is the polling interval to check the revision, default is `DefaultPollInterval`
+	Interval time.Duration
+
+	// OnError will be called when polling failed, the watcher will keep polling
+	OnError func(error)
+}
+
+// WatcherOption :
+type WatcherOption func(*WatcherOptions)
+
+// WithInterval : set the polling interval to check the revision
+func WithInterval(interval time.Duration) WatcherOption {
+	return func(opt *WatcherOptions) {
+		opt.Interval = interval
+	}
+}
+
+// WithErrorHandler : set the handler which will be called when polling failed
+func WithErrorHandler(fn func(error)) WatcherOption {
+	return func(opt *WatcherOptions) {
+		opt.OnError = fn
+	}
+}
+
+// Watcher : is the `persist.Watcher` backed by the revision row of the companion table, so every
+// instance will be notified about the policy changes without an external message bus. Pass it to
+// the adapter by `WithWatcher`, so the revision will be increased within every adapter write, and
+// use `Enforcer.SetWatcher` to receive the changes of the other instances.
+type Watcher struct {
+	ctx   context.Context
+	table *sqlike.Table
+	opts  WatcherOptions
+
+	mu       sync.Mutex
+	callback func(string)
+	revision int64
+	linked   bool
+
+	once   sync.Once
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ persist.Watcher = new(Watcher)
+
+// MustNewWatcher :
+func MustNewWatcher(ctx context.Context, table *sqlike.Table, opts ...WatcherOption) *Watcher {
+	w, err := NewWatcher(ctx, table, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// NewWatcher : create the watcher with the companion table, the table will be migrated and start polling immediately
+func NewWatcher(ctx context.Context, table *sqlike.Table, opts ...WatcherOption) (*Watcher, error) {
+	if table == nil {
+		return nil, errors.New("invalid <nil> table")
+	}
+
+	w := &Watcher{
+		ctx:   ctx,
+		table: table,
+		done:  make(chan struct{}),
+	}
+	w.opts.Interval = DefaultPollInterval
+	for _, opt := range opts {
+		opt(&w.opts)
+	}
+	if w.opts.Interval <= 0 {
+		return nil, errors.New("casbin: poll interval should be greater than zero")
+	}
+
+	if err := table.Migrate(ctx, Revision{}); err != nil {
+		return nil, err
+	}
+	if _, err := table.InsertOne(
+		ctx,
+		&Revision{ID: revisionID, UpdatedAt: time.Now().UTC()},
+		options.InsertOne().
+			SetMode(options.InsertIgnore),
+	); err != nil {
+		return nil, err
+	}
+
+	revision, err := w.getRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w.revision = revision
+
+	var c context.Context
+	c, w.cancel = context.WithCancel(ctx)
+	go w.poll(c)
+	return w, nil
+}
+
+// SetUpdateCallback : sets the callback function that the watcher will call when the policy has been changed by other instances.
+func (w *Watcher) SetUpdateCallback(cb func(string)) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callback = cb
+	return nil
+}
+
+// Update : increase the revision so the other instances will be notified, the revision of
+// the current instance will be updated as well, so the callback won't be called on itself.
+// It's a no-op when the watcher is linked to the adapter by `WithWatcher`, since the revision
+// has been increased within the transaction of the adapter write.
+func (w *Watcher) Update() error {
+	w.mu.Lock()
+	linked := w.linked
+	w.mu.Unlock()
+	if linked {
+		return nil
+	}
+
+	var revision int64
+	if err := runInTransaction(w.ctx, []*sqlike.Table{w.table}, func(ctx sqlike.SessionContext, tables []*sqlike.Table) error {
+		rev, err := increaseRevision(ctx, tables[0])
+		if err != nil {
+			return err
+		}
+		revision = rev
+		return nil
+	}); err != nil {
+		return err
+	}
+	w.setRevision(revision)
+	return nil
+}
+
+// link : the revision will be increased by the adapter
+func (w *Watcher) link() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.linked = true
+}
+
+// setRevision : set the revision of the current instance after the change is committed, it only
+// advances when the change is the next revision. Otherwise the other instances have changed the policy
+// in between, so it's left for `check` to catch up and call the callback.
+func (w *Watcher) setRevision(revision int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if revision == w.revision+1 {
+		w.revision = revision
+	}
+}
+
+// increaseRevision : increase the revision within the transaction and return the new revision
+func increaseRevision(ctx sqlike.SessionContext, table *sqlike.Table) (int64, error) {
+	if _, err := table.UpdateOne(
+		ctx,
+		actions.UpdateOne().
+			Where(
+				expr.Equal("ID", revisionID),
+			).
+			Set(
+				expr.ColumnValue("Revision", expr.Increment("Revision", 1)),
+				expr.ColumnValue("UpdatedAt", time.Now().UTC()),
+			),
+	); err != nil {
+		return 0, err
+	}
+
+	// the row is locked by the update, so it's the revision of this transaction
+	var rev Revision
+	if err := table.FindOne(
+		ctx,
+		actions.FindOne().
+			Where(
+				expr.Equal("ID", revisionID),
+			),
+	).Decode(&rev); err != nil {
+		return 0, err
+	}
+	return rev.Revision, nil
+}
+
+// Close : stop polling, the callback function will not be called any more.
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		w.cancel()
+		<-w.done
+	})
+}
+
+func (w *Watcher) poll(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.check(ctx); err != nil && ctx.Err() == nil && w.opts.OnError != nil {
+				w.opts.OnError(err)
+			}
+		}
+	}
+}
+
+func (w *Watcher) check(ctx context.Context) error {
+	revision, err := w.getRevision(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	if revision == w.revision {
+		w.mu.Unlock()
+		return nil
+	}
+	w.revision = revision
+	cb := w.callback
+	w.mu.Unlock()
+
+	if cb != nil {
+		cb(strconv.FormatInt(revision, 10))
+	}
+	return nil
+}
+
+func (w *Watcher) getRevision(ctx context.Context) (int64, error) {
+	var rev Revision
+	if err := w.table.FindOne(
+		ctx,
+		actions.FindOne().
+			Where(
+				expr.Equal("ID", revisionID),
+			),
+	).Decode(&rev); err != nil {
+		return 0, err
+	}
+	return rev.Revision, nil
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+)
+
+// ConnBeginTx :
+func (mi *MetricsInterceptor) ConnBeginTx(ctx context.Context, conn driver.ConnBeginTx, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &timedTx{Tx: tx, start: start}, nil
+}
+
+// ConnExecContext :
+func (mi *MetricsInterceptor) ConnExecContext(ctx context.Context, conn driver.ExecerContext, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	start := time.Now()
+	result, err = conn.ExecContext(ctx, query, args)
+	mi.observe(kindExec, query, start, err)
+	return
+}
+
+// ConnQueryContext :
+func (mi *MetricsInterceptor) ConnQueryContext(ctx context.Context, conn driver.QueryerContext, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	start := time.Now()
+	rows, err = conn.QueryContext(ctx, query, args)
+	operation, table := mi.observe(kindQuery, query, start, err)
+	if err != nil {
+		return
+	}
+	rows = wrapRows(rows, operation, table)
+	return
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+)
+
+const (
+	kindQuery = "query"
+	kindExec  = "exec"
+)
+
+func (mi *MetricsInterceptor) observe(kind, query string, start time.Time, err error) (operation, table string) {
+	// we didn't want to record driver.ErrSkip, because the native sql package will retry with another way
+	operation, table = instrumented.ParseQuery(query)
+	if err == driver.ErrSkip {
+		return
+	}
+	mi.statements.WithLabelValues(kind, operation, table).Inc()
+	mi.latency.WithLabelValues(kind, operation, table).Observe(time.Since(start).Seconds())
+	if err != nil {
+		mi.errors.WithLabelValues(kind, operation, table).Inc()
+	}
+	return
+}
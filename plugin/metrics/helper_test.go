@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type mockExecer struct {
+	err error
+}
+
+func (m mockExecer) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type mockRows struct {
+	n int
+}
+
+func (m *mockRows) Columns() []string                         { return []string{"a"} }
+func (m *mockRows) Close() error                              { return nil }
+func (m *mockRows) HasNextResultSet() bool                    { return false }
+func (m *mockRows) NextResultSet() error                      { return io.EOF }
+func (m *mockRows) ColumnTypeScanType(index int) reflect.Type { return reflect.TypeOf("") }
+func (m *mockRows) Next(dest []driver.Value) error {
+	if m.n <= 0 {
+		return io.EOF
+	}
+	m.n--
+	return nil
+}
+
+type mockQueryer struct{}
+
+func (mockQueryer) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &mockRows{n: 3}, nil
+}
+
+type mockTx struct{}
+
+func (mockTx) Commit() error   { return nil }
+func (mockTx) Rollback() error { return nil }
+
+type mockBeginner struct{}
+
+func (mockBeginner) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return mockTx{}, nil
+}
+
+func TestInterceptor(t *testing.T) {
+	ctx := context.Background()
+	mi := NewInterceptor(WithNamespace("test"))
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(mi))
+
+	_, err := mi.ConnExecContext(ctx, mockExecer{}, "INSERT INTO `db`.`User` (`a`) VALUES (?);", nil)
+	require.NoError(t, err)
+	_, err = mi.ConnExecContext(ctx, mockExecer{err: errors.New("duplicate")}, "INSERT INTO `db`.`User` (`a`) VALUES (?);", nil)
+	require.Error(t, err)
+	_, err = mi.ConnExecContext(ctx, mockExecer{err: driver.ErrSkip}, "INSERT INTO `db`.`User` (`a`) VALUES (?);", nil)
+	require.Equal(t, driver.ErrSkip, err)
+
+	rows, err := mi.ConnQueryContext(ctx, mockQueryer{}, "SELECT * FROM `db`.`User`;", nil)
+	require.NoError(t, err)
+	for {
+		if err := mi.RowsNext(ctx, rows, nil); err != nil {
+			require.Equal(t, io.EOF, err)
+			break
+		}
+	}
+
+	tx, err := mi.ConnBeginTx(ctx, mockBeginner{}, driver.TxOptions{})
+	require.NoError(t, err)
+	require.NoError(t, mi.TxCommit(ctx, tx))
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(`
+# HELP test_statements_total Total number of query and exec statements sent to the database.
+# TYPE test_statements_total counter
+test_statements_total{kind="exec",operation="INSERT",table="User"} 2
+test_statements_total{kind="query",operation="SELECT",table="User"} 1
+# HELP test_statement_errors_total Total number of query and exec statements which returned an error.
+# TYPE test_statement_errors_total counter
+test_statement_errors_total{kind="exec",operation="INSERT",table="User"} 1
+# HELP test_rows_returned_total Total number of rows returned by query statements.
+# TYPE test_rows_returned_total counter
+test_rows_returned_total{operation="SELECT",table="User"} 3
+`), "test_statements_total", "test_statement_errors_total", "test_rows_returned_total"))
+	require.Equal(t, 1, testutil.CollectAndCount(mi.txDuration))
+}
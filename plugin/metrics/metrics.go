@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"database/sql"
+	"sync"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsOptions :
+type MetricsOptions struct {
+	// Namespace is the prefix of every metric name, default is `sqlike`
+	Namespace string
+
+	// Subsystem is the middle part of every metric name, it's optional
+	Subsystem string
+
+	// Buckets is the latency histogram buckets in seconds
+	Buckets []float64
+
+	// ConstLabels will attach to every metric, such as service name
+	ConstLabels prometheus.Labels
+}
+
+// MetricsOption :
+type MetricsOption func(*MetricsOptions)
+
+// StatsGetter : is the connection pool source, *sql.DB and *sqlike.Client satisfy this interface
+type StatsGetter interface {
+	Stats() sql.DBStats
+}
+
+// MetricsInterceptor : is an interceptor which record the database metrics, it implements
+// `prometheus.Collector` so you can register it into any prometheus registry
+type MetricsInterceptor struct {
+	opts MetricsOptions
+	instrumented.NullInterceptor
+
+	statements *prometheus.CounterVec
+	errors     *prometheus.CounterVec
+	latency    *prometheus.HistogramVec
+	rows       *prometheus.CounterVec
+	txDuration *prometheus.HistogramVec
+
+	mu    sync.RWMutex
+	pools map[string]StatsGetter
+	pool  poolDescs
+}
+
+var (
+	_ instrumented.Interceptor = (*MetricsInterceptor)(nil)
+	_ prometheus.Collector     = (*MetricsInterceptor)(nil)
+)
+
+// NewInterceptor :
+func NewInterceptor(opts ...MetricsOption) *MetricsInterceptor {
+	it := new(MetricsInterceptor)
+	it.opts.Namespace = "sqlike"
+	it.opts.Buckets = prometheus.DefBuckets
+	it.pools = make(map[string]StatsGetter)
+	for _, opt := range opts {
+		opt(&it.opts)
+	}
+
+	ns, sub, labels := it.opts.Namespace, it.opts.Subsystem, it.opts.ConstLabels
+	it.statements = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   ns,
+		Subsystem:   sub,
+		Name:        "statements_total",
+		Help:        "Total number of query and exec statements sent to the database.",
+		ConstLabels: labels,
+	}, []string{"kind", "operation", "table"})
+	it.errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   ns,
+		Subsystem:   sub,
+		Name:        "statement_errors_total",
+		Help:        "Total number of query and exec statements which returned an error.",
+		ConstLabels: labels,
+	}, []string{"kind", "operation", "table"})
+	it.latency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   ns,
+		Subsystem:   sub,
+		Name:        "statement_duration_seconds",
+		Help:        "Latency of query and exec statements in seconds.",
+		ConstLabels: labels,
+		Buckets:     it.opts.Buckets,
+	}, []string{"kind", "operation", "table"})
+	it.rows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   ns,
+		Subsystem:   sub,
+		Name:        "rows_returned_total",
+		Help:        "Total number of rows returned by query statements.",
+		ConstLabels: labels,
+	}, []string{"operation", "table"})
+	it.txDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:   ns,
+		Subsystem:   sub,
+		Name:        "transaction_duration_seconds",
+		Help:        "Duration of transactions from begin until commit or rollback in seconds.",
+		ConstLabels: labels,
+		Buckets:     it.opts.Buckets,
+	}, []string{"status"})
+	it.pool = newPoolDescs(ns, sub, labels)
+	return it
+}
+
+// WatchPool : register the connection pool which the stats will be reported on every collect,
+// the pool will be labelled with the `db` name
+func (mi *MetricsInterceptor) WatchPool(name string, db StatsGetter) *MetricsInterceptor {
+	if db == nil {
+		panic("sqlike: pool cannot be nil")
+	}
+	mi.mu.Lock()
+	defer mi.mu.Unlock()
+	mi.pools[name] = db
+	return mi
+}
+
+// Describe : implements prometheus.Collector
+func (mi *MetricsInterceptor) Describe(ch chan<- *prometheus.Desc) {
+	mi.statements.Describe(ch)
+	mi.errors.Describe(ch)
+	mi.latency.Describe(ch)
+	mi.rows.Describe(ch)
+	mi.txDuration.Describe(ch)
+	mi.pool.describe(ch)
+}
+
+// Collect : implements prometheus.Collector
+func (mi *MetricsInterceptor) Collect(ch chan<- prometheus.Metric) {
+	mi.statements.Collect(ch)
+	mi.errors.Collect(ch)
+	mi.latency.Collect(ch)
+	mi.rows.Collect(ch)
+	mi.txDuration.Collect(ch)
+
+	mi.mu.RLock()
+	defer mi.mu.RUnlock()
+	for name, db := range mi.pools {
+		mi.pool.collect(ch, name, db.Stats())
+	}
+}
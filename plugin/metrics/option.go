@@ -0,0 +1,31 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// WithNamespace :
+func WithNamespace(ns string) MetricsOption {
+	return func(opt *MetricsOptions) {
+		opt.Namespace = ns
+	}
+}
+
+// WithSubsystem :
+func WithSubsystem(sub string) MetricsOption {
+	return func(opt *MetricsOptions) {
+		opt.Subsystem = sub
+	}
+}
+
+// WithBuckets :
+func WithBuckets(buckets []float64) MetricsOption {
+	return func(opt *MetricsOptions) {
+		opt.Buckets = buckets
+	}
+}
+
+// WithConstLabels :
+func WithConstLabels(labels prometheus.Labels) MetricsOption {
+	return func(opt *MetricsOptions) {
+		opt.ConstLabels = labels
+	}
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type poolDescs struct {
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxIdleTimeClosed *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+func newPoolDescs(ns, sub string, labels prometheus.Labels) poolDescs {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(ns, sub, name),
+			help,
+			[]string{"db"},
+			labels,
+		)
+	}
+	return poolDescs{
+		maxOpen:           desc("pool_max_open_connections", "Maximum number of open connections to the database."),
+		open:              desc("pool_open_connections", "The number of established connections both in use and idle."),
+		inUse:             desc("pool_in_use_connections", "The number of connections currently in use."),
+		idle:              desc("pool_idle_connections", "The number of idle connections."),
+		waitCount:         desc("pool_wait_count_total", "The total number of connections waited for."),
+		waitDuration:      desc("pool_wait_duration_seconds_total", "The total time blocked waiting for a new connection."),
+		maxIdleClosed:     desc("pool_max_idle_closed_total", "The total number of connections closed due to SetMaxIdleConns."),
+		maxIdleTimeClosed: desc("pool_max_idle_time_closed_total", "The total number of connections closed due to SetConnMaxIdleTime."),
+		maxLifetimeClosed: desc("pool_max_lifetime_closed_total", "The total number of connections closed due to SetConnMaxLifetime."),
+	}
+}
+
+func (p poolDescs) describe(ch chan<- *prometheus.Desc) {
+	ch <- p.maxOpen
+	ch <- p.open
+	ch <- p.inUse
+	ch <- p.idle
+	ch <- p.waitCount
+	ch <- p.waitDuration
+	ch <- p.maxIdleClosed
+	ch <- p.maxIdleTimeClosed
+	ch <- p.maxLifetimeClosed
+}
+
+func (p poolDescs) collect(ch chan<- prometheus.Metric, name string, stats sql.DBStats) {
+	ch <- prometheus.MustNewConstMetric(p.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections), name)
+	ch <- prometheus.MustNewConstMetric(p.open, prometheus.GaugeValue, float64(stats.OpenConnections), name)
+	ch <- prometheus.MustNewConstMetric(p.inUse, prometheus.GaugeValue, float64(stats.InUse), name)
+	ch <- prometheus.MustNewConstMetric(p.idle, prometheus.GaugeValue, float64(stats.Idle), name)
+	ch <- prometheus.MustNewConstMetric(p.waitCount, prometheus.CounterValue, float64(stats.WaitCount), name)
+	ch <- prometheus.MustNewConstMetric(p.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds(), name)
+	ch <- prometheus.MustNewConstMetric(p.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed), name)
+	ch <- prometheus.MustNewConstMetric(p.maxIdleTimeClosed, prometheus.CounterValue, float64(stats.MaxIdleTimeClosed), name)
+	ch <- prometheus.MustNewConstMetric(p.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed), name)
+}
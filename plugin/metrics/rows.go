@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+)
+
+// labelledRows : carry the statement labels so RowsNext able to count the returned rows
+type labelledRows struct {
+	instrumented.Rows
+	operation string
+	table     string
+}
+
+func wrapRows(rows driver.Rows, operation, table string) driver.Rows {
+	x, ok := rows.(instrumented.Rows)
+	if !ok {
+		return rows
+	}
+	return &labelledRows{Rows: x, operation: operation, table: table}
+}
+
+// RowsNext :
+func (mi *MetricsInterceptor) RowsNext(ctx context.Context, rows driver.Rows, dest []driver.Value) error {
+	err := rows.Next(dest)
+	x, ok := rows.(*labelledRows)
+	if !ok {
+		return err
+	}
+	switch err {
+	case nil:
+		mi.rows.WithLabelValues(x.operation, x.table).Inc()
+	case io.EOF:
+	default:
+		mi.errors.WithLabelValues(kindQuery, x.operation, x.table).Inc()
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package metrics
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+)
+
+// StmtExecContext :
+func (mi *MetricsInterceptor) StmtExecContext(ctx context.Context, conn driver.StmtExecContext, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	start := time.Now()
+	result, err = conn.ExecContext(ctx, args)
+	mi.observe(kindExec, query, start, err)
+	return
+}
+
+// StmtQueryContext :
+func (mi *MetricsInterceptor) StmtQueryContext(ctx context.Context, conn driver.StmtQueryContext, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	start := time.Now()
+	rows, err = conn.QueryContext(ctx, args)
+	operation, table := mi.observe(kindQuery, query, start, err)
+	if err != nil {
+		return
+	}
+	rows = wrapRows(rows, operation, table)
+	return
+}
@@ -0,0 +1,38 @@
+package metrics
+
+import (
+	"context"
+	"database/sql/driver"
+	"time"
+)
+
+// timedTx : remember when the transaction begin, so we can observe the duration on commit or rollback
+type timedTx struct {
+	driver.Tx
+	start time.Time
+}
+
+// TxCommit :
+func (mi *MetricsInterceptor) TxCommit(ctx context.Context, tx driver.Tx) error {
+	err := tx.Commit()
+	mi.observeTx(tx, "commit", err)
+	return err
+}
+
+// TxRollback :
+func (mi *MetricsInterceptor) TxRollback(ctx context.Context, tx driver.Tx) error {
+	err := tx.Rollback()
+	mi.observeTx(tx, "rollback", err)
+	return err
+}
+
+func (mi *MetricsInterceptor) observeTx(tx driver.Tx, status string, err error) {
+	x, ok := tx.(*timedTx)
+	if !ok {
+		return
+	}
+	if err != nil {
+		status += "_error"
+	}
+	mi.txDuration.WithLabelValues(status).Observe(time.Since(x.start).Seconds())
+}
@@ -0,0 +1,93 @@
+package otel
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ConnPing :
+func (ot *OpenTelemetryInterceptor) ConnPing(ctx context.Context, conn driver.Pinger) (err error) {
+	if ot.opts.Ping {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, conn, "ping")
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	err = conn.Ping(ctx)
+	return
+}
+
+// ConnBeginTx :
+func (ot *OpenTelemetryInterceptor) ConnBeginTx(ctx context.Context, conn driver.ConnBeginTx, opts driver.TxOptions) (driver.Tx, error) {
+	if !ot.opts.BeginTx {
+		return conn.BeginTx(ctx, opts)
+	}
+
+	span, ctx := ot.StartSpan(ctx, conn, "transaction")
+	tx, err := conn.BeginTx(ctx, opts)
+	if err != nil {
+		ot.endSpan(span, err)
+		return nil, err
+	}
+
+	x := &tracedTx{Tx: tx, span: span}
+	if key, ok := connKey(conn); ok {
+		x.key = key
+		ot.txs.Store(key, span.SpanContext())
+	}
+	return x, nil
+}
+
+// ConnPrepareContext :
+func (ot *OpenTelemetryInterceptor) ConnPrepareContext(ctx context.Context, conn driver.ConnPrepareContext, query string) (stmt driver.Stmt, err error) {
+	if ot.opts.Prepare {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, conn, "prepare")
+		ot.setQuery(span, query)
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	stmt, err = conn.PrepareContext(ctx, query)
+	if err != nil {
+		return
+	}
+	if x, ok := stmt.(instrumented.Stmt); ok {
+		if key, ok := connKey(conn); ok {
+			stmt = &tracedStmt{Stmt: x, conn: key}
+		}
+	}
+	return
+}
+
+// ConnExecContext :
+func (ot *OpenTelemetryInterceptor) ConnExecContext(ctx context.Context, conn driver.ExecerContext, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	if ot.opts.Exec {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, conn, "exec")
+		ot.setQueryArgs(span, query, args)
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	result, err = conn.ExecContext(ctx, query, args)
+	return
+}
+
+// ConnQueryContext :
+func (ot *OpenTelemetryInterceptor) ConnQueryContext(ctx context.Context, conn driver.QueryerContext, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	if ot.opts.Query {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, conn, "query")
+		ot.setQueryArgs(span, query, args)
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	rows, err = conn.QueryContext(ctx, query, args)
+	return
+}
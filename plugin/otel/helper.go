@@ -0,0 +1,102 @@
+package otel
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+	"github.com/Oskang09/sqlike/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DBStatementArgsKey : is the attribute key of the statement arguments
+const DBStatementArgsKey = attribute.Key("db.statement.args")
+
+// Redactor : return the value which going to be recorded for the argument,
+// the `ordinal` is the position of the argument starting from 1
+type Redactor func(query string, ordinal int, value driver.Value) driver.Value
+
+// RedactAll : redact every argument of the statement
+func RedactAll(query string, ordinal int, value driver.Value) driver.Value {
+	return "[REDACTED]"
+}
+
+// RedactOrdinals : redact the arguments on the selected positions, the position is starting from 1
+func RedactOrdinals(ordinals ...int) Redactor {
+	return func(query string, ordinal int, value driver.Value) driver.Value {
+		for _, o := range ordinals {
+			if o == ordinal {
+				return "[REDACTED]"
+			}
+		}
+		return value
+	}
+}
+
+func (ot *OpenTelemetryInterceptor) setQuery(span trace.Span, query string) {
+	operation, table := instrumented.ParseQuery(query)
+	attrs := []attribute.KeyValue{semconv.DBStatementKey.String(query)}
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationKey.String(operation))
+	}
+	if table != "" {
+		attrs = append(attrs, semconv.DBSQLTableKey.String(table))
+	}
+	span.SetAttributes(attrs...)
+}
+
+func (ot *OpenTelemetryInterceptor) setQueryArgs(span trace.Span, query string, args []driver.NamedValue) {
+	ot.setQuery(span, query)
+	if !ot.opts.Args || len(args) == 0 {
+		return
+	}
+
+	values := make([]string, len(args))
+	for i, arg := range args {
+		v := arg.Value
+		if ot.opts.Redactor != nil {
+			v = ot.opts.Redactor(query, arg.Ordinal, v)
+		}
+		values[i] = formatValue(v)
+	}
+	span.SetAttributes(DBStatementArgsKey.StringSlice(values))
+}
+
+func formatValue(it driver.Value) string {
+	switch v := it.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'e', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return util.UnsafeString(v)
+	case fmt.Stringer:
+		return v.String()
+	case nil:
+		return "NULL"
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func (ot *OpenTelemetryInterceptor) endSpan(span trace.Span, err error) {
+	// we didn't want to record driver.ErrSkip, because the native sql package will handle
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,96 @@
+package otel
+
+import "go.opentelemetry.io/otel/trace"
+
+// WithAllTraceOptions :
+func WithAllTraceOptions() TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Ping = true
+		opt.BeginTx = true
+		opt.TxCommit = true
+		opt.TxRollback = true
+		opt.Prepare = true
+		opt.Query = true
+		opt.Exec = true
+		opt.Args = true
+	}
+}
+
+// WithTracerProvider :
+func WithTracerProvider(provider trace.TracerProvider) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.TracerProvider = provider
+	}
+}
+
+// WithDBSystem :
+func WithDBSystem(system string) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.DBSystem = system
+	}
+}
+
+// WithDBName :
+func WithDBName(name string) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.DBName = name
+	}
+}
+
+// WithDBUser :
+func WithDBUser(user string) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.DBUser = user
+	}
+}
+
+// WithPing :
+func WithPing(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Ping = flag
+	}
+}
+
+// WithPrepare :
+func WithPrepare(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Prepare = flag
+	}
+}
+
+// WithExec :
+func WithExec(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Exec = flag
+	}
+}
+
+// WithQuery :
+func WithQuery(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Query = flag
+	}
+}
+
+// WithTransaction : trace the begin, commit and rollback of transaction
+func WithTransaction(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.BeginTx = flag
+		opt.TxCommit = flag
+		opt.TxRollback = flag
+	}
+}
+
+// WithArgs :
+func WithArgs(flag bool) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Args = flag
+	}
+}
+
+// WithRedactor : set the redactor for arguments, it only take effect when `Args` is true
+func WithRedactor(redactor Redactor) TraceOption {
+	return func(opt *TraceOptions) {
+		opt.Redactor = redactor
+	}
+}
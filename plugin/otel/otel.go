@@ -0,0 +1,121 @@
+package otel
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+	gotel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Oskang09/sqlike/plugin/otel"
+
+// TraceOptions :
+type TraceOptions struct {
+	// TracerProvider is the provider to create tracer, default is the global provider
+	TracerProvider trace.TracerProvider
+
+	// DBSystem is the database management system
+	// db.system: value
+	DBSystem string
+
+	// DBName is the database name
+	// db.name: value
+	DBName string
+
+	// DBUser is the database user
+	// db.user: value
+	DBUser string
+
+	// Ping is a flag to trace the ping
+	Ping bool
+
+	// Prepare is a flag to trace the prepare stmt
+	Prepare bool
+
+	// when Query is true, it will trace all the query statement
+	Query bool
+
+	// when Exec is true, it will trace all the exec statement
+	Exec       bool
+	BeginTx    bool
+	TxCommit   bool
+	TxRollback bool
+
+	// when Args is true, it will record all the arguments of the statement
+	Args bool
+
+	// Redactor will be called on every argument before it's recorded
+	Redactor Redactor
+}
+
+// TraceOption :
+type TraceOption func(*TraceOptions)
+
+// OpenTelemetryInterceptor :
+type OpenTelemetryInterceptor struct {
+	opts   TraceOptions
+	tracer trace.Tracer
+	attrs  []attribute.KeyValue
+	instrumented.NullInterceptor
+
+	// txs keep the span context of active transaction for each connection,
+	// so the statements executed within the transaction able to link to it
+	txs sync.Map
+}
+
+var _ instrumented.Interceptor = (*OpenTelemetryInterceptor)(nil)
+
+// NewInterceptor :
+func NewInterceptor(opts ...TraceOption) instrumented.Interceptor {
+	it := new(OpenTelemetryInterceptor)
+	it.opts.DBSystem = semconv.DBSystemMySQL.Value.AsString()
+	for _, opt := range opts {
+		opt(&it.opts)
+	}
+	if it.opts.TracerProvider == nil {
+		it.opts.TracerProvider = gotel.GetTracerProvider()
+	}
+	it.tracer = it.opts.TracerProvider.Tracer(instrumentationName)
+	it.attrs = append(it.attrs, semconv.DBSystemKey.String(it.opts.DBSystem))
+	if it.opts.DBName != "" {
+		it.attrs = append(it.attrs, semconv.DBNameKey.String(it.opts.DBName))
+	}
+	if it.opts.DBUser != "" {
+		it.attrs = append(it.attrs, semconv.DBUserKey.String(it.opts.DBUser))
+	}
+	return it
+}
+
+// StartSpan : start a client span with the common database attributes, the span will link
+// to the active transaction of the connection if there is any
+func (ot *OpenTelemetryInterceptor) StartSpan(ctx context.Context, conn interface{}, operationName string) (trace.Span, context.Context) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(ot.attrs...),
+	}
+	if key, ok := connKey(conn); ok {
+		if sc, ok := ot.txs.Load(key); ok {
+			opts = append(opts, trace.WithLinks(trace.Link{
+				SpanContext: sc.(trace.SpanContext),
+			}))
+		}
+	}
+	ctx, span := ot.tracer.Start(ctx, operationName, opts...)
+	return span, ctx
+}
+
+// connKey : only comparable connection able to be used as a map key
+func connKey(conn interface{}) (interface{}, bool) {
+	if conn == nil {
+		return nil, false
+	}
+	if !reflect.TypeOf(conn).Comparable() {
+		return nil, false
+	}
+	return conn, true
+}
@@ -0,0 +1,187 @@
+package otel
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/Oskang09/sqlike/plugin/otel/oteltest"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+)
+
+type mockConn struct {
+	err error
+}
+
+func (c *mockConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *mockConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return &mockStmt{conn: c}, nil
+}
+
+func (c *mockConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	return mockTx{}, nil
+}
+
+type mockStmt struct {
+	conn *mockConn
+}
+
+func (s *mockStmt) Close() error  { return nil }
+func (s *mockStmt) NumInput() int { return -1 }
+func (s *mockStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+func (s *mockStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+func (s *mockStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, "", args)
+}
+func (s *mockStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+type mockTx struct{}
+
+func (mockTx) Commit() error   { return nil }
+func (mockTx) Rollback() error { return nil }
+
+func attrValue(attrs []attribute.KeyValue, key attribute.Key) (attribute.Value, bool) {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestExec(t *testing.T) {
+	ctx := context.Background()
+	exporter := oteltest.NewExporter()
+	provider := exporter.TracerProvider()
+	itpr := NewInterceptor(
+		WithTracerProvider(provider),
+		WithDBName("sqlike"),
+		WithExec(true),
+		WithArgs(true),
+		WithRedactor(RedactOrdinals(2)),
+	)
+
+	query := "INSERT INTO `sqlike`.`User` (`Name`,`Password`) VALUES (?,?);"
+	_, err := itpr.ConnExecContext(ctx, &mockConn{}, query, []driver.NamedValue{
+		{Ordinal: 1, Value: "John"},
+		{Ordinal: 2, Value: "secret"},
+	})
+	require.NoError(t, err)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	attrs := spans[0].Attributes()
+	v, _ := attrValue(attrs, semconv.DBSystemKey)
+	require.Equal(t, "mysql", v.AsString())
+	v, _ = attrValue(attrs, semconv.DBNameKey)
+	require.Equal(t, "sqlike", v.AsString())
+	v, _ = attrValue(attrs, semconv.DBStatementKey)
+	require.Equal(t, query, v.AsString())
+	v, _ = attrValue(attrs, semconv.DBOperationKey)
+	require.Equal(t, "INSERT", v.AsString())
+	v, _ = attrValue(attrs, semconv.DBSQLTableKey)
+	require.Equal(t, "User", v.AsString())
+	v, _ = attrValue(attrs, DBStatementArgsKey)
+	require.Equal(t, []string{"John", "[REDACTED]"}, v.AsStringSlice())
+
+	exporter.Reset()
+	_, err = itpr.ConnExecContext(ctx, &mockConn{err: errors.New("duplicate")}, query, nil)
+	require.Error(t, err)
+	spans = exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	_, ok := attrValue(spans[0].Attributes(), DBStatementArgsKey)
+	require.False(t, ok)
+
+	exporter.Reset()
+	_, err = itpr.ConnExecContext(ctx, &mockConn{err: driver.ErrSkip}, query, nil)
+	require.Equal(t, driver.ErrSkip, err)
+	spans = exporter.Spans()
+	require.Len(t, spans, 1)
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+}
+
+func TestTransaction(t *testing.T) {
+	ctx := context.Background()
+	exporter := oteltest.NewExporter()
+	provider := exporter.TracerProvider()
+	itpr := NewInterceptor(
+		WithTracerProvider(provider),
+		WithExec(true),
+		WithTransaction(true),
+	)
+
+	conn := &mockConn{}
+	tx, err := itpr.ConnBeginTx(ctx, conn, driver.TxOptions{})
+	require.NoError(t, err)
+	_, err = itpr.ConnExecContext(ctx, conn, "UPDATE `User` SET `Name` = ?;", nil)
+	require.NoError(t, err)
+	require.NoError(t, itpr.TxCommit(ctx, tx))
+
+	// statement after the transaction shouldn't link to it anymore
+	_, err = itpr.ConnExecContext(ctx, conn, "UPDATE `User` SET `Name` = ?;", nil)
+	require.NoError(t, err)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 4)
+	exec, commit, txSpan, after := spans[0], spans[1], spans[2], spans[3]
+	require.Equal(t, "exec", exec.Name())
+	require.Equal(t, "tx_commit", commit.Name())
+	require.Equal(t, "transaction", txSpan.Name())
+	require.Len(t, exec.Links(), 1)
+	require.Equal(t, txSpan.SpanContext(), exec.Links()[0].SpanContext)
+	require.Equal(t, txSpan.SpanContext().SpanID(), commit.Parent().SpanID())
+	require.Len(t, after.Links(), 0)
+}
+
+func TestStmtTransaction(t *testing.T) {
+	ctx := context.Background()
+	exporter := oteltest.NewExporter()
+	itpr := NewInterceptor(
+		WithTracerProvider(exporter.TracerProvider()),
+		WithExec(true),
+		WithTransaction(true),
+	)
+
+	conn := &mockConn{}
+	tx, err := itpr.ConnBeginTx(ctx, conn, driver.TxOptions{})
+	require.NoError(t, err)
+	stmt, err := itpr.ConnPrepareContext(ctx, conn, "UPDATE `User` SET `Name` = ?;")
+	require.NoError(t, err)
+	_, err = itpr.StmtExecContext(ctx, stmt.(driver.StmtExecContext), "UPDATE `User` SET `Name` = ?;", nil)
+	require.NoError(t, err)
+	require.NoError(t, itpr.TxRollback(ctx, tx))
+
+	// statement after the transaction shouldn't link to it anymore
+	_, err = itpr.StmtExecContext(ctx, stmt.(driver.StmtExecContext), "UPDATE `User` SET `Name` = ?;", nil)
+	require.NoError(t, err)
+	require.NoError(t, itpr.StmtClose(ctx, stmt))
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 4)
+	exec, txSpan, after := spans[0], spans[2], spans[3]
+	require.Equal(t, "stmt_exec", exec.Name())
+	require.Equal(t, "tx_rollback", spans[1].Name())
+	require.Equal(t, "transaction", txSpan.Name())
+	require.Len(t, exec.Links(), 1)
+	require.Equal(t, txSpan.SpanContext(), exec.Links()[0].SpanContext)
+	v, _ := attrValue(exec.Attributes(), semconv.DBSQLTableKey)
+	require.Equal(t, "User", v.AsString())
+	require.Len(t, after.Links(), 0)
+}
@@ -0,0 +1,57 @@
+// Package oteltest provides an in-memory span exporter, to verify the spans recorded by the interceptor in tests.
+package oteltest
+
+import (
+	"context"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Exporter : is an in-memory span exporter, the exported spans are kept until it's reset
+type Exporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+var _ sdktrace.SpanExporter = (*Exporter)(nil)
+
+// NewExporter :
+func NewExporter() *Exporter {
+	return new(Exporter)
+}
+
+// TracerProvider : return the tracer provider which export the ended spans to the exporter synchronously
+func (e *Exporter) TracerProvider() *sdktrace.TracerProvider {
+	return sdktrace.NewTracerProvider(sdktrace.WithSyncer(e))
+}
+
+// ExportSpans :
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+// Shutdown :
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.Reset()
+	return nil
+}
+
+// Spans : return the exported spans in the order of ended
+func (e *Exporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	spans := make([]sdktrace.ReadOnlySpan, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+// Reset : clear the exported spans
+func (e *Exporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
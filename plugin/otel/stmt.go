@@ -0,0 +1,36 @@
+package otel
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StmtExecContext :
+func (ot *OpenTelemetryInterceptor) StmtExecContext(ctx context.Context, conn driver.StmtExecContext, query string, args []driver.NamedValue) (result driver.Result, err error) {
+	if ot.opts.Exec {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, stmtConn(conn), "stmt_exec")
+		ot.setQueryArgs(span, query, args)
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	result, err = conn.ExecContext(ctx, args)
+	return
+}
+
+// StmtQueryContext :
+func (ot *OpenTelemetryInterceptor) StmtQueryContext(ctx context.Context, conn driver.StmtQueryContext, query string, args []driver.NamedValue) (rows driver.Rows, err error) {
+	if ot.opts.Query {
+		var span trace.Span
+		span, ctx = ot.StartSpan(ctx, stmtConn(conn), "stmt_query")
+		ot.setQueryArgs(span, query, args)
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	rows, err = conn.QueryContext(ctx, args)
+	return
+}
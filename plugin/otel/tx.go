@@ -0,0 +1,82 @@
+package otel
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/Oskang09/sqlike/sql/instrumented"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedTx : hold the transaction span, which will end on commit or rollback
+type tracedTx struct {
+	driver.Tx
+	key  interface{}
+	span trace.Span
+}
+
+// tracedStmt : hold the connection which prepared the statement, so the statement
+// executed within the transaction able to link to it
+type tracedStmt struct {
+	instrumented.Stmt
+	conn interface{}
+}
+
+// stmtConn : return the connection which prepared the statement, it's nil if it's unknown
+func stmtConn(stmt interface{}) interface{} {
+	if x, ok := stmt.(*tracedStmt); ok {
+		return x.conn
+	}
+	return nil
+}
+
+// TxCommit :
+func (ot *OpenTelemetryInterceptor) TxCommit(ctx context.Context, tx driver.Tx) (err error) {
+	defer func() {
+		ot.endTx(tx, err)
+	}()
+	if ot.opts.TxCommit {
+		var span trace.Span
+		span, _ = ot.StartSpan(ot.txContext(ctx, tx), nil, "tx_commit")
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	err = tx.Commit()
+	return
+}
+
+// TxRollback :
+func (ot *OpenTelemetryInterceptor) TxRollback(ctx context.Context, tx driver.Tx) (err error) {
+	defer func() {
+		ot.endTx(tx, err)
+	}()
+	if ot.opts.TxRollback {
+		var span trace.Span
+		span, _ = ot.StartSpan(ot.txContext(ctx, tx), nil, "tx_rollback")
+		defer func() {
+			ot.endSpan(span, err)
+		}()
+	}
+	err = tx.Rollback()
+	return
+}
+
+// txContext : make the commit and rollback span as a child of the transaction span
+func (ot *OpenTelemetryInterceptor) txContext(ctx context.Context, tx driver.Tx) context.Context {
+	if x, ok := tx.(*tracedTx); ok {
+		return trace.ContextWithSpan(ctx, x.span)
+	}
+	return ctx
+}
+
+func (ot *OpenTelemetryInterceptor) endTx(tx driver.Tx, err error) {
+	x, ok := tx.(*tracedTx)
+	if !ok {
+		return
+	}
+	if x.key != nil {
+		ot.txs.Delete(x.key)
+	}
+	ot.endSpan(x.span, err)
+}
@@ -368,5 +368,9 @@ func parseTag(f reflect.StructField, tagName string, fmtFunc FormatFunc) (st Str
 			st.opts[opt] = ""
 		}
 	}
+	// inline json schema contains comma, so it has it own tag
+	if v, ok := f.Tag.Lookup("json_schema"); ok {
+		st.opts["json_schema"] = strings.TrimSpace(v)
+	}
 	return
 }
@@ -76,6 +76,17 @@ func TestStructTag(t *testing.T) {
 		require.Equal(t, "20", v)
 		require.True(t, ok)
 	}
+
+	// json schema tag
+	{
+		f, _ := reflect.TypeOf(struct {
+			Tags []string `sqlike:"tags" json_schema:"{\"type\":\"array\",\"maxItems\":2}"`
+		}{}).FieldByName("Tags")
+		tag = parseTag(f, "sqlike", nil)
+		v, ok = tag.LookUp("json_schema")
+		require.Equal(t, `{"type":"array","maxItems":2}`, v)
+		require.True(t, ok)
+	}
 }
 
 func TestStructField(t *testing.T) {
@@ -1,5 +1,7 @@
 package charset
 
+import "strings"
+
 // Code :
 type Code string
 
@@ -36,3 +38,39 @@ const (
 	Binary   Code = "binary"   // Binary pseudo charset
 	EUCJPMS  Code = "eucjpms"  // UJIS for Windows Japanese
 )
+
+var collations = map[Code]string{
+	UTF8MB4: "utf8mb4_unicode_ci",
+	Latin1:  "latin1_bin",
+}
+
+// DefaultCollation : the collation which is used when only the character set is declared,
+// it's empty if it's unknown and the server default will be used
+func DefaultCollation(charset string) string {
+	return collations[Code(charset)]
+}
+
+// FromCollation : the collation name always starts with its character set, except `binary`
+func FromCollation(collation string) string {
+	if i := strings.IndexByte(collation, '_'); i > 0 {
+		return collation[:i]
+	}
+	return collation
+}
+
+// Resolve : the character set and collation of the declared `charset` and `collate`, the empty
+// one is not declared. The character set is derived from the collation if only `collate` is declared.
+func Resolve(charset, collation string) (string, string) {
+	cs, cl := string(UTF8MB4), DefaultCollation(string(UTF8MB4))
+	if charset != "" {
+		cs = strings.ToLower(charset)
+		cl = DefaultCollation(cs)
+	}
+	if collation != "" {
+		cl = strings.ToLower(collation)
+		if charset == "" {
+			cs = FromCollation(cl)
+		}
+	}
+	return cs, cl
+}
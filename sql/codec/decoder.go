@@ -27,7 +27,8 @@ import (
 
 // DefaultDecoders :
 type DefaultDecoders struct {
-	codec *Registry
+	codec Codecer
+	json  *jsonb.Codec
 }
 
 // DecodeByte :
@@ -534,7 +535,7 @@ func (dec *DefaultDecoders) DecodeStruct(it interface{}, v reflect.Value) error
 	case []byte:
 		b = vi
 	}
-	return jsonb.UnmarshalValue(b, v)
+	return jsonCodec(dec.json).UnmarshalValue(b, v)
 }
 
 // DecodeArray :
@@ -546,7 +547,7 @@ func (dec DefaultDecoders) DecodeArray(it interface{}, v reflect.Value) error {
 	case []byte:
 		b = vi
 	}
-	return jsonb.UnmarshalValue(b, v)
+	return jsonCodec(dec.json).UnmarshalValue(b, v)
 }
 
 // DecodeMap :
@@ -558,7 +559,7 @@ func (dec DefaultDecoders) DecodeMap(it interface{}, v reflect.Value) error {
 	case []byte:
 		b = vi
 	}
-	return jsonb.UnmarshalValue(b, v)
+	return jsonCodec(dec.json).UnmarshalValue(b, v)
 }
 
 func (dec DefaultDecoders) DecodeDatastoreKey(it interface{}, v reflect.Value) error {
@@ -23,7 +23,8 @@ import (
 
 // DefaultEncoders :
 type DefaultEncoders struct {
-	codec *Registry
+	codec Codecer
+	json  *jsonb.Codec
 }
 
 // EncodeByte :
@@ -42,16 +43,19 @@ func (enc DefaultEncoders) EncodeRawBytes(_ reflext.StructFielder, v reflect.Val
 }
 
 // EncodeJSONRaw :
-func (enc DefaultEncoders) EncodeJSONRaw(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
+func (enc DefaultEncoders) EncodeJSONRaw(sf reflext.StructFielder, v reflect.Value) (interface{}, error) {
 	if v.IsNil() {
-		return []byte("null"), nil
+		return encodeJSON(sf, []byte("null"))
 	}
 	buf := new(bytes.Buffer)
 	if err := json.Compact(buf, v.Bytes()); err != nil {
 		return nil, err
 	}
 	if buf.Len() == 0 {
-		return []byte(`{}`), nil
+		return encodeJSON(sf, []byte(`{}`))
+	}
+	if err := validateJSON(sf, buf.Bytes()); err != nil {
+		return nil, err
 	}
 	return json.RawMessage(buf.Bytes()), nil
 }
@@ -153,18 +157,29 @@ func (enc *DefaultEncoders) EncodePtr(sf reflext.StructFielder, v reflect.Value)
 }
 
 // EncodeStruct :
-func (enc DefaultEncoders) EncodeStruct(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
-	return jsonb.Marshal(v)
+func (enc DefaultEncoders) EncodeStruct(sf reflext.StructFielder, v reflect.Value) (interface{}, error) {
+	b, err := jsonCodec(enc.json).Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(sf, b)
 }
 
 // EncodeArray :
-func (enc DefaultEncoders) EncodeArray(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
-	return jsonb.Marshal(v)
+func (enc DefaultEncoders) EncodeArray(sf reflext.StructFielder, v reflect.Value) (interface{}, error) {
+	b, err := jsonCodec(enc.json).Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(sf, b)
 }
 
 // EncodeMap :
-func (enc DefaultEncoders) EncodeMap(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
+func (enc DefaultEncoders) EncodeMap(sf reflext.StructFielder, v reflect.Value) (interface{}, error) {
 	if v.IsNil() {
+		if err := validateJSON(sf, []byte("null")); err != nil {
+			return nil, err
+		}
 		return string("null"), nil
 	}
 
@@ -177,7 +192,11 @@ func (enc DefaultEncoders) EncodeMap(_ reflext.StructFielder, v reflect.Value) (
 	// if !isBaseType(k) {
 	// 	return nil, fmt.Errorf("codec: unsupported data type %q for map value", k.Kind())
 	// }
-	return jsonb.Marshal(v)
+	b, err := jsonCodec(enc.json).Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return encodeJSON(sf, b)
 }
 
 // func isBaseType(t reflect.Type) bool {
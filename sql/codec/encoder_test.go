@@ -1,9 +1,12 @@
 package codec
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/reflext"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,3 +50,53 @@ func TestEncodeMap(t *testing.T) {
 	}
 
 }
+
+func TestEncodeJSONSchema(t *testing.T) {
+	type address struct {
+		Line1    string
+		Postcode string
+	}
+	type entity struct {
+		Tags    []string          `json_schema:"{\"type\":\"array\",\"maxItems\":2}"`
+		Address address           `sqlike:",json_schema=codec.address"`
+		Extra   map[string]string `json_schema:"{\"type\":\"object\"}"`
+		Raw     json.RawMessage   `json_schema:"{\"type\":\"integer\"}"`
+	}
+
+	jsonb.MustRegisterSchema("codec.address", []byte(`{"required":["Postcode"],"properties":{"Postcode":{"pattern":"^[0-9]+$"}}}`))
+
+	var (
+		enc      = DefaultEncoders{}
+		cdc      = reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{}))
+		sf, _    = cdc.LookUpFieldByName("Tags")
+		addr, _  = cdc.LookUpFieldByName("Address")
+		extra, _ = cdc.LookUpFieldByName("Extra")
+		raw, _   = cdc.LookUpFieldByName("Raw")
+		it       interface{}
+		err      error
+	)
+
+	it, err = enc.EncodeArray(sf, reflect.ValueOf([]string{"a", "b"}))
+	require.NoError(t, err)
+	require.Equal(t, []byte(`["a","b"]`), it)
+
+	_, err = enc.EncodeArray(sf, reflect.ValueOf([]string{"a", "b", "c"}))
+	require.Error(t, err)
+	require.IsType(t, &jsonb.SchemaError{}, err)
+
+	_, err = enc.EncodeStruct(addr, reflect.ValueOf(address{Postcode: "123"}))
+	require.NoError(t, err)
+
+	_, err = enc.EncodeStruct(addr, reflect.ValueOf(address{Postcode: "abc"}))
+	require.Error(t, err)
+
+	_, err = enc.EncodeMap(extra, reflect.ValueOf(map[string]string(nil)))
+	require.Error(t, err)
+
+	it, err = enc.EncodeJSONRaw(raw, reflect.ValueOf(json.RawMessage(` 10 `)))
+	require.NoError(t, err)
+	require.Equal(t, json.RawMessage(`10`), it)
+
+	_, err = enc.EncodeJSONRaw(raw, reflect.ValueOf(json.RawMessage(`"10"`)))
+	require.Error(t, err)
+}
@@ -1,7 +1,44 @@
 package codec
 
-import "unsafe"
+import (
+	"unsafe"
+
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/reflext"
+)
 
 func b2s(b []byte) string {
 	return *(*string)(unsafe.Pointer(&b))
 }
+
+// jsonCodec : fallback to the default json codec
+func jsonCodec(jc *jsonb.Codec) *jsonb.Codec {
+	if jc == nil {
+		return jsonb.DefaultCodec()
+	}
+	return jc
+}
+
+// validateJSON : validate the json document with the `json_schema` tag of the field
+func validateJSON(sf reflext.StructFielder, b []byte) error {
+	if sf == nil {
+		return nil
+	}
+	v, ok := sf.Tag().LookUp("json_schema")
+	if !ok {
+		return nil
+	}
+	schema, err := jsonb.SchemaOf(v)
+	if err != nil {
+		return err
+	}
+	return schema.Validate(b)
+}
+
+// encodeJSON : return the json document if it's valid
+func encodeJSON(sf reflext.StructFielder, b []byte) (interface{}, error) {
+	if err := validateJSON(sf, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -11,6 +11,7 @@ import (
 
 	"cloud.google.com/go/civil"
 	"cloud.google.com/go/datastore"
+	"github.com/Oskang09/sqlike/jsonb"
 	"github.com/Oskang09/sqlike/reflext"
 	"github.com/Oskang09/sqlike/spatial"
 	"github.com/paulmach/orb"
@@ -36,9 +37,15 @@ var (
 )
 
 func buildDefaultRegistry() Codecer {
+	return NewDefaultRegistry(nil)
+}
+
+// NewDefaultRegistry : create the default registry with the json codec, the struct, array and map
+// will be encoded and decoded by the json codec, nil will fallback to `jsonb.DefaultCodec`
+func NewDefaultRegistry(jc *jsonb.Codec) Codecer {
 	rg := NewRegistry()
-	dec := DefaultDecoders{rg}
-	enc := DefaultEncoders{rg}
+	dec := DefaultDecoders{codec: rg, json: jc}
+	enc := DefaultEncoders{codec: rg, json: jc}
 
 	rg.RegisterTypeCodec(reflect.TypeOf([]byte{}), enc.EncodeByte, dec.DecodeByte)
 	rg.RegisterTypeCodec(reflect.TypeOf(language.Tag{}), enc.EncodeStringer, dec.DecodeLanguage)
@@ -74,13 +81,31 @@ func buildDefaultRegistry() Codecer {
 	rg.RegisterKindCodec(reflect.Float32, enc.EncodeFloat, dec.DecodeFloat)
 	rg.RegisterKindCodec(reflect.Float64, enc.EncodeFloat, dec.DecodeFloat)
 	rg.RegisterKindCodec(reflect.Ptr, enc.EncodePtr, dec.DecodePtr)
-	rg.RegisterKindCodec(reflect.Struct, enc.EncodeStruct, dec.DecodeStruct)
-	rg.RegisterKindCodec(reflect.Array, enc.EncodeArray, dec.DecodeArray)
-	rg.RegisterKindCodec(reflect.Slice, enc.EncodeArray, dec.DecodeArray)
-	rg.RegisterKindCodec(reflect.Map, enc.EncodeMap, dec.DecodeMap)
+	RegisterJSONCodec(rg, jc)
 	return rg
 }
 
+// RegisterJSONCodec : register the struct, array and map coders which encoded and decoded by the json codec,
+// the pointer coders are registered as well so the element is looked up from this registry, the other coders
+// of the registry are kept
+func RegisterJSONCodec(rg Codecer, jc *jsonb.Codec) {
+	dec := &DefaultDecoders{codec: rg, json: jc}
+	enc := &DefaultEncoders{codec: rg, json: jc}
+	for k, coder := range map[reflect.Kind]struct {
+		enc ValueEncoder
+		dec ValueDecoder
+	}{
+		reflect.Ptr:    {enc.EncodePtr, dec.DecodePtr},
+		reflect.Struct: {enc.EncodeStruct, dec.DecodeStruct},
+		reflect.Array:  {enc.EncodeArray, dec.DecodeArray},
+		reflect.Slice:  {enc.EncodeArray, dec.DecodeArray},
+		reflect.Map:    {enc.EncodeMap, dec.DecodeMap},
+	} {
+		rg.RegisterKindEncoder(k, coder.enc)
+		rg.RegisterKindDecoder(k, coder.dec)
+	}
+}
+
 // Registry :
 type Registry struct {
 	mutex        *sync.Mutex
@@ -103,6 +128,26 @@ func NewRegistry() *Registry {
 	}
 }
 
+// Clone : copy the registry, so the coders can be registered without affecting the original registry
+func (r *Registry) Clone() *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	rg := NewRegistry()
+	for t, enc := range r.typeEncoders {
+		rg.typeEncoders[t] = enc
+	}
+	for t, dec := range r.typeDecoders {
+		rg.typeDecoders[t] = dec
+	}
+	for k, enc := range r.kindEncoders {
+		rg.kindEncoders[k] = enc
+	}
+	for k, dec := range r.kindDecoders {
+		rg.kindDecoders[k] = dec
+	}
+	return rg
+}
+
 // RegisterTypeCodec :
 func (r *Registry) RegisterTypeCodec(t reflect.Type, enc ValueEncoder, dec ValueDecoder) {
 	r.mutex.Lock()
@@ -3,8 +3,10 @@ package codec
 import (
 	"database/sql/driver"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/Oskang09/sqlike/jsonb"
 	"github.com/Oskang09/sqlike/reflext"
 	"github.com/stretchr/testify/require"
 )
@@ -85,3 +87,82 @@ func TestNilEncoder(t *testing.T) {
 		require.Nil(t, it)
 	}
 }
+
+func TestNewDefaultRegistry(t *testing.T) {
+	type item struct {
+		Name  string
+		Value int
+	}
+
+	rg := NewDefaultRegistry(jsonb.NewCodec(jsonb.Config{
+		NameFunc:  strings.ToLower,
+		OmitEmpty: true,
+	}))
+
+	v := reflect.ValueOf(item{Name: "abc"})
+	encoder, err := rg.LookupEncoder(v)
+	require.NoError(t, err)
+	it, err := encoder(nil, v)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"abc"}`, string(it.([]byte)))
+
+	decoder, err := rg.LookupDecoder(v.Type())
+	require.NoError(t, err)
+	var x item
+	require.NoError(t, decoder(`{"name":"xyz","value":10}`, reflect.ValueOf(&x).Elem()))
+	require.Equal(t, item{Name: "xyz", Value: 10}, x)
+}
+
+func TestRegisterJSONCodec(t *testing.T) {
+	type flag bool
+	type item struct {
+		Name string
+	}
+
+	rg := NewRegistry()
+	rg.RegisterTypeEncoder(reflect.TypeOf(flag(false)), func(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
+		return "Y", nil
+	})
+	rg.RegisterKindCodec(reflect.String, DefaultEncoders{}.EncodeString, DefaultDecoders{}.DecodeString)
+	RegisterJSONCodec(rg, jsonb.NewCodec(jsonb.Config{NameFunc: strings.ToLower}))
+
+	v := reflect.ValueOf(item{Name: "abc"})
+	encoder, err := rg.LookupEncoder(v)
+	require.NoError(t, err)
+	it, err := encoder(nil, v)
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"abc"}`, string(it.([]byte)))
+
+	// the existing coders are kept
+	encoder, err = rg.LookupEncoder(reflect.ValueOf(flag(true)))
+	require.NoError(t, err)
+	it, err = encoder(nil, reflect.ValueOf(flag(true)))
+	require.NoError(t, err)
+	require.Equal(t, "Y", it)
+	_, err = rg.LookupDecoder(reflect.TypeOf(""))
+	require.NoError(t, err)
+}
+
+func TestRegistryClone(t *testing.T) {
+	type flag bool
+
+	rg := NewDefaultRegistry(nil).(*Registry)
+	clone := rg.Clone()
+	clone.RegisterTypeEncoder(reflect.TypeOf(flag(false)), func(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
+		return "Y", nil
+	})
+
+	v := reflect.ValueOf(flag(true))
+	encoder, err := clone.LookupEncoder(v)
+	require.NoError(t, err)
+	it, err := encoder(nil, v)
+	require.NoError(t, err)
+	require.Equal(t, "Y", it)
+
+	// the original registry is not affected
+	encoder, err = rg.LookupEncoder(v)
+	require.NoError(t, err)
+	it, err = encoder(nil, v)
+	require.NoError(t, err)
+	require.Equal(t, true, it)
+}
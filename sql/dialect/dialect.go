@@ -12,6 +12,8 @@ import (
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sql/util"
 	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
 	"github.com/Oskang09/sqlike/sqlike/indexes"
 	"github.com/Oskang09/sqlike/sqlike/options"
 )
@@ -22,6 +24,24 @@ type SQLDialect interface {
 	Var(i int) string
 	Quote(n string) string
 	Format(v interface{}) (val string)
+	QuoteString(v string) string
+}
+
+// AlterTableInput : the current state of the table and the entity to be migrated to
+type AlterTableInput struct {
+	DB          string
+	Table       string
+	PK          string
+	HasPK       bool
+	Info        driver.Info
+	Fields      []reflext.StructFielder
+	Columns     []columns.Column
+	Indexes     util.StringSlice
+	Checks      util.StringSlice
+	DropIndexes util.StringSlice
+	AddIndexes  []indexes.Index
+	Unsafe      bool
+	Options     *options.MigrateOptions
 }
 
 // Dialect :
@@ -34,20 +54,27 @@ type Dialect interface {
 	CreateDatabase(stmt sqlstmt.Stmt, db string, checkExists bool)
 	DropDatabase(stmt sqlstmt.Stmt, db string, checkExists bool)
 	HasTable(stmt sqlstmt.Stmt, db, table string)
+	GetTables(stmt sqlstmt.Stmt, db string)
+	ShowCreateTable(stmt sqlstmt.Stmt, db, table string)
 	HasPrimaryKey(stmt sqlstmt.Stmt, db, table string)
+	GetCheckConstraints(stmt sqlstmt.Stmt, db, table string)
+	GetTableOptions(stmt sqlstmt.Stmt, db, table string)
 	RenameTable(stmt sqlstmt.Stmt, db, oldName, newName string)
 	RenameColumn(stmt sqlstmt.Stmt, db, table, oldColName, newColName string)
 	DropColumn(stmt sqlstmt.Stmt, db, table, column string)
 	DropTable(stmt sqlstmt.Stmt, db, table string, checkExists bool)
 	TruncateTable(stmt sqlstmt.Stmt, db, table string)
-	GetColumns(stmt sqlstmt.Stmt, db, table string)
+	GetColumns(stmt sqlstmt.Stmt, info driver.Info, db, table string)
+	ColumnDefinition(col columns.Definition) string
 	HasIndexByName(stmt sqlstmt.Stmt, db, table, indexName string)
 	HasIndex(stmt sqlstmt.Stmt, dbName, table string, idx indexes.Index)
 	GetIndexes(stmt sqlstmt.Stmt, db, table string)
+	GetIndexColumns(stmt sqlstmt.Stmt, info driver.Info, db, table string)
 	CreateIndexes(stmt sqlstmt.Stmt, db, table string, idxs []indexes.Index, supportDesc bool)
 	DropIndexes(stmt sqlstmt.Stmt, db, table string, idxs []string)
-	CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver.Info, fields []reflext.StructFielder) (err error)
-	AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool, info driver.Info, fields []reflext.StructFielder, columns util.StringSlice, indexes util.StringSlice, unsafe bool) (err error)
+	AlterIndexes(stmt sqlstmt.Stmt, db, table string, drops []string, idxs []indexes.Index, supportDesc bool)
+	CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver.Info, fields []reflext.StructFielder, idxs []indexes.Index) (err error)
+	AlterTable(stmt sqlstmt.Stmt, in AlterTableInput) (plan *ddl.Plan, err error)
 	InsertInto(stmt sqlstmt.Stmt, db, table, pk string, mapper reflext.StructMapper, codec codec.Codecer, fields []reflext.StructFielder, values reflect.Value, opts *options.InsertOptions) (err error)
 	Select(stmt sqlstmt.Stmt, act *actions.FindActions, mode options.LockMode) (err error)
 	Update(stmt sqlstmt.Stmt, act *actions.UpdateActions) (err error)
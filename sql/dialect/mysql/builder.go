@@ -118,6 +118,9 @@ func (b *mySQLBuilder) BuildFunction(stmt sqlstmt.Stmt, it interface{}) error {
 // BuildJSONFunction :
 func (b *mySQLBuilder) BuildJSONFunction(stmt sqlstmt.Stmt, it interface{}) error {
 	x := it.(primitive.JSONFunc)
+	if x.Type == primitive.JSON_TABLE {
+		return b.buildJSONTable(stmt, x)
+	}
 	if x.Prefix != nil {
 		if err := b.getValue(stmt, x.Prefix); err != nil {
 			return err
@@ -138,6 +141,66 @@ func (b *mySQLBuilder) BuildJSONFunction(stmt sqlstmt.Stmt, it interface{}) erro
 	return nil
 }
 
+// buildJSONTable : JSON_TABLE(expr, path COLUMNS (column_list)) AS alias
+func (b *mySQLBuilder) buildJSONTable(stmt sqlstmt.Stmt, x primitive.JSONFunc) error {
+	if len(x.Args) != 3 {
+		return errors.New("mysql: invalid arguments of JSON_TABLE")
+	}
+	path, ok1 := x.Args[1].(string)
+	tbl, ok2 := x.Args[2].(primitive.JSONTable)
+	if !ok1 || !ok2 {
+		return errors.New("mysql: invalid arguments of JSON_TABLE")
+	}
+
+	stmt.WriteString(x.Type.String())
+	stmt.WriteByte('(')
+	if err := b.builder.BuildStatement(stmt, x.Args[0]); err != nil {
+		return err
+	}
+	// path of `JSON_TABLE` must be a string literal
+	stmt.WriteString("," + quoteString(path) + " ")
+	if err := b.appendJSONTableColumns(stmt, tbl.Columns); err != nil {
+		return err
+	}
+	stmt.WriteString(") AS " + b.Quote(tbl.Alias))
+	return nil
+}
+
+func (b *mySQLBuilder) appendJSONTableColumns(stmt sqlstmt.Stmt, cols []primitive.JSONTableColumn) error {
+	stmt.WriteString("COLUMNS (")
+	for i, col := range cols {
+		if i > 0 {
+			stmt.WriteByte(',')
+		}
+		switch {
+		case col.Nested != nil:
+			stmt.WriteString("NESTED PATH " + quoteString(col.Path) + " ")
+			if err := b.appendJSONTableColumns(stmt, col.Nested); err != nil {
+				return err
+			}
+		case col.Ordinality:
+			stmt.WriteString(b.Quote(col.Name) + " FOR ORDINALITY")
+		default:
+			if col.DataType == "" {
+				return errors.New("mysql: missing data type of JSON_TABLE column " + col.Name)
+			}
+			stmt.WriteString(b.Quote(col.Name) + " " + col.DataType)
+			if col.Exists {
+				stmt.WriteString(" EXISTS")
+			}
+			stmt.WriteString(" PATH " + quoteString(col.Path))
+			if col.OnEmpty != "" {
+				stmt.WriteString(" " + col.OnEmpty + " ON EMPTY")
+			}
+			if col.OnError != "" {
+				stmt.WriteString(" " + col.OnError + " ON ERROR")
+			}
+		}
+	}
+	stmt.WriteByte(')')
+	return nil
+}
+
 // BuildString :
 func (b *mySQLBuilder) BuildString(stmt sqlstmt.Stmt, it interface{}) error {
 	v := reflect.ValueOf(it)
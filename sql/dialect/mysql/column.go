@@ -1,14 +1,87 @@
 package mysql
 
-import sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+import (
+	"strconv"
+	"strings"
 
-// GetColumns :
-func (ms *MySQL) GetColumns(stmt sqlstmt.Stmt, dbName, table string) {
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/sql/driver"
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+)
+
+var srsID = semver.MustParse("8.0.0")
+
+// GetColumns : `SRS_ID` is only selected on 8.0, it doesn't exist on 5.7
+func (ms *MySQL) GetColumns(stmt sqlstmt.Stmt, info driver.Info, dbName, table string) {
 	stmt.WriteString(`SELECT ORDINAL_POSITION, COLUMN_NAME, COLUMN_TYPE, COLUMN_DEFAULT, IS_NULLABLE,
-	DATA_TYPE, CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_COMMENT, EXTRA FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;`)
+	DATA_TYPE, CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_COMMENT, EXTRA, GENERATION_EXPRESSION`)
+	if v := versionOf(info); v != nil && !v.LessThan(srsID) {
+		stmt.WriteString(", SRS_ID")
+	}
+	stmt.WriteString(` FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;`)
 	stmt.AppendArgs(dbName, table)
 }
 
+// ColumnDefinition : the column definition of the existing column, eg.
+// `Age` int unsigned NOT NULL DEFAULT '18'
+func (ms *MySQL) ColumnDefinition(col columns.Definition) string {
+	blr := new(strings.Builder)
+	blr.WriteString(ms.Quote(col.Name) + " " + strings.ToLower(col.Type))
+	if col.Charset != nil {
+		blr.WriteString(" CHARACTER SET " + *col.Charset)
+	}
+	if col.Collation != nil {
+		blr.WriteString(" COLLATE " + *col.Collation)
+	}
+
+	// `DEFAULT_GENERATED` is implied by the default value
+	extra := strings.ToUpper(col.Extra)
+	defaultGenerated := strings.Contains(extra, "DEFAULT_GENERATED")
+	extra = strings.Join(strings.Fields(strings.Replace(extra, "DEFAULT_GENERATED", "", 1)), " ")
+	if col.Expression != "" {
+		blr.WriteString(" GENERATED ALWAYS AS (" + col.Expression + ")")
+		if strings.Contains(extra, "STORED") {
+			blr.WriteString(" STORED")
+		} else {
+			blr.WriteString(" VIRTUAL")
+		}
+		extra = ""
+	}
+
+	if col.SRID != nil {
+		blr.WriteString(" SRID " + strconv.FormatUint(uint64(*col.SRID), 10))
+	}
+
+	if col.Nullable {
+		blr.WriteString(" NULL")
+	} else {
+		blr.WriteString(" NOT NULL")
+	}
+
+	if col.DefaultValue != nil && col.Expression == "" {
+		v := *col.DefaultValue
+		blr.WriteString(" DEFAULT ")
+		switch {
+		case strings.HasPrefix(strings.ToUpper(v), "CURRENT_TIMESTAMP"):
+			blr.WriteString(v)
+		case defaultGenerated:
+			// expression default value should be enclosed within parentheses
+			blr.WriteString("(" + v + ")")
+		default:
+			blr.WriteString(quoteString(v))
+		}
+	}
+
+	if extra != "" {
+		blr.WriteString(" " + extra)
+	}
+	if col.Comment != "" {
+		blr.WriteString(" COMMENT " + quoteString(col.Comment))
+	}
+	return blr.String()
+}
+
 // RenameColumn :
 func (ms *MySQL) RenameColumn(stmt sqlstmt.Stmt, db, table, oldColName, newColName string) {
 	stmt.WriteString("ALTER TABLE " + ms.TableName(db, table))
@@ -22,3 +95,11 @@ func (ms *MySQL) DropColumn(stmt sqlstmt.Stmt, db, table, column string) {
 	stmt.WriteString(" DROP COLUMN " + ms.Quote(column))
 	stmt.WriteByte(';')
 }
+
+// versionOf : the version is nil when it's unknown
+func versionOf(info driver.Info) *semver.Version {
+	if info == nil {
+		return nil
+	}
+	return info.Version()
+}
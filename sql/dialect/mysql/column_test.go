@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/Oskang09/sqlike/sqlike/columns"
 	"github.com/stretchr/testify/require"
 )
 
@@ -11,10 +12,18 @@ func TestGetColumns(t *testing.T) {
 	ms := New()
 	stmt := sqlstmt.AcquireStmt(ms)
 	defer sqlstmt.ReleaseStmt(stmt)
-	ms.GetColumns(stmt, "db", "table")
+	ms.GetColumns(stmt, testInfo{version: "8.0.20"}, "db", "table")
 	require.Equal(t, `SELECT ORDINAL_POSITION, COLUMN_NAME, COLUMN_TYPE, COLUMN_DEFAULT, IS_NULLABLE,
-	DATA_TYPE, CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_COMMENT, EXTRA FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;`, stmt.String())
+	DATA_TYPE, CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_COMMENT, EXTRA, GENERATION_EXPRESSION, SRS_ID FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;`, stmt.String())
 	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
+
+	// `SRS_ID` doesn't exist on 5.7
+	for _, info := range []testInfo{{version: "5.7.30"}, {}} {
+		stmt.Reset()
+		ms.GetColumns(stmt, info, "db", "table")
+		require.Equal(t, `SELECT ORDINAL_POSITION, COLUMN_NAME, COLUMN_TYPE, COLUMN_DEFAULT, IS_NULLABLE,
+	DATA_TYPE, CHARACTER_SET_NAME, COLLATION_NAME, COLUMN_COMMENT, EXTRA, GENERATION_EXPRESSION FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION;`, stmt.String())
+	}
 }
 
 func TestRenameColumn(t *testing.T) {
@@ -34,3 +43,30 @@ func TestDropColumn(t *testing.T) {
 	require.Equal(t, "ALTER TABLE `db`.`table` DROP COLUMN `c1`;", stmt.String())
 	require.ElementsMatch(t, []interface{}{}, stmt.Args())
 }
+
+func TestColumnDefinition(t *testing.T) {
+	var (
+		ms      = New()
+		utf8mb4 = "utf8mb4"
+		collate = "utf8mb4_unicode_ci"
+		empty   = ""
+		now     = "CURRENT_TIMESTAMP(6)"
+		uuid    = "uuid_to_bin(uuid())"
+		srid    = uint32(4326)
+	)
+
+	for _, c := range []struct {
+		col columns.Definition
+		def string
+	}{
+		{col: columns.Definition{Name: "ID", Type: "BIGINT UNSIGNED", Extra: "auto_increment"}, def: "`ID` bigint unsigned NOT NULL AUTO_INCREMENT"},
+		{col: columns.Definition{Name: "Name", Type: "varchar(191)", DefaultValue: &empty, Charset: &utf8mb4, Collation: &collate, Comment: "user's name"}, def: "`Name` varchar(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT 'user''s name'"},
+		{col: columns.Definition{Name: "Age", Type: "int", Nullable: true, Expression: "`Meta`->>'$.age'", Extra: "VIRTUAL GENERATED"}, def: "`Age` int GENERATED ALWAYS AS (`Meta`->>'$.age') VIRTUAL NULL"},
+		{col: columns.Definition{Name: "Total", Type: "int", Expression: "`A` + `B`", Extra: "STORED GENERATED"}, def: "`Total` int GENERATED ALWAYS AS (`A` + `B`) STORED NOT NULL"},
+		{col: columns.Definition{Name: "CreatedAt", Type: "datetime(6)", DefaultValue: &now, Extra: "DEFAULT_GENERATED"}, def: "`CreatedAt` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)"},
+		{col: columns.Definition{Name: "UUID", Type: "binary(16)", DefaultValue: &uuid, Extra: "DEFAULT_GENERATED"}, def: "`UUID` binary(16) NOT NULL DEFAULT (uuid_to_bin(uuid()))"},
+		{col: columns.Definition{Name: "Location", Type: "point", SRID: &srid}, def: "`Location` point SRID 4326 NOT NULL"},
+	} {
+		require.Equal(t, c.def, ms.ColumnDefinition(c.col))
+	}
+}
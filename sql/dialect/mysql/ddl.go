@@ -0,0 +1,419 @@
+package mysql
+
+import (
+	"strconv"
+	"strings"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+)
+
+var (
+	instantDDL       = semver.MustParse("8.0.0")
+	instantAddColumn = semver.MustParse("8.0.12")
+	instantAnyColumn = semver.MustParse("8.0.29")
+	jsonSchemaValid  = semver.MustParse("8.0.17")
+)
+
+// planner : classify the operations of `ALTER TABLE` follows by the InnoDB online DDL
+type planner struct {
+	version  *semver.Version
+	existing []columns.Column
+	columns  map[string]columns.Column
+	order    []string
+}
+
+func newPlanner(version *semver.Version, existing []columns.Column) *planner {
+	p := &planner{version: version, existing: existing}
+	p.columns = make(map[string]columns.Column, len(existing))
+	for _, c := range existing {
+		p.columns[c.Name] = c
+	}
+	return p
+}
+
+// supports : whether the server version is greater or equal to v, unknown version is treated as unsupported
+func (p *planner) supports(v *semver.Version) bool {
+	return p.version != nil && !p.version.LessThan(v)
+}
+
+// metadata : the algorithm of the operation which only changes the metadata
+func (p *planner) metadata() ddl.Algorithm {
+	if p.supports(instantDDL) {
+		return ddl.Instant
+	}
+	return ddl.Inplace
+}
+
+// reordered : whether the column is placed after a different existing column
+func (p *planner) reordered(name string) bool {
+	prev := func(names []string) string {
+		last := ""
+		for _, n := range names {
+			if n == name {
+				return last
+			}
+			if _, ok := p.columns[n]; ok {
+				last = n
+			}
+		}
+		return last
+	}
+
+	current := make([]string, 0, len(p.existing))
+	for _, c := range p.existing {
+		for _, n := range p.order {
+			if n == c.Name {
+				current = append(current, n)
+				break
+			}
+		}
+	}
+	return prev(current) != prev(p.order)
+}
+
+// appended : whether there is no existing column after the column
+func (p *planner) appended(name string) bool {
+	found := false
+	for _, n := range p.order {
+		if n == name {
+			found = true
+			continue
+		}
+		if _, ok := p.columns[n]; ok && found {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *planner) addColumn(op *ddl.Operation, col columns.Column, generated string) {
+	switch {
+	case generated == "STORED":
+		op.Algorithm, op.Reason = ddl.Copy, "stored generated column"
+	case strings.Contains(strings.ToUpper(col.Extra), "AUTO_INCREMENT"):
+		op.Algorithm, op.Lock, op.Reason = ddl.Inplace, ddl.SharedLock, "auto increment column"
+		return
+	case p.supports(instantAnyColumn):
+		op.Algorithm = ddl.Instant
+	case p.supports(instantAddColumn) && p.appended(op.Name):
+		op.Algorithm = ddl.Instant
+	case p.supports(instantAddColumn):
+		op.Algorithm, op.Reason = ddl.Inplace, "column is not appended as the last column"
+	default:
+		op.Algorithm = ddl.Inplace
+	}
+	op.Lock = lockOf(op.Algorithm)
+}
+
+func (p *planner) modifyColumn(op *ddl.Operation, old, col columns.Column) {
+	reasons := make([]string, 0)
+	op.Algorithm = p.metadata()
+
+	if alg, destructive, ok := p.compareType(old, col); !ok {
+		op.Algorithm = maxAlgorithm(op.Algorithm, alg)
+		op.Destructive = op.Destructive || destructive
+		reasons = append(reasons, "type: "+old.Type+" => "+col.Type)
+	}
+
+	if old.Charset != nil && col.Charset != nil {
+		from, to := strings.ToLower(*old.Charset), strings.ToLower(*col.Charset)
+		if from != to || (old.Collation != nil && col.Collation != nil && !strings.EqualFold(*old.Collation, *col.Collation)) {
+			op.Algorithm = ddl.Copy
+			// only utf8mb4 is able to store every character
+			op.Destructive = op.Destructive || (from != to && to != "utf8mb4")
+			reasons = append(reasons, "charset: "+describeCharset(old)+" => "+describeCharset(col))
+		}
+	}
+
+	if old.Nullable != col.Nullable {
+		op.Algorithm = maxAlgorithm(op.Algorithm, ddl.Inplace)
+		reasons = append(reasons, "nullable: "+strconv.FormatBool(old.Nullable)+" => "+strconv.FormatBool(col.Nullable))
+	}
+
+	if hasAutoIncrement(old.Extra) != hasAutoIncrement(col.Extra) {
+		op.Algorithm = ddl.Copy
+		reasons = append(reasons, "auto increment: "+strconv.FormatBool(hasAutoIncrement(old.Extra))+" => "+strconv.FormatBool(hasAutoIncrement(col.Extra)))
+	}
+
+	if p.reordered(op.Name) {
+		op.Algorithm = maxAlgorithm(op.Algorithm, ddl.Inplace)
+		reasons = append(reasons, "column reordered")
+	}
+
+	op.Lock = lockOf(op.Algorithm)
+	op.Reason = strings.Join(reasons, ", ")
+}
+
+// addIndex : fulltext and spatial index doesn't permit concurrent DML while it's building
+func (p *planner) addIndex(op *ddl.Operation, idx indexes.Index) {
+	op.Algorithm, op.Lock = ddl.Inplace, ddl.NoneLock
+	switch idx.Type {
+	case indexes.FullText:
+		op.Lock, op.Reason = ddl.SharedLock, "fulltext index"
+	case indexes.Spatial:
+		op.Lock, op.Reason = ddl.SharedLock, "spatial index"
+	}
+}
+
+func (p *planner) dropColumn(op *ddl.Operation) {
+	op.Algorithm = ddl.Inplace
+	if p.supports(instantAnyColumn) {
+		op.Algorithm = ddl.Instant
+	}
+	op.Lock = lockOf(op.Algorithm)
+	op.Destructive = true
+	op.Reason = "column data will be deleted"
+}
+
+// compareType : return ok if both data type are the same, otherwise return the algorithm and
+// whether the conversion may lose data
+func (p *planner) compareType(old, col columns.Column) (ddl.Algorithm, bool, bool) {
+	o, n := parseColumnType(old.Type), parseColumnType(col.Type)
+	if o.base == n.base && o.unsigned == n.unsigned {
+		if o.integer() || strings.Join(o.args, ",") == strings.Join(n.args, ",") {
+			return ddl.DefaultAlgorithm, false, true
+		}
+	}
+
+	switch {
+	case o.integer() && n.integer():
+		narrow := intRanks[n.base] < intRanks[o.base] ||
+			(o.unsigned && !n.unsigned && intRanks[n.base] == intRanks[o.base]) ||
+			(!o.unsigned && n.unsigned)
+		return ddl.Copy, narrow, false
+
+	case o.base == n.base && (o.base == "VARCHAR" || o.base == "VARBINARY"):
+		os, ns := o.size(), n.size()
+		if ns < os {
+			return ddl.Copy, true, false
+		}
+		// the length prefix of VARCHAR is 1 byte up to 255 bytes, otherwise 2 bytes
+		if (os*bytesPerChar(old.Charset) > 255) == (ns*bytesPerChar(col.Charset) > 255) {
+			return ddl.Inplace, false, false
+		}
+		return ddl.Copy, false, false
+
+	case o.char() && n.char():
+		return ddl.Copy, n.size() < o.size(), false
+
+	case o.text() && n.text():
+		return ddl.Copy, textRanks[n.base] < textRanks[o.base], false
+
+	case o.char() && n.text():
+		return ddl.Copy, textCapacity[textRanks[n.base]] < o.size()*bytesPerChar(old.Charset), false
+
+	case o.base == "DECIMAL" && n.base == "DECIMAL":
+		op, os := o.precision()
+		np, ns := n.precision()
+		return ddl.Copy, np-ns < op-os || ns < os || (o.unsigned != n.unsigned && n.unsigned), false
+
+	case o.float() && n.float():
+		return ddl.Copy, o.base == "DOUBLE" && n.base == "FLOAT", false
+
+	case o.base == n.base && (o.base == "DATETIME" || o.base == "TIMESTAMP" || o.base == "TIME"):
+		return ddl.Copy, n.size() < o.size(), false
+
+	case o.base == n.base && (o.base == "ENUM" || o.base == "SET"):
+		if len(n.args) < len(o.args) || strings.Join(n.args[:len(o.args)], ",") != strings.Join(o.args, ",") {
+			return ddl.Copy, true, false
+		}
+		// appending members is only a metadata change if the storage size is the same
+		if memberBytes(o.base, len(o.args)) == memberBytes(n.base, len(n.args)) {
+			return p.metadata(), false, false
+		}
+		return ddl.Copy, false, false
+	}
+	return ddl.Copy, true, false
+}
+
+var intRanks = map[string]int{
+	"TINYINT":   1,
+	"SMALLINT":  2,
+	"MEDIUMINT": 3,
+	"INT":       4,
+	"INTEGER":   4,
+	"BIGINT":    5,
+}
+
+var textRanks = map[string]int{
+	"TINYTEXT":   1,
+	"TINYBLOB":   1,
+	"TEXT":       2,
+	"BLOB":       2,
+	"MEDIUMTEXT": 3,
+	"MEDIUMBLOB": 3,
+	"LONGTEXT":   4,
+	"LONGBLOB":   4,
+}
+
+// textCapacity : maximum bytes of the text types by rank
+var textCapacity = map[int]int{
+	1: 1<<8 - 1,
+	2: 1<<16 - 1,
+	3: 1<<24 - 1,
+	4: 1<<32 - 1,
+}
+
+// columnType : the parsed column type, eg. `decimal(10,2) unsigned`
+type columnType struct {
+	base     string
+	args     []string
+	unsigned bool
+}
+
+func parseColumnType(t string) (ct columnType) {
+	t = strings.TrimSpace(t)
+	rest := t
+	if i := strings.IndexByte(t, '('); i > -1 {
+		j := strings.LastIndexByte(t, ')')
+		if j < i {
+			j = len(t)
+		}
+		ct.base = strings.ToUpper(strings.TrimSpace(t[:i]))
+		ct.args = splitArgs(t[i+1 : j])
+		if j < len(t) {
+			rest = t[j+1:]
+		} else {
+			rest = ""
+		}
+	} else {
+		paths := strings.Fields(t)
+		if len(paths) > 0 {
+			ct.base = strings.ToUpper(paths[0])
+			rest = strings.Join(paths[1:], " ")
+		}
+	}
+	for _, f := range strings.Fields(strings.ToUpper(rest)) {
+		if f == "UNSIGNED" {
+			ct.unsigned = true
+		}
+	}
+	return
+}
+
+// splitArgs : split the arguments by comma, the comma within the quotes will be skipped
+func splitArgs(s string) []string {
+	args := make([]string, 0)
+	quoted, last := false, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				args = append(args, strings.TrimSpace(s[last:i]))
+				last = i + 1
+			}
+		}
+	}
+	if last < len(s) {
+		args = append(args, strings.TrimSpace(s[last:]))
+	}
+	return args
+}
+
+func (ct columnType) integer() bool {
+	_, ok := intRanks[ct.base]
+	return ok
+}
+
+func (ct columnType) char() bool {
+	switch ct.base {
+	case "CHAR", "VARCHAR", "BINARY", "VARBINARY":
+		return true
+	}
+	return false
+}
+
+func (ct columnType) text() bool {
+	_, ok := textRanks[ct.base]
+	return ok
+}
+
+func (ct columnType) float() bool {
+	return ct.base == "FLOAT" || ct.base == "DOUBLE"
+}
+
+func (ct columnType) size() int {
+	if len(ct.args) < 1 {
+		if ct.base == "CHAR" || ct.base == "BINARY" {
+			return 1
+		}
+		return 0
+	}
+	n, _ := strconv.Atoi(ct.args[0])
+	return n
+}
+
+func (ct columnType) precision() (p int, s int) {
+	p = 10
+	if len(ct.args) > 0 {
+		p, _ = strconv.Atoi(ct.args[0])
+	}
+	if len(ct.args) > 1 {
+		s, _ = strconv.Atoi(ct.args[1])
+	}
+	return
+}
+
+// memberBytes : storage size of `ENUM` and `SET`
+func memberBytes(base string, n int) int {
+	if base == "ENUM" {
+		if n > 255 {
+			return 2
+		}
+		return 1
+	}
+	b := (n + 7) / 8
+	if b > 4 {
+		return 8
+	}
+	return b
+}
+
+func bytesPerChar(charset *string) int {
+	if charset == nil {
+		return 1
+	}
+	switch strings.ToLower(*charset) {
+	case "utf8mb4", "utf16", "utf16le", "utf32":
+		return 4
+	case "utf8", "utf8mb3", "ujis", "eucjpms":
+		return 3
+	case "ucs2", "big5", "gbk", "sjis", "cp932", "euckr", "gb2312":
+		return 2
+	}
+	return 1
+}
+
+func hasAutoIncrement(extra string) bool {
+	return strings.Contains(strings.ToUpper(extra), "AUTO_INCREMENT")
+}
+
+func describeCharset(col columns.Column) string {
+	s := *col.Charset
+	if col.Collation != nil {
+		s += " " + *col.Collation
+	}
+	return s
+}
+
+func maxAlgorithm(a, b ddl.Algorithm) ddl.Algorithm {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func lockOf(alg ddl.Algorithm) ddl.Lock {
+	switch alg {
+	case ddl.Inplace:
+		return ddl.NoneLock
+	case ddl.Copy:
+		return ddl.SharedLock
+	}
+	return ddl.DefaultLock
+}
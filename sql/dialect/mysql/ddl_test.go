@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"testing"
+
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareType(t *testing.T) {
+	utf8mb4, latin1 := "utf8mb4", "latin1"
+	p := newPlanner(semver.MustParse("8.0.30"), nil)
+
+	for _, c := range []struct {
+		old, new    columns.Column
+		algorithm   ddl.Algorithm
+		destructive bool
+		same        bool
+	}{
+		{old: columns.Column{Type: "int(11)"}, new: columns.Column{Type: "INT"}, same: true},
+		{old: columns.Column{Type: "bigint(20) unsigned"}, new: columns.Column{Type: "BIGINT UNSIGNED"}, same: true},
+		{old: columns.Column{Type: "int"}, new: columns.Column{Type: "BIGINT"}, algorithm: ddl.Copy},
+		{old: columns.Column{Type: "bigint"}, new: columns.Column{Type: "INT"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "int"}, new: columns.Column{Type: "INT UNSIGNED"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "int unsigned"}, new: columns.Column{Type: "BIGINT"}, algorithm: ddl.Copy},
+		{old: columns.Column{Type: "varchar(10)", Charset: &latin1}, new: columns.Column{Type: "VARCHAR(255)", Charset: &latin1}, algorithm: ddl.Inplace},
+		{old: columns.Column{Type: "varchar(60)", Charset: &utf8mb4}, new: columns.Column{Type: "VARCHAR(64)", Charset: &utf8mb4}, algorithm: ddl.Copy},
+		{old: columns.Column{Type: "varchar(191)", Charset: &utf8mb4}, new: columns.Column{Type: "VARCHAR(100)", Charset: &utf8mb4}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "varchar(191)", Charset: &utf8mb4}, new: columns.Column{Type: "TEXT", Charset: &utf8mb4}, algorithm: ddl.Copy},
+		{old: columns.Column{Type: "text"}, new: columns.Column{Type: "VARCHAR(191)"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "decimal(10,2)"}, new: columns.Column{Type: "DECIMAL(12,2)"}, algorithm: ddl.Copy},
+		{old: columns.Column{Type: "decimal(10,2)"}, new: columns.Column{Type: "DECIMAL(10,4)"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "datetime(6)"}, new: columns.Column{Type: "DATETIME(3)"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "enum('a','b')"}, new: columns.Column{Type: "ENUM('a','b','c,d')"}, algorithm: ddl.Instant},
+		{old: columns.Column{Type: "enum('a','b')"}, new: columns.Column{Type: "ENUM('b','a')"}, algorithm: ddl.Copy, destructive: true},
+		{old: columns.Column{Type: "int"}, new: columns.Column{Type: "VARCHAR(191)"}, algorithm: ddl.Copy, destructive: true},
+	} {
+		alg, destructive, same := p.compareType(c.old, c.new)
+		require.Equal(t, c.same, same, c.old.Type+" => "+c.new.Type)
+		if !same {
+			require.Equal(t, c.algorithm, alg, c.old.Type+" => "+c.new.Type)
+			require.Equal(t, c.destructive, destructive, c.old.Type+" => "+c.new.Type)
+		}
+	}
+}
+
+func TestParseColumnType(t *testing.T) {
+	ct := parseColumnType("decimal(10,2) unsigned zerofill")
+	require.Equal(t, columnType{base: "DECIMAL", args: []string{"10", "2"}, unsigned: true}, ct)
+
+	ct = parseColumnType("set('a,b','c')")
+	require.Equal(t, columnType{base: "SET", args: []string{"'a,b'", "'c'"}}, ct)
+
+	ct = parseColumnType("BIGINT UNSIGNED")
+	require.Equal(t, columnType{base: "BIGINT", unsigned: true}, ct)
+}
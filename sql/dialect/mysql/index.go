@@ -3,18 +3,28 @@ package mysql
 import (
 	"regexp"
 	"strconv"
+	"strings"
 
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/sql/driver"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sqlike/indexes"
 )
 
+var (
+	invisibleIndex    = semver.MustParse("8.0.0")
+	functionalKeyPart = semver.MustParse("8.0.13")
+	descendingIndex   = semver.MustParse("8.0.0")
+)
+
 // HasIndexByName :
 func (ms MySQL) HasIndexByName(stmt sqlstmt.Stmt, dbName, table, indexName string) {
 	stmt.WriteString(`SELECT COUNT(1) FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_NAME = ?;`)
 	stmt.AppendArgs(dbName, table, indexName)
 }
 
-// HasIndex :
+// HasIndex : the key parts are matched by sequence, column name and prefix length, the functional
+// key part is matched by the index name as its expression is normalised by the server
 func (ms MySQL) HasIndex(stmt sqlstmt.Stmt, dbName, table string, idx indexes.Index) {
 	nonUnique, idxType := true, "BTREE"
 	switch idx.Type {
@@ -27,27 +37,34 @@ func (ms MySQL) HasIndex(stmt sqlstmt.Stmt, dbName, table string, idx indexes.In
 	case indexes.Primary:
 		nonUnique = false
 	}
-	args := []interface{}{dbName, table, idxType, nonUnique}
+	args := make([]interface{}, 0, len(idx.Columns)*3+6)
 	stmt.WriteString("SELECT COUNT(1) FROM (")
-	stmt.WriteString("SELECT INDEX_NAME, COUNT(*) AS c FROM INFORMATION_SCHEMA.STATISTICS ")
-	stmt.WriteString("WHERE TABLE_SCHEMA = ? ")
-	stmt.WriteString("AND TABLE_NAME = ? ")
-	stmt.WriteString("AND INDEX_TYPE = ? ")
-	stmt.WriteString("AND NON_UNIQUE = ? ")
-	stmt.WriteString("AND COLUMN_NAME IN ")
-	stmt.WriteByte('(')
+	stmt.WriteString("SELECT INDEX_NAME, COUNT(*) AS c, SUM(CASE WHEN ")
 	for i, col := range idx.Columns {
 		if i > 0 {
-			stmt.WriteByte(',')
+			stmt.WriteString(" OR ")
+		}
+		if col.Expr != "" {
+			stmt.WriteString("(SEQ_IN_INDEX = ? AND COLUMN_NAME IS NULL AND INDEX_NAME = ?)")
+			args = append(args, int64(i+1), idx.GetName())
+			continue
+		}
+		stmt.WriteString("(SEQ_IN_INDEX = ? AND COLUMN_NAME = ? AND SUB_PART <=> ?)")
+		var subPart interface{}
+		if col.Length > 0 {
+			subPart = int64(col.Length)
 		}
-		stmt.WriteByte('?')
-		args = append(args, col.Name)
+		args = append(args, int64(i+1), col.Name, subPart)
 	}
-	stmt.WriteByte(')')
+	stmt.WriteString(" THEN 1 ELSE 0 END) AS m FROM INFORMATION_SCHEMA.STATISTICS ")
+	stmt.WriteString("WHERE TABLE_SCHEMA = ? ")
+	stmt.WriteString("AND TABLE_NAME = ? ")
+	stmt.WriteString("AND INDEX_TYPE = ? ")
+	stmt.WriteString("AND NON_UNIQUE = ?")
 	stmt.WriteString(" GROUP BY INDEX_NAME")
-	stmt.WriteString(") AS temp WHERE temp.c = ?")
+	stmt.WriteString(") AS temp WHERE temp.c = ? AND temp.m = ?")
 	stmt.WriteByte(';')
-	args = append(args, int64(len(idx.Columns)))
+	args = append(args, dbName, table, idxType, nonUnique, int64(len(idx.Columns)), int64(len(idx.Columns)))
 	stmt.AppendArgs(args...)
 }
 
@@ -57,50 +74,131 @@ func (ms MySQL) GetIndexes(stmt sqlstmt.Stmt, dbName, table string) {
 	stmt.AppendArgs(dbName, table)
 }
 
+// GetIndexColumns : `IS_VISIBLE` is only selected on 8.0 and `EXPRESSION` of functional key part
+// is only selected on 8.0.13
+func (ms MySQL) GetIndexColumns(stmt sqlstmt.Stmt, info driver.Info, dbName, table string) {
+	stmt.WriteString(`SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, SUB_PART, COLLATION, INDEX_TYPE, NON_UNIQUE, INDEX_COMMENT`)
+	v := versionOf(info)
+	if v != nil && !v.LessThan(invisibleIndex) {
+		stmt.WriteString(", IS_VISIBLE")
+	}
+	if v != nil && !v.LessThan(functionalKeyPart) {
+		stmt.WriteString(", EXPRESSION")
+	}
+	stmt.WriteString(` FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX;`)
+	stmt.AppendArgs(dbName, table)
+}
+
 // CreateIndexes :
 func (ms MySQL) CreateIndexes(stmt sqlstmt.Stmt, db, table string, idxs []indexes.Index, supportDesc bool) {
+	ms.AlterIndexes(stmt, db, table, nil, idxs, supportDesc)
+}
+
+// AlterIndexes : drop and create the indexes within a single `ALTER TABLE`, so the table
+// is never left without the index in between, the primary key will never be dropped
+func (ms MySQL) AlterIndexes(stmt sqlstmt.Stmt, db, table string, drops []string, idxs []indexes.Index, supportDesc bool) {
+	var algorithm, lock string
 	stmt.WriteString("ALTER TABLE " + ms.TableName(db, table))
-	for i, idx := range idxs {
-		if i > 0 {
+	n := 0
+	for _, name := range drops {
+		if name == "PRIMARY" {
+			continue
+		}
+		if n > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteString(" DROP INDEX " + ms.Quote(name))
+		n++
+	}
+	for _, idx := range idxs {
+		if n > 0 {
 			stmt.WriteByte(',')
 		}
+		n++
 
-		stmt.WriteString(" ADD " + ms.getIndexByType(idx.Type) + " ")
-		name := idx.GetName()
-		if idx.Type == indexes.MultiValued {
-			stmt.WriteString(name + "( (CAST(")
-			if regexp.MustCompile(`(?is).+\s*\-\>\s*.+`).MatchString(idx.Cast) {
-				stmt.WriteString(idx.Cast)
-			} else {
-				stmt.WriteString("`" + idx.Cast + "` -> '$'")
-			}
-			stmt.WriteString(" AS " + idx.As + ")) )")
+		stmt.WriteString(" ADD ")
+		ms.buildIndex(stmt, idx, supportDesc)
+
+		if algorithm == "" {
+			algorithm = strings.ToUpper(idx.Algorithm)
+		}
+		if lock == "" {
+			lock = strings.ToUpper(idx.Lock)
+		}
+	}
+	if algorithm != "" {
+		stmt.WriteString(", ALGORITHM = " + algorithm)
+	}
+	if lock != "" {
+		stmt.WriteString(", LOCK = " + lock)
+	}
+	stmt.WriteByte(';')
+}
+
+// buildIndex : write the index definition, eg. UNIQUE INDEX `UX_Name` (`Name`)
+func (ms MySQL) buildIndex(stmt sqlstmt.Stmt, idx indexes.Index, supportDesc bool) {
+	stmt.WriteString(ms.getIndexByType(idx.Type))
+	name := idx.GetName()
+	if idx.Type == indexes.MultiValued {
+		stmt.WriteString(" " + name + "( (CAST(")
+		if regexp.MustCompile(`(?is).+\s*\-\>\s*.+`).MatchString(idx.Cast) {
+			stmt.WriteString(idx.Cast)
 		} else {
-			if name != "" {
-				stmt.WriteString(ms.Quote(name))
+			stmt.WriteString("`" + idx.Cast + "` -> '$'")
+		}
+		stmt.WriteString(" AS " + idx.As + ")) )")
+	} else {
+		// the name of primary key is always `PRIMARY`
+		if name != "" && idx.Type != indexes.Primary {
+			stmt.WriteString(" " + ms.Quote(name))
+		}
+		if idx.Using != "" {
+			stmt.WriteString(" USING " + strings.ToUpper(idx.Using))
+		}
+		stmt.WriteString(" (")
+		for j, col := range idx.Columns {
+			if j > 0 {
+				stmt.WriteByte(',')
 			}
-			stmt.WriteString(" (")
-			for j, col := range idx.Columns {
-				if j > 0 {
-					stmt.WriteByte(',')
-				}
+			if col.Expr != "" {
+				stmt.WriteString("(" + col.Expr + ")")
+			} else {
 				stmt.WriteString(ms.Quote(col.Name))
-				if !supportDesc {
-					continue
-				}
-				if col.Direction == indexes.Descending {
-					stmt.WriteString(" DESC")
+				if col.Length > 0 {
+					stmt.WriteString("(" + strconv.FormatUint(uint64(col.Length), 10) + ")")
 				}
 			}
-			stmt.WriteByte(')')
-		}
-
-		if idx.Comment != "" {
-			stmt.WriteString(" COMMENT " + strconv.Quote(idx.Comment))
+			if !supportDesc {
+				continue
+			}
+			if col.Direction == indexes.Descending {
+				stmt.WriteString(" DESC")
+			}
 		}
+		stmt.WriteByte(')')
+	}
 
+	if idx.KeyBlockSize > 0 {
+		stmt.WriteString(" KEY_BLOCK_SIZE = " + strconv.FormatUint(uint64(idx.KeyBlockSize), 10))
 	}
-	stmt.WriteByte(';')
+	if idx.Parser != "" {
+		stmt.WriteString(" WITH PARSER " + idx.Parser)
+	}
+	if idx.Comment != "" {
+		stmt.WriteString(" COMMENT " + strconv.Quote(idx.Comment))
+	}
+	switch idx.Visibility {
+	case indexes.Visible:
+		stmt.WriteString(" VISIBLE")
+	case indexes.Invisible:
+		stmt.WriteString(" INVISIBLE")
+	}
+}
+
+// supportsDesc : descending index is only supported after 8.0, it's parsed but ignored on the older version
+func supportsDesc(info driver.Info) bool {
+	v := versionOf(info)
+	return v != nil && v.GreaterThan(descendingIndex)
 }
 
 // DropIndexes :
@@ -17,6 +17,29 @@ func TestHasIndexByName(t *testing.T) {
 	require.ElementsMatch(t, []interface{}{"db", "table", "idx1"}, stmt.Args())
 }
 
+func TestHasIndex(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	idx := indexes.Index{
+		Name: "IX_Name_Email",
+		Columns: []indexes.Col{
+			{Name: "Name", Length: 10},
+			{Expr: "LOWER(`Email`)"},
+			{Name: "Age"},
+		},
+	}
+	ms.HasIndex(stmt, "db", "table", idx)
+	require.Equal(t, "SELECT COUNT(1) FROM (SELECT INDEX_NAME, COUNT(*) AS c, SUM(CASE WHEN (SEQ_IN_INDEX = ? AND COLUMN_NAME = ? AND SUB_PART <=> ?) OR (SEQ_IN_INDEX = ? AND COLUMN_NAME IS NULL AND INDEX_NAME = ?) OR (SEQ_IN_INDEX = ? AND COLUMN_NAME = ? AND SUB_PART <=> ?) THEN 1 ELSE 0 END) AS m FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND INDEX_TYPE = ? AND NON_UNIQUE = ? GROUP BY INDEX_NAME) AS temp WHERE temp.c = ? AND temp.m = ?;", stmt.String())
+	require.Equal(t, []interface{}{
+		int64(1), "Name", int64(10),
+		int64(2), "IX_Name_Email",
+		int64(3), "Age", nil,
+		"db", "table", "BTREE", true, int64(3), int64(3),
+	}, stmt.Args())
+}
+
 func TestGetIndexes(t *testing.T) {
 	ms := New()
 	stmt := sqlstmt.AcquireStmt(ms)
@@ -27,6 +50,24 @@ func TestGetIndexes(t *testing.T) {
 	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
 }
 
+func TestGetIndexColumns(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	ms.GetIndexColumns(stmt, testInfo{version: "5.7.30"}, "db", "table")
+	require.Equal(t, "SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, SUB_PART, COLLATION, INDEX_TYPE, NON_UNIQUE, INDEX_COMMENT FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX;", stmt.String())
+	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
+
+	stmt.Reset()
+	ms.GetIndexColumns(stmt, testInfo{version: "8.0.12"}, "db", "table")
+	require.Equal(t, "SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, SUB_PART, COLLATION, INDEX_TYPE, NON_UNIQUE, INDEX_COMMENT, IS_VISIBLE FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX;", stmt.String())
+
+	stmt.Reset()
+	ms.GetIndexColumns(stmt, testInfo{version: "8.0.13"}, "db", "table")
+	require.Equal(t, "SELECT INDEX_NAME, SEQ_IN_INDEX, COLUMN_NAME, SUB_PART, COLLATION, INDEX_TYPE, NON_UNIQUE, INDEX_COMMENT, IS_VISIBLE, EXPRESSION FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY INDEX_NAME, SEQ_IN_INDEX;", stmt.String())
+}
+
 func TestGetIndexByType(t *testing.T) {
 	ms := New()
 	require.Equal(t, "FULLTEXT INDEX", ms.getIndexByType(indexes.FullText))
@@ -35,3 +76,43 @@ func TestGetIndexByType(t *testing.T) {
 	require.Equal(t, "PRIMARY KEY", ms.getIndexByType(indexes.Primary))
 	require.Equal(t, "INDEX", ms.getIndexByType(0))
 }
+
+func TestCreateIndexes(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	{
+		ms.CreateIndexes(stmt, "db", "table", []indexes.Index{
+			{Name: "IX_Name", Columns: indexes.Columns("Name(20)", "-Age")},
+		}, true)
+		require.Equal(t, "ALTER TABLE `db`.`table` ADD INDEX `IX_Name` (`Name`(20),`Age` DESC);", stmt.String())
+	}
+
+	stmt.Reset()
+
+	{
+		ms.CreateIndexes(stmt, "db", "table", []indexes.Index{
+			{Name: "IX_Email", Columns: []indexes.Col{indexes.Expr("LOWER(`Email`)", indexes.Descending)}, Using: "btree", KeyBlockSize: 8, Comment: "lower email", Visibility: indexes.Invisible, Algorithm: "inplace", Lock: "none"},
+			{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio"), Parser: "ngram", Visibility: indexes.Visible, Algorithm: "copy"},
+		}, false)
+		require.Equal(t, "ALTER TABLE `db`.`table` "+
+			"ADD INDEX `IX_Email` USING BTREE ((LOWER(`Email`))) KEY_BLOCK_SIZE = 8 COMMENT \"lower email\" INVISIBLE, "+
+			"ADD FULLTEXT INDEX `FTX_Bio` (`Bio`) WITH PARSER ngram VISIBLE, ALGORITHM = INPLACE, LOCK = NONE;", stmt.String())
+	}
+}
+
+func TestAlterIndexes(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	ms.AlterIndexes(stmt, "db", "table", []string{"PRIMARY", "IX_Name", "IX_Legacy"}, []indexes.Index{
+		{Name: "IX_Name", Columns: indexes.Columns("Name", "-Age")},
+	}, true)
+	require.Equal(t, "ALTER TABLE `db`.`table` DROP INDEX `IX_Name`, DROP INDEX `IX_Legacy`, ADD INDEX `IX_Name` (`Name`,`Age` DESC);", stmt.String())
+
+	stmt.Reset()
+	ms.AlterIndexes(stmt, "db", "table", []string{"IX_Legacy"}, nil, true)
+	require.Equal(t, "ALTER TABLE `db`.`table` DROP INDEX `IX_Legacy`;", stmt.String())
+}
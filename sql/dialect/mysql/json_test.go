@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/Oskang09/sqlike/sql"
+	"github.com/Oskang09/sqlike/sql/expr"
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFunction(t *testing.T) {
+	ms := New()
+
+	for _, tc := range []struct {
+		it     interface{}
+		result string
+		args   []interface{}
+	}{
+		{
+			it:     expr.JSON_ARRAY_APPEND(expr.Column("Tags"), "$", expr.Column("Name")),
+			result: "JSON_ARRAY_APPEND(`Tags`,?,`Name`)",
+			args:   []interface{}{"$"},
+		},
+		{
+			it:     expr.JSON_ARRAY_INSERT(expr.Column("Tags"), "$[0]", expr.Column("Name")),
+			result: "JSON_ARRAY_INSERT(`Tags`,?,`Name`)",
+			args:   []interface{}{"$[0]"},
+		},
+		{
+			it:     expr.JSON_MERGE_PRESERVE(expr.Column("A"), `{"a":1}`),
+			result: "JSON_MERGE_PRESERVE(`A`,?)",
+			args:   []interface{}{`{"a":1}`},
+		},
+		{
+			it:     expr.JSON_LENGTH(expr.Column("A"), "$.list"),
+			result: "JSON_LENGTH(`A`,?)",
+			args:   []interface{}{"$.list"},
+		},
+		{
+			it:     expr.JSON_DEPTH(expr.Column("A")),
+			result: "JSON_DEPTH(`A`)",
+		},
+		{
+			it:     expr.JSON_SEARCH(expr.Column("A"), "all", "abc%", "$.a", "$.b"),
+			result: "JSON_SEARCH(`A`,?,?,?,?,?)",
+			args:   []interface{}{"all", "abc%", nil, "$.a", "$.b"},
+		},
+		{
+			it:     expr.JSON_CONTAINS_PATH(expr.Column("A"), "one", "$.a", "$.b"),
+			result: "JSON_CONTAINS_PATH(`A`,?,?,?)",
+			args:   []interface{}{"one", "$.a", "$.b"},
+		},
+		{
+			it:     expr.JSON_OVERLAPS(expr.JSONColumn("A", "zipcode"), `[94507, 94582]`),
+			result: "JSON_OVERLAPS(`A`->'$.zipcode',?)",
+			args:   []interface{}{`[94507, 94582]`},
+		},
+		{
+			it:     expr.JSON_OBJECT("id", expr.Column("ID"), "n", 1),
+			result: "JSON_OBJECT(?,`ID`,?,?)",
+			args:   []interface{}{"id", "n", int64(1)},
+		},
+		{
+			it:     expr.JSON_ARRAY(1, "a", expr.Column("B")),
+			result: "JSON_ARRAY(?,?,`B`)",
+			args:   []interface{}{int64(1), "a"},
+		},
+		{
+			it:     expr.JSON_ARRAYAGG("Name"),
+			result: "JSON_ARRAYAGG(`Name`)",
+		},
+		{
+			it:     expr.JSON_OBJECTAGG("ID", "Name"),
+			result: "JSON_OBJECTAGG(`ID`,`Name`)",
+		},
+	} {
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		require.NoError(t, ms.parser.BuildStatement(stmt, tc.it))
+		require.Equal(t, tc.result, stmt.String())
+		require.Equal(t, tc.args, stmt.Args())
+		sqlstmt.ReleaseStmt(stmt)
+	}
+
+	t.Run("JSON_TABLE", func(t *testing.T) {
+		name := expr.JSONTableColumn("name", "VARCHAR(100)", "$.name")
+		name.OnEmpty = "DEFAULT 'it''s empty'"
+		name.OnError = "NULL"
+
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt)
+		require.NoError(t, ms.parser.BuildStatement(stmt, sql.Select(
+			expr.Column("jt", "id"),
+			expr.Column("jt", "name"),
+			expr.Column("jt", "tag"),
+		).From(
+			expr.JSON_TABLE(
+				`[{"name":"a","tags":["x"]}]`, "$[*]", "jt",
+				expr.JSONTableOrdinality("id"),
+				name,
+				expr.JSONTableExists("has_tags", "INT", "$.tags"),
+				expr.JSONTableNested("$.tags[*]",
+					expr.JSONTableColumn("tag", "VARCHAR(10)", "$"),
+				),
+			),
+		).Where(
+			expr.Equal(expr.Column("jt", "has_tags"), 1),
+		)))
+		require.Equal(t, "SELECT `jt`.`id`,`jt`.`name`,`jt`.`tag` FROM JSON_TABLE(?,'$[*]' COLUMNS ("+
+			"`id` FOR ORDINALITY,"+
+			"`name` VARCHAR(100) PATH '$.name' DEFAULT 'it''s empty' ON EMPTY NULL ON ERROR,"+
+			"`has_tags` INT EXISTS PATH '$.tags',"+
+			"NESTED PATH '$.tags[*]' COLUMNS (`tag` VARCHAR(10) PATH '$'))) AS `jt` "+
+			"WHERE `jt`.`has_tags` = ?", stmt.String())
+		require.Equal(t, []interface{}{`[{"name":"a","tags":["x"]}]`, int64(1)}, stmt.Args())
+
+		require.Panics(t, func() {
+			expr.JSON_TABLE(expr.Column("A"), "$", "jt")
+		})
+
+		stmt2 := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt2)
+		require.Error(t, ms.parser.BuildStatement(stmt2, expr.JSON_TABLE(
+			expr.Column("A"), "$", "jt", expr.JSONTableColumn("x", "", "$.x"),
+		)))
+	})
+}
@@ -16,11 +16,6 @@ import (
 	"golang.org/x/text/currency"
 )
 
-var charsetMap = map[string]string{
-	"utf8mb4": "utf8mb4_unicode_ci",
-	"latin1":  "latin1_bin",
-}
-
 // mySQLSchema :
 type mySQLSchema struct {
 	sqlutil.MySQLUtil
@@ -68,6 +63,20 @@ func (s mySQLSchema) ByteDataType(sf reflext.StructFielder) (col columns.Column)
 	col.Type = "MEDIUMBLOB"
 	col.Nullable = sf.IsNullable()
 	tag := sf.Tag()
+	// `char=N` is the fixed length `BINARY(N)` and `size=N` is `VARBINARY(N)`, same as the string
+	if char, ok := tag.LookUp("char"); ok {
+		if _, err := strconv.Atoi(char); err != nil {
+			panic("invalid value for binary data type")
+		}
+		col.DataType = "BINARY"
+		col.Type = "BINARY(" + char + ")"
+	} else if size, ok := tag.LookUp("size"); ok {
+		if _, err := strconv.Atoi(size); err != nil {
+			panic("invalid value for varbinary data type")
+		}
+		col.DataType = "VARBINARY"
+		col.Type = "VARBINARY(" + size + ")"
+	}
 	if v, ok := tag.LookUp("default"); ok {
 		col.DefaultValue = &v
 	}
@@ -163,19 +172,19 @@ func (s mySQLSchema) StringDataType(sf reflext.StructFielder) (col columns.Colum
 	col.Name = sf.Name()
 	col.Nullable = sf.IsNullable()
 
-	charset := "utf8mb4"
-	collation := charsetMap[charset]
 	dflt := ""
 	tag := sf.Tag()
 	cs, ok1 := tag.LookUp("charset")
-	if ok1 {
-		charset = strings.ToLower(cs)
-		collation = charsetMap[charset]
-	}
+	cl, ok2 := tag.LookUp("collate")
+	chset, collation := charset.Resolve(cs, cl)
 
 	col.DefaultValue = &dflt
-	col.Charset = &charset
+	col.Charset = &chset
 	col.Collation = &collation
+	// unknown charset without `collate` tag, leave it to the server default
+	if collation == "" {
+		col.Collation = nil
+	}
 	if v, ok := tag.LookUp("default"); ok {
 		col.DefaultValue = &v
 	}
@@ -186,8 +195,8 @@ func (s mySQLSchema) StringDataType(sf reflext.StructFielder) (col columns.Colum
 			panic("invalid enum formats")
 		}
 
-		if !ok1 {
-			charset = "utf8mb4"
+		if !ok1 && !ok2 {
+			chset = "utf8mb4"
 			collation = "utf8mb4_unicode_ci"
 		}
 
@@ -215,12 +224,14 @@ func (s mySQLSchema) StringDataType(sf reflext.StructFielder) (col columns.Colum
 		col.DataType = "CHAR"
 		col.Type = "CHAR(" + char + ")"
 		return
-	} else if _, ok := tag.LookUp("longtext"); ok {
-		col.DataType = "TEXT"
-		col.Type = "TEXT"
+	} else if typ, ok := textType(tag); ok {
+		col.DataType = typ
+		col.Type = typ
 		col.DefaultValue = nil
-		col.Charset = nil
-		col.Collation = nil
+		if !ok1 && !ok2 {
+			col.Charset = nil
+			col.Collation = nil
+		}
 		return
 	}
 
@@ -235,6 +246,29 @@ func (s mySQLSchema) StringDataType(sf reflext.StructFielder) (col columns.Colum
 	return
 }
 
+// textType : `text` tag is `TEXT`, and `text=tiny|medium|long` is the other size of text,
+// `longtext` tag is `TEXT` for backward compatibility
+func textType(tag reflext.StructTag) (string, bool) {
+	if _, ok := tag.LookUp("longtext"); ok {
+		return "TEXT", true
+	}
+	v, ok := tag.LookUp("text")
+	if !ok {
+		return "", false
+	}
+	switch strings.ToLower(v) {
+	case "":
+		return "TEXT", true
+	case "tiny":
+		return "TINYTEXT", true
+	case "medium":
+		return "MEDIUMTEXT", true
+	case "long":
+		return "LONGTEXT", true
+	}
+	panic("invalid value for text data type")
+}
+
 func (s mySQLSchema) CharDataType(sf reflext.StructFielder) (col columns.Column) {
 	dflt := ""
 	switch sf.Type() {
@@ -0,0 +1,105 @@
+package mysql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringDataType(t *testing.T) {
+	type entity struct {
+		Legacy string `sqlike:",longtext"`
+		Text   string `sqlike:",text"`
+		Tiny   string `sqlike:",text=tiny"`
+		Medium string `sqlike:",text=medium"`
+		Long   string `sqlike:",text=long,charset=latin1"`
+		Name   string
+	}
+
+	s := mySQLSchema{}
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	for i, typ := range []string{"TEXT", "TEXT", "TINYTEXT", "MEDIUMTEXT", "LONGTEXT", "VARCHAR(191)"} {
+		col := s.StringDataType(fields[i])
+		require.Equal(t, typ, col.Type, fields[i].Name())
+	}
+	require.Nil(t, s.StringDataType(fields[0]).DefaultValue)
+	require.Equal(t, "latin1", *s.StringDataType(fields[4]).Charset)
+
+	// the character set is derived from the collation
+	type collated struct {
+		Code  string `sqlike:",collate=latin1_general_ci"`
+		Bin   string `sqlike:",collate=binary"`
+		Mixed string `sqlike:",charset=utf8mb4,collate=utf8mb4_bin"`
+	}
+	fields = reflext.DefaultMapper.CodecByType(reflect.TypeOf(collated{})).Properties()
+	for i, result := range [][2]string{
+		{"latin1", "latin1_general_ci"},
+		{"binary", "binary"},
+		{"utf8mb4", "utf8mb4_bin"},
+	} {
+		col := s.StringDataType(fields[i])
+		require.Equal(t, result[0], *col.Charset, fields[i].Name())
+		require.Equal(t, result[1], *col.Collation, fields[i].Name())
+	}
+
+	type invalid struct {
+		Text string `sqlike:",text=huge"`
+	}
+	fields = reflext.DefaultMapper.CodecByType(reflect.TypeOf(invalid{})).Properties()
+	require.Panics(t, func() {
+		s.StringDataType(fields[0])
+	})
+}
+
+func TestByteDataType(t *testing.T) {
+	type entity struct {
+		Hash  []byte `sqlike:",char=16"`
+		Token []byte `sqlike:",size=255"`
+		Raw   []byte
+	}
+
+	s := mySQLSchema{}
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	for i, typ := range []string{"BINARY(16)", "VARBINARY(255)", "MEDIUMBLOB"} {
+		col := s.ByteDataType(fields[i])
+		require.Equal(t, typ, col.Type, fields[i].Name())
+	}
+
+	type invalid struct {
+		Hash []byte `sqlike:",size=large"`
+	}
+	fields = reflext.DefaultMapper.CodecByType(reflect.TypeOf(invalid{})).Properties()
+	require.Panics(t, func() {
+		s.ByteDataType(fields[0])
+	})
+}
+
+func TestNullDataType(t *testing.T) {
+	type entity struct {
+		Count types.Null[int32]
+		Code  types.Null[string]  `sqlike:",size=20,collate=latin1_general_ci"`
+		Score types.Null[float64] `sqlike:",default=1"`
+		At    types.Null[time.Time]
+	}
+
+	ms := New()
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	for i, typ := range []string{"INT", "VARCHAR(20)", "REAL", "DATETIME(6)"} {
+		col, err := ms.schema.GetColumn(nil, fields[i])
+		require.NoError(t, err)
+		require.Equal(t, fields[i].Name(), col.Name)
+		require.Equal(t, typ, col.Type, fields[i].Name())
+		require.True(t, col.Nullable, fields[i].Name())
+	}
+
+	col, _ := ms.schema.GetColumn(nil, fields[1])
+	require.Nil(t, col.DefaultValue)
+	require.Equal(t, "latin1", *col.Charset)
+	require.Equal(t, "latin1_general_ci", *col.Collation)
+	col, _ = ms.schema.GetColumn(nil, fields[2])
+	require.Equal(t, "1", *col.DefaultValue)
+}
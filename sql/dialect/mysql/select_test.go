@@ -8,6 +8,7 @@ import (
 	"github.com/Oskang09/sqlike/sql/expr"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/Oskang09/sqlike/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -92,3 +93,65 @@ func TestSelect(t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestSelectKey(t *testing.T) {
+	ms := New()
+	parent := types.NameKey("Account", "a_b%", nil)
+
+	for _, tc := range []struct {
+		it     interface{}
+		result string
+		args   []interface{}
+	}{
+		{
+			it:     expr.Ancestor("Key", parent),
+			result: "(`Key` = ? OR `Key` LIKE ?)",
+			args:   []interface{}{"Account,'a_b%25'", `Account,'a\_b\%25'/%`},
+		},
+		{
+			it:     expr.Ancestor(expr.Column("u", "Key"), types.IDKey("User", 1, parent)),
+			result: "(`u`.`Key` = ? OR `u`.`Key` LIKE ?)",
+			args:   []interface{}{"Account,'a_b%25'/User,1", `Account,'a\_b\%25'/User,1/%`},
+		},
+		{
+			it:     expr.KeyKind("Key", "User"),
+			result: "SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(`Key`,'/',-1),',',1),':',-1) = ?",
+			args:   []interface{}{"User"},
+		},
+		{
+			it:     expr.KeyKind(expr.Column("u", "Key"), "User"),
+			result: "SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(`u`.`Key`,'/',-1),',',1),':',-1) = ?",
+			args:   []interface{}{"User"},
+		},
+		{
+			it:     expr.KeyNamespace("Key", "tenant_1"),
+			result: "`Key` LIKE ?",
+			args:   []interface{}{`tenant\_1:%`},
+		},
+		{
+			it:     expr.KeyNamespace(expr.Column("u", "Key"), ""),
+			result: "LOCATE(':',SUBSTRING_INDEX(`u`.`Key`,',',1)) = ?",
+			args:   []interface{}{int64(0)},
+		},
+		{
+			it:     expr.Ancestor("Key", &types.Key{Namespace: "tenant", Kind: "Account", IntID: 1}),
+			result: "(`Key` = ? OR `Key` LIKE ?)",
+			args:   []interface{}{"tenant:Account,1", `tenant:Account,1/%`},
+		},
+	} {
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		require.NoError(t, ms.parser.BuildStatement(stmt, tc.it))
+		require.Equal(t, tc.result, stmt.String())
+		require.Equal(t, tc.args, stmt.Args())
+		sqlstmt.ReleaseStmt(stmt)
+	}
+
+	require.Panics(t, func() {
+		expr.Ancestor("Key", nil)
+	})
+	require.Equal(t, "SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(`Key`,'/',-1),',',1),':',-1)", expr.KeyKindIndex("Key").Expr)
+	require.Equal(t, expr.KeyKindIndex("Key"), expr.KeyKindIndex(expr.Column("u", "Key")))
+	require.Panics(t, func() {
+		expr.KeyKindIndex(expr.Raw("Key"))
+	})
+}
@@ -1,15 +1,22 @@
 package mysql
 
 import (
+	"fmt"
+	"hash/crc32"
 	"reflect"
+	"regexp"
 	"strings"
 
+	"github.com/Oskang09/sqlike/jsonb"
 	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sql/dialect"
 	"github.com/Oskang09/sqlike/sql/driver"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sql/util"
 	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
 	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/Oskang09/sqlike/sqlike/options"
 )
 
 // HasPrimaryKey :
@@ -20,6 +27,23 @@ func (ms MySQL) HasPrimaryKey(stmt sqlstmt.Stmt, db, table string) {
 	stmt.AppendArgs(db, table)
 }
 
+// GetCheckConstraints :
+func (ms MySQL) GetCheckConstraints(stmt sqlstmt.Stmt, db, table string) {
+	stmt.WriteString("SELECT CONSTRAINT_NAME FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS ")
+	stmt.WriteString("WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_TYPE = 'CHECK'")
+	stmt.WriteByte(';')
+	stmt.AppendArgs(db, table)
+}
+
+// GetTableOptions : the charset of the table is derived from its collation
+func (ms MySQL) GetTableOptions(stmt sqlstmt.Stmt, db, table string) {
+	stmt.WriteString("SELECT t.ENGINE, c.CHARACTER_SET_NAME, t.TABLE_COLLATION, t.TABLE_COMMENT FROM INFORMATION_SCHEMA.TABLES t ")
+	stmt.WriteString("LEFT JOIN INFORMATION_SCHEMA.COLLATION_CHARACTER_SET_APPLICABILITY c ON c.COLLATION_NAME = t.TABLE_COLLATION ")
+	stmt.WriteString("WHERE t.TABLE_SCHEMA = ? AND t.TABLE_NAME = ?")
+	stmt.WriteByte(';')
+	stmt.AppendArgs(db, table)
+}
+
 // RenameTable :
 func (ms MySQL) RenameTable(stmt sqlstmt.Stmt, db, oldName, newName string) {
 	stmt.WriteString("RENAME TABLE ")
@@ -50,8 +74,20 @@ func (ms MySQL) HasTable(stmt sqlstmt.Stmt, dbName, table string) {
 	stmt.AppendArgs(dbName, table)
 }
 
-// CreateTable :
-func (ms MySQL) CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver.Info, fields []reflext.StructFielder) (err error) {
+// ShowCreateTable :
+func (ms MySQL) ShowCreateTable(stmt sqlstmt.Stmt, dbName, table string) {
+	stmt.WriteString("SHOW CREATE TABLE " + ms.TableName(dbName, table) + ";")
+}
+
+// GetTables :
+func (ms MySQL) GetTables(stmt sqlstmt.Stmt, dbName string) {
+	stmt.WriteString(`SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME;`)
+	stmt.AppendArgs(dbName)
+}
+
+// CreateTable : the indexes declared by the entity are created along with the table, the declared
+// primary key will override the primary key of the fields
+func (ms MySQL) CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver.Info, fields []reflext.StructFielder, idxs []indexes.Index) (err error) {
 	var (
 		col     columns.Column
 		pkk     reflext.StructFielder
@@ -60,6 +96,11 @@ func (ms MySQL) CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver
 		stored  bool
 	)
 
+	checks, err := ms.jsonSchemaChecks(table, info, fields)
+	if err != nil {
+		return
+	}
+
 	stmt.WriteString("CREATE TABLE " + ms.TableName(db, table) + " ")
 	stmt.WriteByte('(')
 
@@ -96,7 +137,7 @@ func (ms MySQL) CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver
 			if len(v) > 60 {
 				panic("maximum length of comment is 60 characters")
 			}
-			stmt.WriteString(" COMMENT '" + v + "'")
+			stmt.WriteString(" COMMENT " + quoteString(v))
 		}
 
 		// check generated columns
@@ -143,28 +184,69 @@ func (ms MySQL) CreateTable(stmt sqlstmt.Stmt, db, table, pk string, info driver
 		}
 
 	}
-	if pkk != nil {
+	if pkk != nil && !hasPrimaryKey(idxs) {
 		stmt.WriteByte(',')
 		stmt.WriteString("PRIMARY KEY (" + ms.Quote(pkk.Name()) + ")")
 	}
+	for _, idx := range idxs {
+		stmt.WriteByte(',')
+		ms.buildIndex(stmt, idx, supportsDesc(info))
+	}
+	for _, chk := range checks {
+		stmt.WriteByte(',')
+		stmt.WriteString(chk.clause)
+	}
 	stmt.WriteByte(')')
-	stmt.WriteString(" ENGINE=INNODB")
+	stmt.WriteString(" ENGINE=INNODB ")
+	ms.buildTableCharset(stmt, info)
+	stmt.WriteByte(';')
+	return
+}
+
+// buildTableCharset : write the default character set and collation of the table
+func (ms *MySQL) buildTableCharset(stmt sqlstmt.Stmt, info driver.Info) {
 	code := string(info.Charset())
 	if code == "" {
-		stmt.WriteString(" CHARACTER SET utf8mb4")
+		stmt.WriteString("CHARACTER SET utf8mb4")
 		stmt.WriteString(" COLLATE utf8mb4_unicode_ci")
-	} else {
-		stmt.WriteString(" CHARACTER SET " + code)
-		if info.Collate() != "" {
-			stmt.WriteString(" COLLATE " + info.Collate())
-		}
+		return
+	}
+	stmt.WriteString("CHARACTER SET " + code)
+	if info.Collate() != "" {
+		stmt.WriteString(" COLLATE " + info.Collate())
 	}
-	stmt.WriteByte(';')
-	return
 }
 
-// AlterTable :
-func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool, info driver.Info, fields []reflext.StructFielder, cols util.StringSlice, idxs util.StringSlice, unsafe bool) (err error) {
+// AlterTable : the character set and collation of the existing columns will be kept,
+// unless `ConvertCharset` is enabled, then the columns will be converted by `MODIFY` individually
+// instead of `CONVERT TO CHARACTER SET`, so the columns with specific `charset` tag will be respected.
+// Every clause will be classified follows by the InnoDB online DDL, the operations which may lose data
+// or unable to perform with the requested algorithm will be refused.
+// The `CHECK` constraints of `json_schema` tag will be added, and the outdated ones will be dropped.
+// The changed indexes declared by the entity are dropped before altering the columns and recreated afterward.
+func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, in dialect.AlterTableInput) (plan *ddl.Plan, err error) {
+	var (
+		db, table, pk = in.DB, in.Table, in.PK
+		hasPk         = in.HasPK
+		info          = in.Info
+		fields        = in.Fields
+		existing      = in.Columns
+		idxs          = in.Indexes
+		checks        = in.Checks
+		dropIdxs      = in.DropIndexes
+		addIdxs       = in.AddIndexes
+		unsafe        = in.Unsafe
+		opt           = in.Options
+	)
+	if opt == nil {
+		opt = options.Migrate()
+	}
+
+	cols := make(util.StringSlice, len(existing))
+	for i, c := range existing {
+		cols[i] = c.Name
+	}
+
 	var (
 		col     columns.Column
 		pkk     reflext.StructFielder
@@ -172,22 +254,52 @@ func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool,
 		k1, k2  string
 		virtual bool
 		stored  bool
+		ops     []ddl.Operation
+		offset  int
 	)
 
-	suffix := "FIRST"
-	stmt.WriteString("ALTER TABLE " + ms.TableName(db, table) + " ")
+	// definitions of the added and modified columns, key by the index of operation
+	defs := make(map[int]columns.Column)
+	keys := make(map[int]indexes.Index)
+	generated := make(map[int]string)
+	planner := newPlanner(info.Version(), existing)
 
-	for i, sf := range fields {
-		if i > 0 {
+	begin := func() {
+		if len(ops) > 0 {
 			stmt.WriteByte(',')
 		}
-
-		action := "ADD"
-		idx = cols.IndexOf(sf.Name())
+		offset = len(stmt.String())
+	}
+	end := func(typ ddl.OperationType, name string) {
+		ops = append(ops, ddl.Operation{
+			Type:   typ,
+			Name:   name,
+			Clause: stmt.String()[offset:],
+		})
+	}
+	action := func(name string) (string, ddl.OperationType) {
+		idx = cols.IndexOf(name)
 		if idx > -1 {
-			action = "MODIFY"
 			cols.Splice(idx)
+			return "MODIFY", ddl.ModifyColumn
+		}
+		return "ADD", ddl.AddColumn
+	}
+
+	suffix := "FIRST"
+	stmt.WriteString("ALTER TABLE " + ms.TableName(db, table) + " ")
+
+	// the primary key will never be dropped
+	for _, name := range dropIdxs {
+		if name == "PRIMARY" {
+			continue
 		}
+		begin()
+		stmt.WriteString("DROP INDEX " + ms.Quote(name))
+		end(ddl.DropIndex, name)
+	}
+
+	for _, sf := range fields {
 		if !hasPk {
 			// allow primary_key tag to override
 			if _, ok := sf.Tag().LookUp("primary_key"); ok {
@@ -204,27 +316,37 @@ func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool,
 		if ok1 || ok2 {
 			idx := indexes.Index{Columns: indexes.Columns(sf.Name())}
 			if idxs.IndexOf(idx.GetName()) < 0 {
+				begin()
 				stmt.WriteString("ADD")
 				stmt.WriteString(" UNIQUE INDEX " + idx.GetName() + " (" + ms.Quote(sf.Name()) + ")")
-				stmt.WriteByte(',')
+				end(ddl.AddIndex, idx.GetName())
 			}
 		}
-		stmt.WriteString(action + " ")
+
+		begin()
+		act, typ := action(sf.Name())
+		stmt.WriteString(act + " ")
 		col, err = ms.schema.GetColumn(info, sf)
 		if err != nil {
 			return
 		}
+		if c, ok := planner.columns[sf.Name()]; ok && !opt.ConvertCharset {
+			keepCharset(&col, c)
+		}
 		ms.buildSchemaByColumn(stmt, col)
 
 		if v, ok := sf.Tag().LookUp("comment"); ok {
 			if len(v) > 60 {
 				panic("maximum length of comment is 60 characters")
 			}
-			stmt.WriteString(" COMMENT '" + v + "'")
+			stmt.WriteString(" COMMENT " + quoteString(v))
 		}
 
 		stmt.WriteString(" " + suffix)
 		suffix = "AFTER " + ms.Quote(sf.Name())
+		defs[len(ops)] = col
+		planner.order = append(planner.order, sf.Name())
+		end(typ, sf.Name())
 
 		// check generated columns
 		t := reflext.Deref(sf.Type())
@@ -239,7 +361,7 @@ func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool,
 			k1, virtual = tg.LookUp("virtual_column")
 			k2, stored = tg.LookUp("stored_column")
 			if virtual || stored {
-				stmt.WriteByte(',')
+				begin()
 				col, err = ms.schema.GetColumn(info, child)
 				if err != nil {
 					return
@@ -252,28 +374,31 @@ func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool,
 				if stored && k2 != "" {
 					name = k2
 				}
+				col.Name = name
 
-				action = "ADD"
-				idx = cols.IndexOf(name)
-				if idx > -1 {
-					action = "MODIFY"
-					cols.Splice(idx)
-				}
-
-				stmt.WriteString(action + " ")
+				act, typ := action(name)
+				stmt.WriteString(act + " ")
 				stmt.WriteString(ms.Quote(name))
 				stmt.WriteString(" " + col.Type)
 				path := strings.TrimLeft(strings.TrimPrefix(child.Name(), sf.Name()), ".")
 				stmt.WriteString(" AS ")
 				stmt.WriteString("(" + ms.Quote(sf.Name()) + "->>'$." + path + "')")
+				generated[len(ops)] = "VIRTUAL"
 				if stored {
 					stmt.WriteString(" STORED")
+					generated[len(ops)] = "STORED"
 				}
 				if !col.Nullable {
 					stmt.WriteString(" NOT NULL")
 				}
 				stmt.WriteString(" " + suffix)
 				suffix = "AFTER " + ms.Quote(name)
+
+				// generated column doesn't have the character set and default value
+				col.Charset, col.Collation, col.DefaultValue, col.Extra = nil, nil, nil, ""
+				defs[len(ops)] = col
+				planner.order = append(planner.order, name)
+				end(typ, name)
 			}
 			children = children[1:]
 			children = append(children, child.Children()...)
@@ -281,23 +406,201 @@ func (ms *MySQL) AlterTable(stmt sqlstmt.Stmt, db, table, pk string, hasPk bool,
 
 	}
 
-	if pkk != nil {
-		stmt.WriteByte(',')
+	if pkk != nil && !hasPrimaryKey(addIdxs) {
+		begin()
 		stmt.WriteString("ADD PRIMARY KEY (" + ms.Quote(pkk.Name()) + ")")
+		end(ddl.AddPrimaryKey, pkk.Name())
+	}
+
+	for _, idx := range addIdxs {
+		begin()
+		stmt.WriteString("ADD ")
+		ms.buildIndex(stmt, idx, supportsDesc(info))
+		typ := ddl.AddIndex
+		if idx.Type == indexes.Primary {
+			typ = ddl.AddPrimaryKey
+		}
+		keys[len(ops)] = idx
+		end(typ, idx.GetName())
+	}
+
+	schemaChecks, err := ms.jsonSchemaChecks(table, info, fields)
+	if err != nil {
+		return
+	}
+	names := make(util.StringSlice, len(schemaChecks))
+	for i, chk := range schemaChecks {
+		names[i] = chk.name
+	}
+	// only the constraints which created by `json_schema` tag will be dropped
+	for _, name := range checks {
+		if names.IndexOf(name) < 0 && jsonSchemaCheckRegex.MatchString(name) {
+			begin()
+			stmt.WriteString("DROP CHECK " + ms.Quote(name))
+			end(ddl.DropCheck, name)
+		}
+	}
+	for _, chk := range schemaChecks {
+		if checks.IndexOf(chk.name) < 0 {
+			begin()
+			stmt.WriteString("ADD " + chk.clause)
+			end(ddl.AddCheck, chk.name)
+		}
 	}
 
 	if unsafe {
 		for _, col := range cols {
-			stmt.WriteByte(',')
+			begin()
 			stmt.WriteString("DROP COLUMN ")
 			stmt.WriteString(ms.Quote(col))
+			end(ddl.DropColumn, col)
 		}
 	}
 
-	// TODO: character set
-	stmt.WriteByte(',')
-	stmt.WriteString(`CHARACTER SET utf8mb4`)
-	stmt.WriteString(` COLLATE utf8mb4_unicode_ci`)
+	// every column has its own character set, so the table default can be skipped for instant DDL
+	if opt.Algorithm != ddl.Instant {
+		begin()
+		ms.buildTableCharset(stmt, info)
+		end(ddl.TableOption, "CHARACTER SET")
+	}
+
+	plan = &ddl.Plan{Table: ms.TableName(db, table)}
+	refused := make([]ddl.Operation, 0)
+	for i := range ops {
+		op := &ops[i]
+		switch op.Type {
+		case ddl.AddColumn:
+			planner.addColumn(op, defs[i], generated[i])
+		case ddl.ModifyColumn:
+			old := planner.columns[op.Name]
+			if generated[i] != "" {
+				old.Charset, old.Collation = nil, nil
+			}
+			planner.modifyColumn(op, old, defs[i])
+		case ddl.DropColumn:
+			planner.dropColumn(op)
+		case ddl.AddIndex:
+			planner.addIndex(op, keys[i])
+		case ddl.AddCheck:
+			op.Algorithm, op.Lock = ddl.Copy, ddl.SharedLock
+			op.Reason = "existing rows are validated by copying the table"
+		case ddl.DropCheck:
+			op.Algorithm, op.Lock = planner.metadata(), ddl.NoneLock
+		default:
+			op.Algorithm, op.Lock = ddl.Inplace, ddl.NoneLock
+		}
+
+		plan.Algorithm = maxAlgorithm(plan.Algorithm, op.Algorithm)
+		if op.Lock > plan.Lock {
+			plan.Lock = op.Lock
+		}
+		if refuse(op, opt) {
+			refused = append(refused, *op)
+		}
+	}
+	plan.Operations = ops
+
+	switch opt.Algorithm {
+	case ddl.Instant:
+		stmt.WriteString(",ALGORITHM=INSTANT")
+	case ddl.Inplace:
+		stmt.WriteString(",ALGORITHM=INPLACE,LOCK=NONE")
+	case ddl.Copy:
+		stmt.WriteString(",ALGORITHM=COPY")
+	}
 	stmt.WriteByte(';')
+	plan.Statement = stmt.String()
+
+	if len(refused) > 0 {
+		err = &ddl.Error{Table: plan.Table, Operations: refused}
+	}
 	return
 }
+
+func hasPrimaryKey(idxs []indexes.Index) bool {
+	for _, idx := range idxs {
+		if idx.Type == indexes.Primary {
+			return true
+		}
+	}
+	return false
+}
+
+// refuse : whether the operation is not allowed by the migrate options, dropping column is
+// always allowed since it's requested by unsafe migration
+func refuse(op *ddl.Operation, opt *options.MigrateOptions) bool {
+	if op.Destructive && !opt.AllowDestructive && op.Type != ddl.DropColumn {
+		return true
+	}
+	switch opt.Algorithm {
+	case ddl.Instant:
+		return op.Algorithm > ddl.Instant
+	case ddl.Inplace:
+		return op.Algorithm > ddl.Inplace || op.Lock > ddl.NoneLock
+	}
+	return false
+}
+
+var jsonSchemaCheckRegex = regexp.MustCompile(`json_schema_([0-9a-f]{8}_)?[0-9a-f]{8}$`)
+
+// jsonSchemaCheck : the `CHECK` constraint of the column with `json_schema` tag
+type jsonSchemaCheck struct {
+	name   string
+	clause string
+}
+
+// jsonSchemaChecks : `JSON_SCHEMA_VALID` is only supported since mysql 8.0.17, the constraints
+// will be skipped on the older or unknown version, the client side validation still applies
+func (ms *MySQL) jsonSchemaChecks(table string, info driver.Info, fields []reflext.StructFielder) ([]jsonSchemaCheck, error) {
+	if v := info.Version(); v == nil || v.LessThan(jsonSchemaValid) {
+		return nil, nil
+	}
+	checks := make([]jsonSchemaCheck, 0)
+	for _, sf := range fields {
+		v, ok := sf.Tag().LookUp("json_schema")
+		if !ok {
+			continue
+		}
+		schema, err := jsonb.SchemaOf(v)
+		if err != nil {
+			return nil, err
+		}
+		name := jsonSchemaCheckName(table, sf.Name(), schema.String())
+		checks = append(checks, jsonSchemaCheck{
+			name: name,
+			clause: "CONSTRAINT " + ms.Quote(name) +
+				" CHECK (JSON_SCHEMA_VALID(" + quoteString(schema.String()) + "," + ms.Quote(sf.Name()) + "))",
+		})
+	}
+	return checks, nil
+}
+
+// jsonSchemaCheckName : the name of constraint is unique per database, and it will be changed
+// when the schema is changed, so the outdated constraint can be replaced
+func jsonSchemaCheckName(table, column, schema string) string {
+	sum := fmt.Sprintf("%08x", crc32.ChecksumIEEE([]byte(schema)))
+	name := table + "_" + column + "_json_schema_" + sum
+	// maximum length of identifier is 64 characters
+	if len(name) > 64 {
+		name = fmt.Sprintf("json_schema_%08x_%s", crc32.ChecksumIEEE([]byte(table+"."+column)), sum)
+	}
+	return name
+}
+
+// keepCharset : use the character set and collation of the existing column, so the data of the column won't be rewritten.
+// The text column without `charset` tag is kept as well, otherwise it will inherit the default of the table on `MODIFY`.
+func keepCharset(col *columns.Column, existing columns.Column) {
+	if existing.Charset == nil {
+		return
+	}
+	if col.Charset == nil && !strings.HasSuffix(parseColumnType(col.Type).base, "TEXT") {
+		return
+	}
+	charset := *existing.Charset
+	col.Charset = &charset
+	col.Collation = nil
+	if existing.Collation != nil {
+		collation := *existing.Collation
+		col.Collation = &collation
+	}
+}
@@ -1,9 +1,19 @@
 package mysql
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sql/charset"
+	"github.com/Oskang09/sqlike/sql/dialect"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/Oskang09/sqlike/sqlike/options"
 	"github.com/stretchr/testify/require"
 )
 
@@ -17,6 +27,17 @@ func TestHasPrimaryKey(t *testing.T) {
 	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
 }
 
+func TestGetTableOptions(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	ms.GetTableOptions(stmt, "db", "table")
+	require.Equal(t, "SELECT t.ENGINE, c.CHARACTER_SET_NAME, t.TABLE_COLLATION, t.TABLE_COMMENT FROM INFORMATION_SCHEMA.TABLES t LEFT JOIN INFORMATION_SCHEMA.COLLATION_CHARACTER_SET_APPLICABILITY c ON c.COLLATION_NAME = t.TABLE_COLLATION WHERE t.TABLE_SCHEMA = ? AND t.TABLE_NAME = ?;", stmt.String())
+	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
+	require.Equal(t, `'it''s \\ ok'`, ms.QuoteString(`it's \ ok`))
+}
+
 func TestDropTable(t *testing.T) {
 	ms := New()
 	stmt := sqlstmt.AcquireStmt(ms)
@@ -66,3 +87,320 @@ func TestHasTable(t *testing.T) {
 	require.ElementsMatch(t, []interface{}{"db", "table"}, stmt.Args())
 
 }
+
+func TestShowCreateTable(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	ms.ShowCreateTable(stmt, "db", "table")
+	require.Equal(t, "SHOW CREATE TABLE `db`.`table`;", stmt.String())
+	require.Empty(t, stmt.Args())
+}
+
+func TestGetTables(t *testing.T) {
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	ms.GetTables(stmt, "db")
+	require.Equal(t, "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE' ORDER BY TABLE_NAME;", stmt.String())
+	require.ElementsMatch(t, []interface{}{"db"}, stmt.Args())
+}
+
+type testInfo struct {
+	version string
+}
+
+func (testInfo) DriverName() string    { return "mysql" }
+func (testInfo) Charset() charset.Code { return "" }
+func (testInfo) Collate() string       { return "" }
+func (i testInfo) Version() *semver.Version {
+	if i.version == "" {
+		return nil
+	}
+	return semver.MustParse(i.version)
+}
+
+func TestAlterTable(t *testing.T) {
+	type entity struct {
+		ID   int64  `sqlike:",primary_key"`
+		Name string `sqlike:",charset=utf8mb4"`
+		Code string `sqlike:",charset=latin1,collate=latin1_general_ci"`
+		Bio  string
+	}
+
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	latin1, latin1Bin := "latin1", "latin1_bin"
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	existing := []columns.Column{
+		{Name: "ID", DataType: "BIGINT", Type: "BIGINT"},
+		{Name: "Name", DataType: "VARCHAR", Type: "VARCHAR(191)", Charset: &latin1, Collation: &latin1Bin},
+		{Name: "Code", DataType: "VARCHAR", Type: "VARCHAR(191)", Charset: &latin1, Collation: &latin1Bin},
+	}
+
+	// keep the existing character set by default
+	{
+		_, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: testInfo{}, Fields: fields, Columns: existing})
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE `db`.`table` MODIFY `ID` BIGINT NOT NULL DEFAULT '0' FIRST,MODIFY `Name` VARCHAR(191) CHARACTER SET latin1 COLLATE latin1_bin NOT NULL DEFAULT '' AFTER `ID`,MODIFY `Code` VARCHAR(191) CHARACTER SET latin1 COLLATE latin1_bin NOT NULL DEFAULT '' AFTER `Name`,ADD `Bio` VARCHAR(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `Code`,CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;", stmt.String())
+	}
+
+	stmt.Reset()
+
+	// convert to the character set of the tag
+	{
+		plan, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: testInfo{}, Fields: fields, Columns: existing, Options: options.Migrate().SetConvertCharset(true)})
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE `db`.`table` MODIFY `ID` BIGINT NOT NULL DEFAULT '0' FIRST,MODIFY `Name` VARCHAR(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `ID`,MODIFY `Code` VARCHAR(191) CHARACTER SET latin1 COLLATE latin1_general_ci NOT NULL DEFAULT '' AFTER `Name`,ADD `Bio` VARCHAR(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `Code`,CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci;", stmt.String())
+		require.Equal(t, ddl.Copy, plan.Algorithm)
+		require.Equal(t, ddl.Copy, plan.Operations[1].Algorithm)
+		require.Equal(t, "charset: latin1 latin1_bin => utf8mb4 utf8mb4_unicode_ci", plan.Operations[1].Reason)
+		require.False(t, plan.Operations[1].Destructive)
+	}
+}
+
+func TestAlterTableText(t *testing.T) {
+	type entity struct {
+		ID  int64  `sqlike:",primary_key"`
+		Bio string `sqlike:",longtext"`
+	}
+
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	latin1, latin1Bin := "latin1", "latin1_bin"
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	existing := []columns.Column{
+		{Name: "ID", DataType: "BIGINT", Type: "BIGINT"},
+		{Name: "Bio", DataType: "TEXT", Type: "TEXT", Charset: &latin1, Collation: &latin1Bin},
+	}
+
+	// the text column without `charset` tag won't inherit the default of the table
+	{
+		_, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: testInfo{}, Fields: fields, Columns: existing})
+		require.NoError(t, err)
+		require.Contains(t, stmt.String(), "MODIFY `Bio` TEXT CHARACTER SET latin1 COLLATE latin1_bin NOT NULL AFTER `ID`")
+	}
+
+	stmt.Reset()
+
+	{
+		_, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: testInfo{}, Fields: fields, Columns: existing, Options: options.Migrate().SetConvertCharset(true)})
+		require.NoError(t, err)
+		require.Contains(t, stmt.String(), "MODIFY `Bio` TEXT NOT NULL AFTER `ID`")
+	}
+}
+
+func TestAlterTablePlan(t *testing.T) {
+	type entity struct {
+		ID     int64  `sqlike:",primary_key"`
+		Name   string `sqlike:",size=60"`
+		Status string `sqlike:",enum=A|B|C"`
+		Age    int
+	}
+
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	utf8mb4, collate := "utf8mb4", "utf8mb4_unicode_ci"
+	info := testInfo{version: "8.0.20"}
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	existing := []columns.Column{
+		{Name: "ID", DataType: "bigint", Type: "bigint(20)"},
+		{Name: "Name", DataType: "varchar", Type: "varchar(50)", Charset: &utf8mb4, Collation: &collate},
+		{Name: "Status", DataType: "enum", Type: "enum('A','B')", Charset: &utf8mb4, Collation: &collate},
+		{Name: "Legacy", DataType: "int", Type: "int(11)"},
+	}
+
+	// classify the operations
+	{
+		plan, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Unsafe: true})
+		require.NoError(t, err)
+		require.Equal(t, `alter table `+"`db`.`table`"+` (algorithm: INPLACE, lock: NONE)
+  INSTANT MODIFY `+"`ID`"+` BIGINT NOT NULL DEFAULT '0' FIRST
+  INPLACE MODIFY `+"`Name`"+` VARCHAR(60) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `+"`ID`"+` -- type: varchar(50) => VARCHAR(60)
+  INSTANT MODIFY `+"`Status`"+` ENUM('A','B','C') CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'A' AFTER `+"`Name`"+` -- type: enum('A','B') => ENUM('A','B','C')
+  INSTANT ADD `+"`Age`"+` INT NOT NULL DEFAULT '0' AFTER `+"`Status`"+`
+  INPLACE DESTRUCTIVE DROP COLUMN `+"`Legacy`"+` -- column data will be deleted
+  INPLACE CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci
+`, plan.String())
+		require.Equal(t, ddl.Inplace, plan.Algorithm)
+		require.Equal(t, ddl.NoneLock, plan.Lock)
+		require.Len(t, plan.Destructive(), 1)
+	}
+
+	stmt.Reset()
+
+	// online DDL
+	{
+		plan, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Unsafe: true, Options: options.Migrate().SetAlgorithm(ddl.Inplace)})
+		require.NoError(t, err)
+		require.Equal(t, "ALTER TABLE `db`.`table` MODIFY `ID` BIGINT NOT NULL DEFAULT '0' FIRST,MODIFY `Name` VARCHAR(60) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' AFTER `ID`,MODIFY `Status` ENUM('A','B','C') CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT 'A' AFTER `Name`,ADD `Age` INT NOT NULL DEFAULT '0' AFTER `Status`,DROP COLUMN `Legacy`,CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci,ALGORITHM=INPLACE,LOCK=NONE;", plan.Statement)
+	}
+
+	stmt.Reset()
+
+	// refuse the operations which are not instant
+	{
+		_, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Unsafe: true, Options: options.Migrate().SetAlgorithm(ddl.Instant)})
+		require.Error(t, err)
+		require.Equal(t, "ddl: refused to alter table `db`.`table`: MODIFY COLUMN Name [INPLACE] (type: varchar(50) => VARCHAR(60)), DROP COLUMN Legacy [INPLACE, DESTRUCTIVE] (column data will be deleted)", err.Error())
+	}
+
+	stmt.Reset()
+
+	// refuse narrowing the data type unless it's allowed
+	{
+		existing[1].Type = "varchar(191)"
+		_, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing})
+		require.Error(t, err)
+		de, ok := err.(*ddl.Error)
+		require.True(t, ok)
+		require.Len(t, de.Operations, 1)
+		require.Equal(t, "Name", de.Operations[0].Name)
+
+		stmt.Reset()
+		_, err = ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Options: options.Migrate().SetAllowDestructive(true)})
+		require.NoError(t, err)
+	}
+}
+
+func TestEntityIndexes(t *testing.T) {
+	type entity struct {
+		ID   int64 `sqlike:",primary_key"`
+		Name string
+		Age  int
+		Bio  string
+	}
+
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	info := testInfo{version: "8.0.20"}
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	idxs := []indexes.Index{
+		{Name: "IX_Name_Age", Columns: indexes.Columns("Name", "-Age")},
+		{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio")},
+	}
+
+	// create the indexes along with the table
+	{
+		require.NoError(t, ms.CreateTable(stmt, "db", "table", "ID", info, fields, idxs))
+		require.Contains(t, stmt.String(), ",PRIMARY KEY (`ID`),INDEX `IX_Name_Age` (`Name`,`Age` DESC),FULLTEXT INDEX `FTX_Bio` (`Bio`)) ENGINE=INNODB")
+	}
+
+	stmt.Reset()
+
+	// the declared primary key overrides the primary key of the fields
+	{
+		pk := indexes.Index{Type: indexes.Primary, Columns: indexes.Columns("ID", "Name")}
+		require.NoError(t, ms.CreateTable(stmt, "db", "table", "ID", info, fields, []indexes.Index{pk}))
+		require.Contains(t, stmt.String(), ",PRIMARY KEY (`ID`,`Name`)) ENGINE=INNODB")
+		require.NotContains(t, stmt.String(), "PRIMARY KEY (`ID`)")
+	}
+
+	stmt.Reset()
+
+	// recreate the changed index within the same statement
+	{
+		existing := []columns.Column{
+			{Name: "ID", DataType: "bigint", Type: "bigint"},
+			{Name: "Name", DataType: "varchar", Type: "varchar(191)"},
+			{Name: "Age", DataType: "int", Type: "int"},
+			{Name: "Bio", DataType: "varchar", Type: "varchar(191)"},
+		}
+		plan, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Indexes: []string{"PRIMARY", "IX_Name_Age"}, DropIndexes: []string{"IX_Name_Age"}, AddIndexes: idxs})
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(plan.Statement, "ALTER TABLE `db`.`table` DROP INDEX `IX_Name_Age`,MODIFY `ID`"))
+		require.Contains(t, plan.Statement, ",ADD INDEX `IX_Name_Age` (`Name`,`Age` DESC),ADD FULLTEXT INDEX `FTX_Bio` (`Bio`),")
+
+		ops := plan.Operations
+		require.Equal(t, ddl.DropIndex, ops[0].Type)
+		require.Equal(t, ddl.Inplace, ops[0].Algorithm)
+		require.Equal(t, ddl.AddIndex, ops[5].Type)
+		require.Equal(t, ddl.NoneLock, ops[5].Lock)
+		require.Equal(t, ddl.AddIndex, ops[6].Type)
+		require.Equal(t, ddl.SharedLock, ops[6].Lock)
+		require.Equal(t, "fulltext index", ops[6].Reason)
+		require.Equal(t, ddl.SharedLock, plan.Lock)
+
+		// refuse the fulltext index which blocks the concurrent DML
+		stmt.Reset()
+		_, err = ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Indexes: []string{"PRIMARY", "IX_Name_Age"}, DropIndexes: []string{"IX_Name_Age"}, AddIndexes: idxs, Options: options.Migrate().SetAlgorithm(ddl.Inplace)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ADD INDEX FTX_Bio [INPLACE] (fulltext index)")
+	}
+}
+
+func TestJSONSchemaCheck(t *testing.T) {
+	type entity struct {
+		ID   int64    `sqlike:",primary_key"`
+		Tags []string `json_schema:"{\"type\": \"array\", \"items\": {\"type\": \"string\", \"pattern\": \"^[a-z']+$\"}}"`
+	}
+
+	ms := New()
+	stmt := sqlstmt.AcquireStmt(ms)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	info := testInfo{version: "8.0.20"}
+	fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+	name := jsonSchemaCheckName("table", "Tags", `{"type":"array","items":{"type":"string","pattern":"^[a-z']+$"}}`)
+	check := "CONSTRAINT `" + name + "` CHECK (JSON_SCHEMA_VALID('{\"type\":\"array\",\"items\":{\"type\":\"string\",\"pattern\":\"^[a-z'']+$\"}}',`Tags`))"
+	require.Regexp(t, `^table_Tags_json_schema_[0-9a-f]{8}$`, name)
+	require.Regexp(t, jsonSchemaCheckRegex, jsonSchemaCheckName(strings.Repeat("t", 60), "Tags", "{}"))
+
+	{
+		ms.GetCheckConstraints(stmt, "db", "table")
+		require.Equal(t, "SELECT CONSTRAINT_NAME FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND CONSTRAINT_TYPE = 'CHECK';", stmt.String())
+		require.Equal(t, []interface{}{"db", "table"}, stmt.Args())
+	}
+
+	stmt.Reset()
+
+	// add the constraint when creating the table
+	{
+		require.NoError(t, ms.CreateTable(stmt, "db", "table", "ID", info, fields, nil))
+		require.Contains(t, stmt.String(), "PRIMARY KEY (`ID`),"+check+") ENGINE=INNODB")
+	}
+
+	stmt.Reset()
+
+	// `JSON_SCHEMA_VALID` is not supported
+	{
+		require.NoError(t, ms.CreateTable(stmt, "db", "table", "ID", testInfo{version: "5.7.30"}, fields, nil))
+		require.NotContains(t, stmt.String(), "CHECK")
+	}
+
+	stmt.Reset()
+
+	// replace the outdated constraint, and keep the others
+	{
+		existing := []columns.Column{
+			{Name: "ID", DataType: "bigint", Type: "bigint"},
+			{Name: "Tags", DataType: "json", Type: "json"},
+		}
+		plan, err := ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Checks: []string{"table_Tags_json_schema_0000abcd", "table_chk_1"}})
+		require.NoError(t, err)
+		require.Contains(t, plan.Statement, ",DROP CHECK `table_Tags_json_schema_0000abcd`,ADD "+check+",")
+		require.NotContains(t, plan.Statement, "table_chk_1")
+		require.Equal(t, ddl.Copy, plan.Algorithm)
+		require.Equal(t, ddl.SharedLock, plan.Lock)
+
+		stmt.Reset()
+		plan, err = ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Checks: []string{name}})
+		require.NoError(t, err)
+		require.NotContains(t, plan.Statement, "CHECK")
+
+		stmt.Reset()
+		_, err = ms.AlterTable(stmt, dialect.AlterTableInput{DB: "db", Table: "table", PK: "ID", HasPK: true, Info: info, Fields: fields, Columns: existing, Options: options.Migrate().SetAlgorithm(ddl.Inplace)})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "ADD CHECK "+name+" [COPY]")
+	}
+}
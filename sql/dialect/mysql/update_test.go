@@ -0,0 +1,111 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/sql/expr"
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/Oskang09/sqlike/sqlike/actions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateJSON(t *testing.T) {
+	type address struct {
+		Line1    string
+		Line2    string
+		Postcode string
+	}
+
+	type profile struct {
+		Name    string
+		Tags    []string
+		Address *address
+		Extra   map[string]interface{}
+	}
+
+	ms := New()
+	update := func(stmt actions.UpdateStatement) *actions.UpdateActions {
+		act := stmt.(*actions.UpdateActions)
+		act.Database = "sqlike"
+		act.Table = "User"
+		return act
+	}
+
+	t.Run("JSONDiff", func(t *testing.T) {
+		old := profile{
+			Name:    "John",
+			Tags:    []string{"a", "b", "c"},
+			Address: &address{Line1: "1", Postcode: "100"},
+			Extra:   map[string]interface{}{"x": 1},
+		}
+		new := old
+		new.Name = "Doe"
+		new.Tags = []string{"a", "b"}
+		new.Address = &address{Line1: "1", Line2: "2", Postcode: "100"}
+		new.Extra = map[string]interface{}{"y": true, "x": 1}
+
+		kvs, err := expr.JSONDiff("Profile", old, new)
+		require.NoError(t, err)
+		require.Len(t, kvs, 1)
+
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt)
+		err = ms.Update(stmt, update(actions.Update().
+			Where(expr.Equal("ID", 1)).
+			Set(kvs...)))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE `sqlike`.`User` SET `Profile` = "+
+			"JSON_SET(JSON_REPLACE(JSON_REMOVE(`Profile`,?),?,CAST(? AS JSON),?,CAST(? AS JSON)),?,CAST(? AS JSON)) "+
+			"WHERE `ID` = ?;", stmt.String())
+		require.Equal(t, []interface{}{
+			"$.Tags[2]",
+			"$.Name", `"Doe"`,
+			"$.Address.Line2", `"2"`,
+			"$.Extra.y", `true`,
+			int64(1),
+		}, stmt.Args())
+	})
+
+	t.Run("JSONDiff without changes", func(t *testing.T) {
+		kvs, err := expr.JSONDiff("Profile", profile{Name: "John"}, profile{Name: "John"})
+		require.NoError(t, err)
+		require.Empty(t, kvs)
+	})
+
+	t.Run("JSONDiff on whole document", func(t *testing.T) {
+		kvs, err := expr.JSONDiff("Profile", nil, profile{Name: "John"})
+		require.NoError(t, err)
+
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt)
+		err = ms.Update(stmt, update(actions.Update().Set(kvs...)))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE `sqlike`.`User` SET `Profile` = ?;", stmt.String())
+		require.Equal(t, []interface{}{`{"Name":"John","Tags":null,"Address":null,"Extra":null}`}, stmt.Args())
+	})
+
+	t.Run("JSONDiffCodec", func(t *testing.T) {
+		codec := jsonb.NewCodec(jsonb.Config{NameFunc: strings.ToLower, OmitEmpty: true})
+		kvs, err := expr.JSONDiffCodec(codec, "Profile", profile{Name: "John"}, profile{Name: "Doe", Tags: []string{"a"}})
+		require.NoError(t, err)
+
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt)
+		err = ms.Update(stmt, update(actions.Update().Set(kvs...)))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE `sqlike`.`User` SET `Profile` = JSON_SET(JSON_REPLACE(`Profile`,?,CAST(? AS JSON)),?,CAST(? AS JSON));", stmt.String())
+		require.Equal(t, []interface{}{"$.name", `"Doe"`, "$.tags", `["a"]`}, stmt.Args())
+	})
+
+	t.Run("JSONMergePatch", func(t *testing.T) {
+		stmt := sqlstmt.AcquireStmt(MySQL{})
+		defer sqlstmt.ReleaseStmt(stmt)
+		err := ms.Update(stmt, update(actions.Update().
+			Set(expr.JSONMergePatch("Profile", `{"Name":"Doe","Extra":null}`))))
+		require.NoError(t, err)
+		require.Equal(t, "UPDATE `sqlike`.`User` SET `Profile` = JSON_MERGE_PATCH(`Profile`,?);", stmt.String())
+		require.Equal(t, []interface{}{`{"Name":"Doe","Extra":null}`}, stmt.Args())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Oskang09/sqlike/util"
@@ -47,3 +48,12 @@ func (ms MySQL) Format(it interface{}) (val string) {
 	}
 	return
 }
+
+// QuoteString : quote the string literal, the backslash and single quote are escaped
+func (ms MySQL) QuoteString(v string) string {
+	return quoteString(v)
+}
+
+func quoteString(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `''`).Replace(v) + "'"
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	semver "github.com/Masterminds/semver/v3"
 	"github.com/Oskang09/sqlike/sql/charset"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sqlike/logs"
@@ -12,6 +13,7 @@ import (
 // Info :
 type Info interface {
 	DriverName() string
+	Version() *semver.Version
 	Charset() charset.Code
 	Collate() string
 }
@@ -34,7 +36,7 @@ func Execute(ctx context.Context, driver Driver, stmt *sqlstmt.Statement, logger
 		stmt.StartTimer()
 		defer func() {
 			stmt.StopTimer()
-			logger.Debug(stmt)
+			logs.Log(ctx, logger, stmt, err)
 		}()
 	}
 	result, err = driver.ExecContext(ctx, stmt.String(), stmt.Args()...)
@@ -47,7 +49,7 @@ func Query(ctx context.Context, driver Driver, stmt *sqlstmt.Statement, logger l
 		stmt.StartTimer()
 		defer func() {
 			stmt.StopTimer()
-			logger.Debug(stmt)
+			logs.Log(ctx, logger, stmt, err)
 		}()
 	}
 	rows, err = driver.QueryContext(ctx, stmt.String(), stmt.Args()...)
@@ -60,7 +62,7 @@ func QueryRowContext(ctx context.Context, driver Driver, stmt *sqlstmt.Statement
 		stmt.StartTimer()
 		defer func() {
 			stmt.StopTimer()
-			logger.Debug(stmt)
+			logs.Log(ctx, logger, stmt, row.Err())
 		}()
 	}
 	row = driver.QueryRowContext(ctx, stmt.String(), stmt.Args()...)
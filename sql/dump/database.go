@@ -0,0 +1,386 @@
+package sqldump
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+)
+
+// DefaultChunkSize : is the maximum number of rows for every chunk when backup the database
+const DefaultChunkSize = 1000
+
+// Progress : is the progress of `BackupDatabase`, it will be reported after every chunk
+type Progress struct {
+	// the table which is backing up
+	Table string
+
+	// the position of the table, start from 1
+	TableNo int
+
+	// the total number of tables which will be backup
+	TotalTables int
+
+	// the number of rows of the table which already backup
+	Rows int64
+
+	// whether the table is completely backup
+	Done bool
+}
+
+// BackupOptions :
+type BackupOptions struct {
+	// Include is the table name patterns which should be backup, all tables will be backup if it's empty
+	Include []string
+
+	// Exclude is the table name patterns which should be skipped, it takes precedence over `Include`
+	Exclude []string
+
+	// Gzip will compress the output in gzip format
+	Gzip bool
+
+	// ChunkSize is the maximum number of rows for every select statement, default is `DefaultChunkSize`
+	ChunkSize uint
+
+	// Progress will be called after every chunk
+	Progress func(Progress)
+}
+
+// BackupOption :
+type BackupOption func(*BackupOptions)
+
+// WithInclude : only backup the tables which match any of the patterns, the syntax of the pattern is same as `path.Match`
+func WithInclude(patterns ...string) BackupOption {
+	return func(opt *BackupOptions) {
+		opt.Include = append(opt.Include, patterns...)
+	}
+}
+
+// WithExclude : skip the tables which match any of the patterns, the syntax of the pattern is same as `path.Match`
+func WithExclude(patterns ...string) BackupOption {
+	return func(opt *BackupOptions) {
+		opt.Exclude = append(opt.Exclude, patterns...)
+	}
+}
+
+// WithGzip : compress the output using gzip
+func WithGzip() BackupOption {
+	return func(opt *BackupOptions) {
+		opt.Gzip = true
+	}
+}
+
+// WithChunkSize : set the maximum number of rows for every select statement
+func WithChunkSize(size uint) BackupOption {
+	return func(opt *BackupOptions) {
+		if size < 1 {
+			panic("chunk size should be greater than zero")
+		}
+		opt.ChunkSize = size
+	}
+}
+
+// WithProgress : set the callback which will be called after every chunk
+func WithProgress(fn func(Progress)) BackupOption {
+	return func(opt *BackupOptions) {
+		opt.Progress = fn
+	}
+}
+
+// connector : *sql.DB and *sqlike.Client satisfy this interface
+type connector interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
+
+// BackupDatabase : backup every table of the database, including the `CREATE TABLE` statement and
+// the indexes. The rows will be selected in chunks which ordered by the primary key, and every
+// statement will be executed in the same transaction using `START TRANSACTION WITH CONSISTENT SNAPSHOT`,
+// so the output is consistent even if there is any write on the database.
+func (d *Dumper) BackupDatabase(ctx context.Context, dbName string, wr io.Writer, opts ...BackupOption) (affected int64, err error) {
+	opt := BackupOptions{ChunkSize: DefaultChunkSize}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	db, ok := d.conn.(connector)
+	if !ok {
+		return 0, errors.New("sqldump: connection is not able to acquire a single connection")
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET SESSION TRANSACTION ISOLATION LEVEL REPEATABLE READ;"); err != nil {
+		return 0, err
+	}
+	if _, err := conn.ExecContext(ctx, "START TRANSACTION WITH CONSISTENT SNAPSHOT;"); err != nil {
+		return 0, err
+	}
+	// the transaction is read only, so it's safe to rollback
+	defer conn.ExecContext(context.Background(), "ROLLBACK;")
+
+	// every query should use the same connection to share the snapshot
+	sd := &Dumper{
+		mu:        new(sync.Mutex),
+		driver:    d.driver,
+		conn:      conn,
+		dialect:   d.dialect,
+		mapper:    d.mapper,
+		batchSize: d.batchSize,
+	}
+
+	version, err := sd.getVersion(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	tables, err := sd.getTables(ctx, dbName)
+	if err != nil {
+		return 0, err
+	}
+	tables = opt.filter(tables)
+
+	out := wr
+	var gz *gzip.Writer
+	if opt.Gzip {
+		gz = gzip.NewWriter(wr)
+		out = gz
+	}
+
+	w := bufio.NewWriter(out)
+	sd.writeHeader(w, version, dbName)
+	for i, table := range tables {
+		progress := Progress{Table: table, TableNo: i + 1, TotalTables: len(tables)}
+		n, err := sd.backupTable(ctx, w, dbName, table, opt, progress)
+		affected += n
+		if err != nil {
+			return affected, err
+		}
+	}
+	sd.writeFooter(w)
+
+	if err := w.Flush(); err != nil {
+		return affected, err
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return affected, err
+		}
+	}
+	return affected, nil
+}
+
+func (d *Dumper) backupTable(ctx context.Context, w *bufio.Writer, dbName, table string, opt BackupOptions, progress Progress) (affected int64, err error) {
+	columns, err := d.getColumns(ctx, dbName, table)
+	if err != nil {
+		return 0, err
+	}
+
+	idxs, err := d.getIndexes(ctx, dbName, table)
+	if err != nil {
+		return 0, err
+	}
+
+	w.WriteString("\n# Table: " + table + "\n\n")
+	if err := d.writeCreateTable(w, table, columns, idxs); err != nil {
+		return 0, err
+	}
+
+	// generated column cannot be inserted
+	cols := make([]string, 0, len(columns))
+	stored := make([]Column, 0, len(columns))
+	for _, col := range columns {
+		if col.Expression != "" {
+			continue
+		}
+		cols = append(cols, col.Name)
+		stored = append(stored, col)
+	}
+
+	var pk []Column
+	for _, idx := range idxs {
+		if idx.Name == "PRIMARY" && idx.hasColumns() {
+			for _, k := range idx.Columns {
+				for _, col := range stored {
+					if col.Name == k.Name {
+						pk = append(pk, col)
+						break
+					}
+				}
+			}
+			break
+		}
+	}
+
+	keys := make([]int, 0, len(pk))
+	for _, k := range pk {
+		for i, col := range cols {
+			if col == k.Name {
+				keys = append(keys, i)
+				break
+			}
+		}
+	}
+
+	quoted := d.dialect.Quote(table)
+	d.writeLockTable(w, quoted)
+
+	var last []interface{}
+	for {
+		stmt := sqlstmt.AcquireStmt(d.dialect)
+		d.selectChunk(stmt, dbName, table, cols, pk, last, opt.ChunkSize)
+		rows, err := d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
+		sqlstmt.ReleaseStmt(stmt)
+		if err != nil {
+			return affected, err
+		}
+
+		n, next, err := d.writeRows(w, quoted, cols, stored, rows, keys)
+		rows.Close()
+		affected += n
+		if err != nil {
+			return affected, err
+		}
+
+		// table without primary key will be selected in single query
+		progress.Rows = affected
+		progress.Done = len(pk) == 0 || n < int64(opt.ChunkSize)
+		if opt.Progress != nil {
+			opt.Progress(progress)
+		}
+		if progress.Done {
+			break
+		}
+		last = next
+	}
+
+	d.writeUnlockTable(w, quoted)
+	return affected, nil
+}
+
+// selectChunk : select the rows after the `last` primary key, eg.
+// SELECT `a`,`b` FROM `db`.`table` WHERE (`a`) > (?) ORDER BY `a` LIMIT 1000;
+func (d *Dumper) selectChunk(stmt sqlstmt.Stmt, dbName, table string, cols []string, pk []Column, last []interface{}, limit uint) {
+	stmt.WriteString("SELECT ")
+	for i, col := range cols {
+		if i > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteString(d.dialect.Quote(col))
+	}
+	stmt.WriteString(" FROM " + d.dialect.TableName(dbName, table))
+	if len(pk) == 0 {
+		stmt.WriteByte(';')
+		return
+	}
+
+	if last != nil {
+		stmt.WriteString(" WHERE (")
+		for i, k := range pk {
+			if i > 0 {
+				stmt.WriteByte(',')
+			}
+			stmt.WriteString(d.dialect.Quote(k.Name))
+		}
+		stmt.WriteString(") > (")
+		for i, k := range pk {
+			if i > 0 {
+				stmt.WriteByte(',')
+			}
+			// decimal is compared with string as double, so cast it to the exact column type
+			if k.DataType == "DECIMAL" || k.DataType == "NUMERIC" {
+				stmt.WriteString("CAST(" + d.dialect.Var(i+1) + " AS " + strings.Fields(k.Type)[0] + ")")
+				continue
+			}
+			stmt.WriteString(d.dialect.Var(i + 1))
+		}
+		stmt.WriteByte(')')
+		stmt.AppendArgs(last...)
+	}
+
+	stmt.WriteString(" ORDER BY ")
+	for i, k := range pk {
+		if i > 0 {
+			stmt.WriteByte(',')
+		}
+		stmt.WriteString(d.dialect.Quote(k.Name))
+	}
+	stmt.WriteString(" LIMIT " + strconv.FormatUint(uint64(limit), 10) + ";")
+}
+
+// cursorValue : bind the primary key with its own type, because MySQL compares integer
+// with string as double, the ids greater than 2^53 will be skipped or repeated between chunks
+func cursorValue(col Column, b sql.RawBytes) (interface{}, error) {
+	switch col.DataType {
+	case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "INTEGER", "BIGINT":
+		if strings.Contains(col.Type, "UNSIGNED") {
+			return strconv.ParseUint(string(b), 10, 64)
+		}
+		return strconv.ParseInt(string(b), 10, 64)
+	case "FLOAT", "DOUBLE", "REAL":
+		return strconv.ParseFloat(string(b), 64)
+	case "BINARY", "VARBINARY", "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB":
+		return append([]byte(nil), b...), nil
+	default:
+		return string(b), nil
+	}
+}
+
+func (d *Dumper) getTables(ctx context.Context, dbName string) ([]string, error) {
+	stmt := sqlstmt.AcquireStmt(d.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	d.dialect.GetTables(stmt, dbName)
+
+	rows, err := d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
+func (opt BackupOptions) filter(tables []string) []string {
+	result := make([]string, 0, len(tables))
+	for _, table := range tables {
+		if len(opt.Include) > 0 && !matchAny(opt.Include, table) {
+			continue
+		}
+		if matchAny(opt.Exclude, table) {
+			continue
+		}
+		result = append(result, table)
+	}
+	return result
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if strings.EqualFold(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -11,6 +11,8 @@ import (
 	"sync"
 	"time"
 
+	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/sql/charset"
 	"github.com/Oskang09/sqlike/sql/driver"
 	"github.com/Oskang09/sqlike/sqlike/actions"
 	"github.com/Oskang09/sqlike/types"
@@ -51,6 +53,12 @@ type Column struct {
 
 	// extra information
 	Extra string
+
+	// expression of generated column
+	Expression string
+
+	// spatial reference system identifier of spatial column
+	SRID *uint32
 }
 
 // Dumper :
@@ -60,6 +68,10 @@ type Dumper struct {
 	conn    driver.Queryer
 	dialect dialect.Dialect
 	mapper  map[string]Parser
+	info    driver.Info
+
+	// batchSize is the maximum number of rows for every insert statement on restore
+	batchSize int
 }
 
 // NewDumper :
@@ -69,20 +81,36 @@ func NewDumper(driver string, conn driver.Queryer) *Dumper {
 	dumper.driver = strings.TrimSpace(strings.ToLower(driver))
 	dumper.conn = conn
 	dumper.dialect = dialect.GetDialectByDriver(driver)
+	dumper.batchSize = DefaultBatchSize
 	dumper.mapper = map[string]Parser{
-		"VARCHAR":   byteToString,
-		"CHAR":      byteToString,
-		"ENUM":      byteToString,
-		"SET":       setToString,
-		"INT":       numToString,
-		"TINYINT":   numToString,
-		"SMALLINT":  numToString,
-		"MEDIUMINT": numToString,
-		"BIGINT":    numToString,
-		"TIMESTAMP": tsToString,
-		"DATETIME":  tsToString,
-		"DATE":      dateToString,
-		"JSON":      jsonToString,
+		"VARCHAR":            byteToString,
+		"CHAR":               byteToString,
+		"ENUM":               byteToString,
+		"SET":                byteToString,
+		"INT":                numToString,
+		"TINYINT":            numToString,
+		"SMALLINT":           numToString,
+		"MEDIUMINT":          numToString,
+		"BIGINT":             numToString,
+		"TIMESTAMP":          tsToString,
+		"DATETIME":           tsToString,
+		"DATE":               dateToString,
+		"JSON":               byteToString,
+		"BINARY":             binToString,
+		"VARBINARY":          binToString,
+		"TINYBLOB":           binToString,
+		"BLOB":               binToString,
+		"MEDIUMBLOB":         binToString,
+		"LONGBLOB":           binToString,
+		"BIT":                binToString,
+		"GEOMETRY":           binToString,
+		"POINT":              binToString,
+		"LINESTRING":         binToString,
+		"POLYGON":            binToString,
+		"MULTIPOINT":         binToString,
+		"MULTILINESTRING":    binToString,
+		"MULTIPOLYGON":       binToString,
+		"GEOMETRYCOLLECTION": binToString,
 	}
 	return dumper
 }
@@ -93,7 +121,7 @@ func (d *Dumper) RegisterParser(dataType string, parser Parser) {
 		panic("parser cannot be nil")
 	}
 	d.mu.Lock()
-	defer d.mu.Lock()
+	defer d.mu.Unlock()
 	d.mapper[dataType] = parser
 }
 
@@ -101,34 +129,17 @@ func (d *Dumper) RegisterParser(dataType string, parser Parser) {
 func (d *Dumper) BackupTo(ctx context.Context, query interface{}, wr io.Writer) (affected int64, err error) {
 	w := bufio.NewWriter(wr)
 
-	var (
-		dbName string
-		table  string
-	)
-	switch v := query.(type) {
-	case *actions.FindActions:
-		dbName = v.Database
-		table = v.Table
-	case *actions.FindOneActions:
-		dbName = v.Database
-		table = v.Table
-	default:
-		return 0, errors.New("unsupported input")
-	}
-
-	columns, err := d.getColumns(ctx, dbName, table)
+	dbName, table, err := getTable(query)
 	if err != nil {
 		return 0, err
 	}
 
-	stmt := sqlstmt.AcquireStmt(d.dialect)
-	defer sqlstmt.ReleaseStmt(stmt)
-
-	if err := d.dialect.SelectStmt(stmt, query); err != nil {
+	columns, err := d.getColumns(ctx, dbName, table)
+	if err != nil {
 		return 0, err
 	}
 
-	rows, err := d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	rows, err := d.queryRows(ctx, query)
 	if err != nil {
 		return 0, err
 	}
@@ -140,6 +151,21 @@ func (d *Dumper) BackupTo(ctx context.Context, query interface{}, wr io.Writer)
 	}
 
 	cols, _ := rows.Columns()
+	d.writeHeader(w, version, dbName)
+
+	table = d.dialect.Quote(table)
+	d.writeLockTable(w, table)
+	defer func() {
+		d.writeUnlockTable(w, table)
+		d.writeFooter(w)
+		w.Flush()
+	}()
+
+	affected, _, err = d.writeRows(w, table, cols, columns, rows, nil)
+	return
+}
+
+func (d *Dumper) writeHeader(w *bufio.Writer, version, dbName string) {
 	w.WriteString(`
 # ************************************************************
 # Sqlike Dumper
@@ -149,7 +175,6 @@ func (d *Dumper) BackupTo(ctx context.Context, query interface{}, wr io.Writer)
 `)
 	w.WriteString("# Driver: " + d.driver + "\n")
 	w.WriteString("# Version: " + version + "\n")
-	// w.WriteString("# Host: rm-zf86x4n0wvyy6830yyo.mysql.kualalumpur.rds.aliyuncs.com\n")
 	w.WriteString("# Database: " + dbName + "\n")
 	w.WriteString("# Generation Time: " + time.Now().UTC().Format(time.RFC3339) + "\n")
 	w.WriteString("# ************************************************************\n")
@@ -165,58 +190,67 @@ SET NAMES utf8mb4;
 /*!40111 SET @OLD_SQL_NOTES=@@SQL_NOTES, SQL_NOTES=0 */;
 
 `)
+}
 
-	table = d.dialect.Quote(table)
+func (d *Dumper) writeFooter(w *bufio.Writer) {
+	w.WriteString(`
+/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
+/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
+/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
+/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
+/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
+/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
+`)
+}
 
+func (d *Dumper) writeLockTable(w *bufio.Writer, table string) {
 	w.WriteString(fmt.Sprintf(`
 LOCK TABLES %s WRITE;
 /*!40000 ALTER TABLE %s DISABLE KEYS */;
 
 `, table, table))
+}
 
-	defer func() {
-		w.WriteString(fmt.Sprintf(`
+func (d *Dumper) writeUnlockTable(w *bufio.Writer, table string) {
+	w.WriteString(fmt.Sprintf(`
 
 /*!40000 ALTER TABLE %s ENABLE KEYS */;
 UNLOCK TABLES;
-
-/*!40111 SET SQL_NOTES=@OLD_SQL_NOTES */;
-/*!40101 SET SQL_MODE=@OLD_SQL_MODE */;
-/*!40014 SET FOREIGN_KEY_CHECKS=@OLD_FOREIGN_KEY_CHECKS */;
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
 `, table))
-		w.Flush()
-	}()
-
-	w.WriteString("INSERT INTO " + table + " ")
-	w.WriteByte('(')
+}
 
-	for i, col := range cols {
-		if i > 0 {
-			w.WriteByte(',')
-		}
-		w.WriteString(d.dialect.Quote(col))
+// writeRows : write the rows as a single `INSERT` statement, it will return the
+// values of the last row in the positions of `keys`
+func (d *Dumper) writeRows(w *bufio.Writer, table string, cols []string, columns []Column, rows *sql.Rows, keys []int) (affected int64, last []interface{}, err error) {
+	first := true
+	length := len(cols)
+	data := make([]interface{}, length)
+	for i := 0; i < length; i++ {
+		data[i] = new(sql.RawBytes)
 	}
-	w.WriteByte(')')
-	w.WriteByte('\n')
-	w.WriteString("VALUES\n")
 
-	first := true
 	for rows.Next() {
-		if !first {
+		if first {
+			// only write the insert statement when there is any record
+			w.WriteString("INSERT INTO " + table + " ")
+			w.WriteByte('(')
+
+			for i, col := range cols {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(d.dialect.Quote(col))
+			}
+			w.WriteByte(')')
+			w.WriteByte('\n')
+			w.WriteString("VALUES\n")
+		} else {
 			w.WriteByte(',')
 			w.WriteByte('\n')
 		}
-		length := len(cols)
-		data := make([]interface{}, length)
-		for i := 0; i < length; i++ {
-			data[i] = new(sql.RawBytes)
-		}
 
 		if err := rows.Scan(data...); err != nil {
-			return 0, err
+			return affected, nil, err
 		}
 
 		w.WriteByte('(')
@@ -238,19 +272,58 @@ UNLOCK TABLES;
 			}
 
 			if _, err := w.WriteString(parse(x)); err != nil {
-				return 0, err
+				return affected, nil, err
 			}
 		}
 		w.WriteByte(')')
 
+		if len(keys) > 0 {
+			// copy the value because `sql.RawBytes` will be overwritten by next scan
+			last = make([]interface{}, len(keys))
+			for i, pos := range keys {
+				if last[i], err = cursorValue(columns[pos], *data[pos].(*sql.RawBytes)); err != nil {
+					return affected, nil, err
+				}
+			}
+		}
+
 		first = false
+		affected++
+	}
+	if err := rows.Err(); err != nil {
+		return affected, nil, err
 	}
 
-	w.WriteByte(';')
-	w.Flush()
+	if !first {
+		w.WriteString(";\n")
+	}
+	return
+}
+
+func getTable(query interface{}) (dbName, table string, err error) {
+	switch v := query.(type) {
+	case *actions.FindActions:
+		dbName = v.Database
+		table = v.Table
+	case *actions.FindOneActions:
+		dbName = v.Database
+		table = v.Table
+	default:
+		err = errors.New("unsupported input")
+	}
 	return
 }
 
+func (d *Dumper) queryRows(ctx context.Context, query interface{}) (*sql.Rows, error) {
+	stmt := sqlstmt.AcquireStmt(d.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	if err := d.dialect.SelectStmt(stmt, query); err != nil {
+		return nil, err
+	}
+	return d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
+}
+
 func (d *Dumper) getVersion(ctx context.Context) (string, error) {
 	stmt := sqlstmt.AcquireStmt(d.dialect)
 	defer sqlstmt.ReleaseStmt(stmt)
@@ -272,10 +345,44 @@ func (d *Dumper) getVersion(ctx context.Context) (string, error) {
 	return version, nil
 }
 
+// serverInfo : the server version decides the columns of `INFORMATION_SCHEMA`, it's queried once
+func (d *Dumper) serverInfo(ctx context.Context) (driver.Info, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.info != nil {
+		return d.info, nil
+	}
+	ver, err := d.getVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := semver.NewVersion(strings.Split(ver, "-")[0])
+	if err != nil {
+		return nil, err
+	}
+	d.info = serverInfo{driver: d.driver, version: version}
+	return d.info, nil
+}
+
+type serverInfo struct {
+	driver  string
+	version *semver.Version
+}
+
+func (i serverInfo) DriverName() string       { return i.driver }
+func (i serverInfo) Version() *semver.Version { return i.version }
+func (i serverInfo) Charset() charset.Code    { return "" }
+func (i serverInfo) Collate() string          { return "" }
+
 func (d *Dumper) getColumns(ctx context.Context, dbName, table string) ([]Column, error) {
+	info, err := d.serverInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	stmt := sqlstmt.AcquireStmt(d.dialect)
 	defer sqlstmt.ReleaseStmt(stmt)
-	d.dialect.GetColumns(stmt, dbName, table)
+	d.dialect.GetColumns(stmt, info, dbName, table)
 
 	rows, err := d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
 	if err != nil {
@@ -283,11 +390,15 @@ func (d *Dumper) getColumns(ctx context.Context, dbName, table string) ([]Column
 	}
 	defer rows.Close()
 
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
 	columns := make([]Column, 0)
 	for i := 0; rows.Next(); i++ {
 		col := Column{}
-
-		if err := rows.Scan(
+		dest := []interface{}{
 			&col.Position,
 			&col.Name,
 			&col.Type,
@@ -298,7 +409,11 @@ func (d *Dumper) getColumns(ctx context.Context, dbName, table string) ([]Column
 			&col.Collation,
 			&col.Comment,
 			&col.Extra,
-		); err != nil {
+			&col.Expression,
+			&col.SRID,
+		}
+		// `SRS_ID` is not selected before 8.0
+		if err := rows.Scan(dest[:len(names)]...); err != nil {
 			return nil, err
 		}
 
@@ -1,8 +1,17 @@
 package sqldump
 
 import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"strings"
 	"testing"
 
+	"github.com/Oskang09/sqlike/sql/dialect/mysql"
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,3 +19,308 @@ func TestNewDumper(t *testing.T) {
 	dumper := NewDumper("driver", nil)
 	require.NotNil(t, dumper)
 }
+
+func TestScanner(t *testing.T) {
+	dump := `
+# ************************************************************
+# Sqlike Dumper
+# ************************************************************
+
+/*!40101 SET NAMES utf8 */;
+SET NAMES utf8mb4;
+/* normal comment; should be skipped */
+LOCK TABLES ` + "`User`" + ` WRITE;
+-- another comment;
+INSERT INTO ` + "`User`" + ` (` + "`ID`,`Name`" + `)
+VALUES
+(1,"John; Doe"),
+(2,'it\'s (me)'),
+(3,NULL);
+UNLOCK TABLES;`
+
+	s := newScanner(strings.NewReader(dump), 2)
+	stmts := make([]string, 0)
+	for {
+		query, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		stmts = append(stmts, query)
+	}
+
+	require.Equal(t, []string{
+		"/*!40101 SET NAMES utf8 */",
+		"SET NAMES utf8mb4",
+		"LOCK TABLES `User` WRITE",
+		"INSERT INTO `User` (`ID`,`Name`)\nVALUES (1,\"John; Doe\"),\n(2,'it\\'s (me)')",
+		"INSERT INTO `User` (`ID`,`Name`)\nVALUES (3,NULL)",
+		"UNLOCK TABLES",
+	}, stmts)
+	require.True(t, skipStatement(stmts[2]))
+	require.True(t, skipStatement(stmts[5]))
+	require.False(t, skipStatement(stmts[3]))
+}
+
+func TestScannerLiteral(t *testing.T) {
+	dumper := NewDumper("mysql", nil)
+	values := []string{
+		dumper.mapper["VARBINARY"]([]byte("\x00\xff;)'")),
+		byteToString([]byte("it's; (me) \\")),
+		byteToString([]byte("世界\x00\n\r\x1a")),
+	}
+	dump := "INSERT INTO `User\\` (`A`,`B`,`C`)\nVALUES\n(" + strings.Join(values, ",") + "),\n(1,2,3);\nUNLOCK TABLES;"
+
+	s := newScanner(strings.NewReader(dump), 1)
+	stmts := make([]string, 0)
+	for {
+		query, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		stmts = append(stmts, query)
+	}
+
+	require.Equal(t, []string{
+		"INSERT INTO `User\\` (`A`,`B`,`C`)\nVALUES (" + strings.Join(values, ",") + ")",
+		"INSERT INTO `User\\` (`A`,`B`,`C`)\nVALUES (1,2,3)",
+		"UNLOCK TABLES",
+	}, stmts)
+}
+
+func TestCSVValue(t *testing.T) {
+	require.Equal(t, "abc", csvValue([]byte("abc")))
+	require.Equal(t, `\\N`, csvValue([]byte(`\N`)))
+	require.Equal(t, `a\0b\\`, csvValue([]byte("a\x00b\\")))
+	require.Equal(t, "世界\xff", csvValue([]byte("世界\xff")))
+}
+
+func TestIsDDL(t *testing.T) {
+	for _, query := range []string{
+		"DROP TABLE IF EXISTS `User`",
+		"create table `User` (`ID` BIGINT)",
+		"/*!40000 ALTER TABLE `User` DISABLE KEYS */",
+		"  TRUNCATE TABLE `User`",
+		"RENAME TABLE `a` TO `b`",
+	} {
+		require.True(t, isDDL(query), query)
+	}
+	for _, query := range []string{
+		"/*!40101 SET NAMES utf8 */",
+		"INSERT INTO `User` (`ID`) VALUES (1)",
+		"SET NAMES utf8mb4",
+		"DROPPED",
+	} {
+		require.False(t, isDDL(query), query)
+	}
+}
+
+func TestWriteCreateTable(t *testing.T) {
+	var (
+		dumper  = NewDumper("mysql", nil)
+		buf     = new(bytes.Buffer)
+		w       = bufio.NewWriter(buf)
+		charset = "utf8mb4"
+		collate = "utf8mb4_unicode_ci"
+		dflt    = "CURRENT_TIMESTAMP(6)"
+		uuid    = "uuid_to_bin(uuid())"
+		empty   = ""
+		size    = int64(10)
+	)
+	dumper.dialect = mysql.New()
+
+	require.NoError(t, dumper.writeCreateTable(w, "User", []Column{
+		{Name: "ID", Type: "BIGINT UNSIGNED", Extra: "auto_increment"},
+		{Name: "Name", Type: "VARCHAR(191)", DefaultValue: &empty, Charset: &charset, Collation: &collate, Comment: "user's name"},
+		{Name: "Age", Type: "INT", Expression: "json_unquote(json_extract(`Meta`,_utf8mb4'$.age'))", Extra: "VIRTUAL GENERATED", IsNullable: true},
+		{Name: "CreatedAt", Type: "DATETIME(6)", DefaultValue: &dflt, Extra: "DEFAULT_GENERATED"},
+		{Name: "UUID", Type: "BINARY(16)", DefaultValue: &uuid, Extra: "DEFAULT_GENERATED"},
+	}, []Index{
+		{Name: "PRIMARY", Type: "BTREE", IsUnique: true, Columns: []IndexColumn{{Name: "ID"}}},
+		{Name: "UX_Name", Type: "BTREE", IsUnique: true, Columns: []IndexColumn{{Name: "Name", SubPart: &size}, {Name: "CreatedAt", Descending: true}}},
+		{Name: "FX", Type: "BTREE", Invisible: true, Columns: []IndexColumn{{Expression: "lower(`Name`)"}, {Name: "ID"}}},
+	}))
+	w.Flush()
+
+	require.Equal(t, "DROP TABLE IF EXISTS `User`;\n"+
+		"CREATE TABLE `User` (\n"+
+		"  `ID` bigint unsigned NOT NULL AUTO_INCREMENT,\n"+
+		"  `Name` varchar(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '' COMMENT 'user''s name',\n"+
+		"  `Age` int GENERATED ALWAYS AS (json_unquote(json_extract(`Meta`,_utf8mb4'$.age'))) VIRTUAL NULL,\n"+
+		"  `CreatedAt` datetime(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),\n"+
+		"  `UUID` binary(16) NOT NULL DEFAULT (uuid_to_bin(uuid())),\n"+
+		"  PRIMARY KEY (`ID`),\n"+
+		"  UNIQUE KEY `UX_Name` (`Name`(10),`CreatedAt` DESC),\n"+
+		"  KEY `FX` ((lower(`Name`)),`ID`) /*!80000 INVISIBLE */\n"+
+		") ENGINE=InnoDB;\n", buf.String())
+
+	// the expression of functional key part is unknown before 8.0.13
+	require.Error(t, dumper.writeCreateTable(w, "User", nil, []Index{
+		{Name: "FX", Type: "BTREE", Columns: []IndexColumn{{}}},
+	}))
+}
+
+func TestWriteJSONLine(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := bufio.NewWriter(buf)
+	err := writeJSONLine(
+		w,
+		[]string{"ID", "Name", "Meta", "Remark"},
+		[]string{"BIGINT", "VARCHAR", "JSON", "TEXT"},
+		[]sql.RawBytes{[]byte("1"), []byte(`"John"`), []byte(`{"a":1}`), nil},
+	)
+	require.NoError(t, err)
+	w.Flush()
+	require.Equal(t, `{"ID":1,"Name":"\"John\"","Meta":{"a":1},"Remark":null}`+"\n", buf.String())
+
+	// binary is encoded in base64, so it won't be corrupted
+	buf.Reset()
+	require.NoError(t, writeJSONLine(w, []string{"Hash"}, []string{"VARBINARY"}, []sql.RawBytes{{0xff, 0x00, 0xfe}}))
+	w.Flush()
+	require.Equal(t, `{"Hash":"/wD+"}`+"\n", buf.String())
+}
+
+func TestParser(t *testing.T) {
+	dumper := NewDumper("mysql", nil)
+
+	// unquote : decode the literal the same as MySQL
+	unquote := func(str string) []byte {
+		if strings.HasPrefix(str, "X'") {
+			b, err := hex.DecodeString(str[2 : len(str)-1])
+			require.NoError(t, err)
+			return b
+		}
+		require.True(t, str[0] == '\'' && str[len(str)-1] == '\'', str)
+		str = str[1 : len(str)-1]
+		b := make([]byte, 0, len(str))
+		for i := 0; i < len(str); i++ {
+			c := str[i]
+			require.NotEqual(t, byte('\''), c, "unescaped quote")
+			if c != '\\' {
+				b = append(b, c)
+				continue
+			}
+			i++
+			switch str[i] {
+			case '0':
+				b = append(b, 0)
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 'Z':
+				b = append(b, 0x1a)
+			default:
+				b = append(b, str[i])
+			}
+		}
+		return b
+	}
+
+	for _, c := range []struct {
+		dataType string
+		data     string
+		literal  string
+	}{
+		{"VARBINARY", "\x00\xff\xfe'\\", "X'00fffe275c'"},
+		{"BLOB", "", "X''"},
+		{"POINT", "\x00\x00\x00\x00\x01\x01", "X'000000000101'"},
+		{"VARCHAR", "héllo, 世界 \u200b", "'héllo, 世界 \u200b'"},
+		{"TEXT", "it's \\ \x00\n\r\x1a\"", `'it\'s \\ \0\n\r\Z"'`},
+		{"JSON", `{"a":"\u00e9 é"}`, `'{"a":"\\u00e9 é"}'`},
+		{"SET", "A,B", "'A,B'"},
+	} {
+		parse, ok := dumper.mapper[c.dataType]
+		if !ok {
+			parse = byteToString
+		}
+		literal := parse([]byte(c.data))
+		require.Equal(t, c.literal, literal)
+		require.Equal(t, []byte(c.data), unquote(literal), c.dataType)
+	}
+}
+
+func TestSelectChunk(t *testing.T) {
+	dumper := NewDumper("mysql", nil)
+	dumper.dialect = mysql.New()
+	stmt := sqlstmt.AcquireStmt(dumper.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+
+	{
+		dumper.selectChunk(stmt, "db", "User", []string{"ID", "Name"}, nil, nil, 100)
+		require.Equal(t, "SELECT `ID`,`Name` FROM `db`.`User`;", stmt.String())
+		require.Empty(t, stmt.Args())
+	}
+
+	stmt.Reset()
+
+	{
+		dumper.selectChunk(stmt, "db", "User", []string{"ID", "Name"}, []Column{{Name: "ID", DataType: "BIGINT", Type: "BIGINT"}}, nil, 100)
+		require.Equal(t, "SELECT `ID`,`Name` FROM `db`.`User` ORDER BY `ID` LIMIT 100;", stmt.String())
+		require.Empty(t, stmt.Args())
+	}
+
+	stmt.Reset()
+
+	{
+		pk := []Column{
+			{Name: "ID", DataType: "BIGINT", Type: "BIGINT"},
+			{Name: "Name", DataType: "VARCHAR", Type: "VARCHAR(20)"},
+		}
+		dumper.selectChunk(stmt, "db", "User", []string{"ID", "Name"}, pk, []interface{}{int64(10), "Oska"}, 100)
+		require.Equal(t, "SELECT `ID`,`Name` FROM `db`.`User` WHERE (`ID`,`Name`) > (?,?) ORDER BY `ID`,`Name` LIMIT 100;", stmt.String())
+		require.ElementsMatch(t, []interface{}{int64(10), "Oska"}, stmt.Args())
+	}
+
+	stmt.Reset()
+
+	// decimal is casted, otherwise it's compared as double
+	{
+		pk := []Column{{Name: "Amount", DataType: "DECIMAL", Type: "DECIMAL(30,2) UNSIGNED"}}
+		dumper.selectChunk(stmt, "db", "User", []string{"Amount"}, pk, []interface{}{"12345678901234567890.10"}, 100)
+		require.Equal(t, "SELECT `Amount` FROM `db`.`User` WHERE (`Amount`) > (CAST(? AS DECIMAL(30,2))) ORDER BY `Amount` LIMIT 100;", stmt.String())
+	}
+}
+
+func TestCursorValue(t *testing.T) {
+	for _, c := range []struct {
+		col    Column
+		raw    string
+		result interface{}
+	}{
+		{Column{DataType: "BIGINT", Type: "BIGINT"}, "1500000000000000001", int64(1500000000000000001)},
+		{Column{DataType: "BIGINT", Type: "BIGINT UNSIGNED"}, "18446744073709551615", uint64(18446744073709551615)},
+		{Column{DataType: "INT", Type: "INT"}, "-7", int64(-7)},
+		{Column{DataType: "DOUBLE", Type: "DOUBLE"}, "1.5", 1.5},
+		{Column{DataType: "VARBINARY", Type: "VARBINARY(16)"}, "\x00\xff", []byte("\x00\xff")},
+		{Column{DataType: "VARCHAR", Type: "VARCHAR(36)"}, "abc", "abc"},
+	} {
+		v, err := cursorValue(c.col, sql.RawBytes(c.raw))
+		require.NoError(t, err)
+		require.Equal(t, c.result, v)
+	}
+
+	_, err := cursorValue(Column{DataType: "BIGINT", Type: "BIGINT"}, sql.RawBytes("abc"))
+	require.Error(t, err)
+}
+
+func TestBackupOptionsFilter(t *testing.T) {
+	tables := []string{"Log_2021", "Log_2022", "User", "UserRole"}
+
+	opt := BackupOptions{}
+	require.ElementsMatch(t, tables, opt.filter(tables))
+
+	opt = BackupOptions{}
+	WithInclude("User*")(&opt)
+	require.ElementsMatch(t, []string{"User", "UserRole"}, opt.filter(tables))
+
+	opt = BackupOptions{}
+	WithExclude("Log_*")(&opt)
+	require.ElementsMatch(t, []string{"User", "UserRole"}, opt.filter(tables))
+
+	opt = BackupOptions{}
+	WithInclude("User*", "Log_2022")(&opt)
+	WithExclude("UserRole")(&opt)
+	require.ElementsMatch(t, []string{"Log_2022", "User"}, opt.filter(tables))
+}
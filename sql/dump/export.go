@@ -0,0 +1,175 @@
+package sqldump
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/Oskang09/sqlike/util"
+)
+
+// Format :
+type Format int
+
+// formats :
+const (
+	// SQL is the `INSERT` statements which same as `BackupTo`
+	SQL Format = iota
+	// CSV is the comma separated values with the header of column names, NULL will be written as `\N`,
+	// backslash and NUL are escaped, so it can be loaded by `LOAD DATA ... FIELDS TERMINATED BY ','
+	// OPTIONALLY ENCLOSED BY '"'`
+	CSV
+	// JSONLines is a json object per line which the key is the column name, binary is encoded in base64
+	JSONLines
+	// Schema is the `CREATE TABLE` statement only, without any data
+	Schema
+)
+
+// ExportTo : export the result of the query in the selected format
+func (d *Dumper) ExportTo(ctx context.Context, query interface{}, wr io.Writer, format Format) (affected int64, err error) {
+	switch format {
+	case SQL:
+		return d.BackupTo(ctx, query, wr)
+	case Schema:
+		dbName, table, err := getTable(query)
+		if err != nil {
+			return 0, err
+		}
+		return 0, d.BackupSchemaTo(ctx, dbName, table, wr)
+	case CSV, JSONLines:
+	default:
+		return 0, errors.New("sqldump: unsupported format")
+	}
+
+	dbName, table, err := getTable(query)
+	if err != nil {
+		return 0, err
+	}
+
+	columns, err := d.getColumns(ctx, dbName, table)
+	if err != nil {
+		return 0, err
+	}
+	types := make(map[string]string, len(columns))
+	for _, col := range columns {
+		types[col.Name] = col.DataType
+	}
+
+	rows, err := d.queryRows(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	var write func([]sql.RawBytes) error
+	if format == CSV {
+		w := csv.NewWriter(wr)
+		defer w.Flush()
+		if err := w.Write(cols); err != nil {
+			return 0, err
+		}
+		record := make([]string, len(cols))
+		write = func(data []sql.RawBytes) error {
+			for i, x := range data {
+				if x == nil {
+					record[i] = `\N`
+					continue
+				}
+				record[i] = csvValue(x)
+			}
+			return w.Write(record)
+		}
+	} else {
+		w := bufio.NewWriter(wr)
+		defer w.Flush()
+		dataTypes := make([]string, len(cols))
+		for i, col := range cols {
+			dataTypes[i] = types[col]
+		}
+		write = func(data []sql.RawBytes) error {
+			return writeJSONLine(w, cols, dataTypes, data)
+		}
+	}
+
+	data := make([]sql.RawBytes, len(cols))
+	dest := make([]interface{}, len(cols))
+	for i := range data {
+		dest[i] = &data[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return affected, err
+		}
+		if err := write(data); err != nil {
+			return affected, err
+		}
+		affected++
+	}
+	return affected, rows.Err()
+}
+
+// csvValue : escape the value the same as `SELECT ... INTO OUTFILE`, so the value `\N` won't be loaded as NULL
+func csvValue(data []byte) string {
+	blr := util.AcquireString()
+	defer util.ReleaseString(blr)
+	for _, c := range data {
+		switch c {
+		case 0:
+			blr.WriteString(`\0`)
+		case '\\':
+			blr.WriteString(`\\`)
+		default:
+			blr.WriteByte(c)
+		}
+	}
+	return blr.String()
+}
+
+func writeJSONLine(w *bufio.Writer, cols, dataTypes []string, data []sql.RawBytes) error {
+	w.WriteByte('{')
+	for i, col := range cols {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		b, _ := json.Marshal(col)
+		w.Write(b)
+		w.WriteByte(':')
+
+		x := data[i]
+		if x == nil {
+			w.WriteString("null")
+			continue
+		}
+
+		switch dataTypes[i] {
+		case "TINYINT", "SMALLINT", "MEDIUMINT", "INT", "BIGINT",
+			"DECIMAL", "FLOAT", "DOUBLE", "REAL", "JSON":
+			w.Write(x)
+		case "BINARY", "VARBINARY", "TINYBLOB", "BLOB", "MEDIUMBLOB", "LONGBLOB", "BIT",
+			"GEOMETRY", "POINT", "LINESTRING", "POLYGON", "MULTIPOINT", "MULTILINESTRING",
+			"MULTIPOLYGON", "GEOMETRYCOLLECTION":
+			// binary may not be valid utf8, so it's encoded in base64
+			w.WriteByte('"')
+			w.WriteString(base64.StdEncoding.EncodeToString(x))
+			w.WriteByte('"')
+		default:
+			b, err := json.Marshal(util.UnsafeString(x))
+			if err != nil {
+				return err
+			}
+			w.Write(b)
+		}
+	}
+	w.WriteByte('}')
+	return w.WriteByte('\n')
+}
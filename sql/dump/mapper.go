@@ -1,24 +1,47 @@
 package sqldump
 
 import (
-	"bytes"
-	"strconv"
-	"strings"
+	"encoding/hex"
 	"time"
 
 	"github.com/Oskang09/sqlike/util"
 )
 
-var null = []byte(`null`)
-
 // Parser :
 type Parser func([]byte) string
 
+// byteToString : the string literal is escaped the same as `mysql_real_escape_string`,
+// so the value will be restored byte by byte
 func byteToString(data []byte) string {
-	if len(data) == 0 {
-		return `""`
+	blr := util.AcquireString()
+	defer util.ReleaseString(blr)
+	blr.WriteByte('\'')
+	for _, c := range data {
+		switch c {
+		case 0:
+			blr.WriteString(`\0`)
+		case '\'':
+			blr.WriteString(`\'`)
+		case '\\':
+			blr.WriteString(`\\`)
+		case '\n':
+			blr.WriteString(`\n`)
+		case '\r':
+			blr.WriteString(`\r`)
+		case 0x1a:
+			blr.WriteString(`\Z`)
+		default:
+			blr.WriteByte(c)
+		}
 	}
-	return strconv.Quote(string(data))
+	blr.WriteByte('\'')
+	return blr.String()
+}
+
+// binToString : binary may not be valid in the character set of the connection,
+// so it's written as hex literal
+func binToString(data []byte) string {
+	return "X'" + hex.EncodeToString(data) + "'"
 }
 
 func numToString(data []byte) string {
@@ -27,33 +50,10 @@ func numToString(data []byte) string {
 
 func tsToString(data []byte) string {
 	t, _ := time.Parse(time.RFC3339, string(data))
-	return t.UTC().Format(`"2006-01-02 15:04:05.999999999"`)
+	return t.UTC().Format(`'2006-01-02 15:04:05.999999999'`)
 }
 
 func dateToString(data []byte) string {
 	t, _ := time.Parse(time.RFC3339, string(data))
-	return t.UTC().Format(`"2006-01-02"`)
-}
-
-func jsonToString(data []byte) string {
-	if bytes.Equal(data, null) {
-		return `"null"`
-	}
-	return strconv.Quote(string(data))
-}
-
-func setToString(data []byte) string {
-	str := string(data)
-	vals := strings.Split(str, ",")
-	blr := util.AcquireString()
-	defer util.ReleaseString(blr)
-	blr.WriteString(`'`)
-	for i, v := range vals {
-		if i > 0 {
-			blr.WriteByte(',')
-		}
-		blr.WriteString(v)
-	}
-	blr.WriteString(`'`)
-	return blr.String()
+	return t.UTC().Format(`'2006-01-02'`)
 }
@@ -0,0 +1,293 @@
+package sqldump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"strings"
+)
+
+// DefaultBatchSize : is the maximum number of rows for every `INSERT` statement on restore
+const DefaultBatchSize = 500
+
+// SetBatchSize : set the maximum number of rows for every `INSERT` statement on restore
+func (d *Dumper) SetBatchSize(size int) *Dumper {
+	if size < 1 {
+		panic("batch size should be greater than zero")
+	}
+	d.batchSize = size
+	return d
+}
+
+// RestoreFrom : read the statements from the dump file and execute it in transaction,
+// the `INSERT` statement will be splitted into multiple statements by the batch size, so it
+// won't exceed the `max_allowed_packet`. `LOCK TABLES` and `UNLOCK TABLES` will be skipped
+// because it will implicitly commit the transaction. The gzip compressed dump file will be
+// decompressed automatically.
+//
+// DDL such as `DROP TABLE` and `CREATE TABLE` implicitly commits in MySQL, so it's executed
+// between the transactions instead: the statements after every DDL are executed in a new
+// transaction. The dump of `BackupDatabase` is restored table by table, and if it fails
+// halfway, the tables before the failed one are already restored.
+func (d *Dumper) RestoreFrom(ctx context.Context, r io.Reader) (affected int64, err error) {
+	db, ok := d.conn.(connector)
+	if !ok {
+		return 0, errors.New("sqldump: connection is not able to begin transaction")
+	}
+
+	// every statement must be executed in the same session, such as `SET FOREIGN_KEY_CHECKS`
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		tx.Rollback()
+	}()
+
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		r = gz
+	} else {
+		r = br
+	}
+
+	s := newScanner(r, d.batchSize)
+	for {
+		query, err := s.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		if skipStatement(query) {
+			continue
+		}
+
+		if isDDL(query) {
+			if err := tx.Commit(); err != nil {
+				return 0, err
+			}
+			if _, err := conn.ExecContext(ctx, query); err != nil {
+				return 0, err
+			}
+			if tx, err = conn.BeginTx(ctx, nil); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		result, err := tx.ExecContext(ctx, query)
+		if err != nil {
+			return 0, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			affected += n
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// isDDL : the statement which implicitly commits the transaction, including the
+// version comment such as `/*!40000 ALTER TABLE ... */`
+func isDDL(query string) bool {
+	query = strings.TrimSpace(query)
+	if strings.HasPrefix(query, "/*!") {
+		query = strings.TrimLeft(query[3:], "0123456789")
+	}
+	upper := strings.ToUpper(strings.TrimSpace(query))
+	for _, prefix := range []string{"CREATE ", "DROP ", "ALTER ", "RENAME ", "TRUNCATE "} {
+		if strings.HasPrefix(upper, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func skipStatement(query string) bool {
+	upper := strings.ToUpper(query)
+	return strings.HasPrefix(upper, "LOCK TABLES") || strings.HasPrefix(upper, "UNLOCK TABLES")
+}
+
+// scanner : split the dump file into statements without reading the whole file into memory
+type scanner struct {
+	r         *bufio.Reader
+	batchSize int
+	buf       bytes.Buffer
+
+	// prefix is the `INSERT INTO ... VALUES` of the current insert statement
+	prefix string
+	rows   int
+}
+
+func newScanner(r io.Reader, batchSize int) *scanner {
+	if batchSize < 1 {
+		batchSize = DefaultBatchSize
+	}
+	return &scanner{r: bufio.NewReader(r), batchSize: batchSize}
+}
+
+// Next : return the next statement, io.EOF will be returned when there is no more statement
+func (s *scanner) Next() (string, error) {
+	var (
+		quote byte
+		depth int
+	)
+
+	for {
+		c, err := s.r.ReadByte()
+		if err == io.EOF {
+			if query := s.flush(); query != "" {
+				return query, nil
+			}
+			return "", io.EOF
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if quote != 0 {
+			s.buf.WriteByte(c)
+			switch {
+			case c == '\\' && quote != '`':
+				// backslash is not an escape character of the quoted identifier
+				if next, err := s.r.ReadByte(); err == nil {
+					s.buf.WriteByte(next)
+				}
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+			s.buf.WriteByte(c)
+
+		case '#':
+			if err := s.skipLine(); err != nil {
+				return "", err
+			}
+
+		case '-':
+			if b, _ := s.r.Peek(2); len(b) == 2 && b[0] == '-' && (b[1] == ' ' || b[1] == '\t' || b[1] == '\n') {
+				if err := s.skipLine(); err != nil {
+					return "", err
+				}
+				continue
+			}
+			s.buf.WriteByte(c)
+
+		case '/':
+			if b, _ := s.r.Peek(2); len(b) == 2 && b[0] == '*' {
+				// executable comment such as `/*!40101 ... */` should be kept
+				if err := s.readComment(b[1] != '!'); err != nil {
+					return "", err
+				}
+				continue
+			}
+			s.buf.WriteByte(c)
+
+		case '(':
+			if depth == 0 && s.prefix == "" && s.isInsertValues() {
+				s.prefix = strings.TrimSpace(s.buf.String()) + " "
+				s.buf.Reset()
+			}
+			depth++
+			s.buf.WriteByte(c)
+
+		case ')':
+			depth--
+			s.buf.WriteByte(c)
+			if depth == 0 && s.prefix != "" {
+				s.rows++
+				if s.rows >= s.batchSize {
+					return s.flush(), nil
+				}
+			}
+
+		case ',':
+			// the remaining rows of the splitted insert statement shouldn't start with comma
+			if depth == 0 && s.prefix != "" && strings.TrimSpace(s.buf.String()) == "" {
+				continue
+			}
+			s.buf.WriteByte(c)
+
+		case ';':
+			if query := s.flush(); query != "" {
+				s.prefix = ""
+				return query, nil
+			}
+			s.prefix = ""
+
+		default:
+			s.buf.WriteByte(c)
+		}
+	}
+}
+
+func (s *scanner) isInsertValues() bool {
+	str := strings.ToUpper(strings.TrimSpace(s.buf.String()))
+	return strings.HasPrefix(str, "INSERT") && strings.HasSuffix(str, "VALUES")
+}
+
+func (s *scanner) flush() string {
+	str := strings.TrimSpace(s.buf.String())
+	s.buf.Reset()
+	s.rows = 0
+	if str == "" {
+		return ""
+	}
+	return s.prefix + str
+}
+
+func (s *scanner) skipLine() error {
+	_, err := s.r.ReadString('\n')
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+func (s *scanner) readComment(skip bool) error {
+	// consume the `*` of the opening
+	if _, err := s.r.ReadByte(); err != nil {
+		return err
+	}
+	if !skip {
+		s.buf.WriteString("/*")
+	}
+	var prev byte
+	for {
+		c, err := s.r.ReadByte()
+		if err != nil {
+			return err
+		}
+		if !skip {
+			s.buf.WriteByte(c)
+		}
+		if prev == '*' && c == '/' {
+			return nil
+		}
+		prev = c
+	}
+}
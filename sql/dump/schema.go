@@ -0,0 +1,239 @@
+package sqldump
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+)
+
+// Index :
+type Index struct {
+	// index name, the primary key is always named as `PRIMARY`
+	Name string
+
+	// index type, eg. BTREE, FULLTEXT, SPATIAL
+	Type string
+
+	// whether the index is unique or not
+	IsUnique bool
+
+	// index comment
+	Comment string
+
+	// whether the index is invisible to the optimizer (^8.0)
+	Invisible bool
+
+	// index columns in sequence order
+	Columns []IndexColumn
+}
+
+// IndexColumn :
+type IndexColumn struct {
+	// column name, it will be empty if it's a functional key part
+	Name string
+
+	// expression of the functional key part (^8.0.13)
+	Expression string
+
+	// the number of indexed characters if the column is only partly indexed
+	SubPart *int64
+
+	// whether the column is sorted in descending order
+	Descending bool
+}
+
+// BackupSchemaTo : write the `CREATE TABLE` statement of the table, which
+// derived from the columns and indexes definition of the table
+func (d *Dumper) BackupSchemaTo(ctx context.Context, dbName, table string, wr io.Writer) error {
+	w := bufio.NewWriter(wr)
+
+	columns, err := d.getColumns(ctx, dbName, table)
+	if err != nil {
+		return err
+	}
+
+	idxs, err := d.getIndexes(ctx, dbName, table)
+	if err != nil {
+		return err
+	}
+
+	if err := d.writeCreateTable(w, table, columns, idxs); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (d *Dumper) writeCreateTable(w *bufio.Writer, table string, columns []Column, idxs []Index) error {
+	for _, idx := range idxs {
+		for _, col := range idx.Columns {
+			if col.Name == "" && col.Expression == "" {
+				return fmt.Errorf("sqldump: expression of functional key part of index %q is unknown", idx.Name)
+			}
+		}
+	}
+
+	w.WriteString("DROP TABLE IF EXISTS " + d.dialect.Quote(table) + ";\n")
+	w.WriteString("CREATE TABLE " + d.dialect.Quote(table) + " (\n")
+	for i, col := range columns {
+		if i > 0 {
+			w.WriteString(",\n")
+		}
+		w.WriteString("  ")
+		d.writeColumn(w, col)
+	}
+
+	for _, idx := range idxs {
+		w.WriteString(",\n  ")
+		d.writeIndex(w, idx)
+	}
+	w.WriteString("\n) ENGINE=InnoDB;\n")
+	return nil
+}
+
+func (d *Dumper) writeColumn(w *bufio.Writer, col Column) {
+	w.WriteString(d.dialect.ColumnDefinition(columns.Definition{
+		Name:         col.Name,
+		Type:         col.Type,
+		Nullable:     bool(col.IsNullable),
+		DefaultValue: col.DefaultValue,
+		Charset:      col.Charset,
+		Collation:    col.Collation,
+		Comment:      col.Comment,
+		Extra:        col.Extra,
+		Expression:   col.Expression,
+		SRID:         col.SRID,
+	}))
+}
+
+func (d *Dumper) writeIndex(w *bufio.Writer, idx Index) {
+	switch {
+	case idx.Name == "PRIMARY":
+		w.WriteString("PRIMARY KEY ")
+	case idx.Type == "FULLTEXT":
+		w.WriteString("FULLTEXT KEY " + d.dialect.Quote(idx.Name) + " ")
+	case idx.Type == "SPATIAL":
+		w.WriteString("SPATIAL KEY " + d.dialect.Quote(idx.Name) + " ")
+	case idx.IsUnique:
+		w.WriteString("UNIQUE KEY " + d.dialect.Quote(idx.Name) + " ")
+	default:
+		w.WriteString("KEY " + d.dialect.Quote(idx.Name) + " ")
+	}
+	w.WriteByte('(')
+	for i, col := range idx.Columns {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		if col.Name == "" {
+			w.WriteString("(" + col.Expression + ")")
+		} else {
+			w.WriteString(d.dialect.Quote(col.Name))
+		}
+		if col.SubPart != nil {
+			w.WriteString("(" + strconv.FormatInt(*col.SubPart, 10) + ")")
+		}
+		if col.Descending {
+			w.WriteString(" DESC")
+		}
+	}
+	w.WriteByte(')')
+	if idx.Comment != "" {
+		w.WriteString(" COMMENT " + d.dialect.QuoteString(idx.Comment))
+	}
+	if idx.Invisible {
+		w.WriteString(" /*!80000 INVISIBLE */")
+	}
+}
+
+func (idx Index) hasColumns() bool {
+	if len(idx.Columns) == 0 {
+		return false
+	}
+	for _, col := range idx.Columns {
+		if col.Name == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func (d *Dumper) getIndexes(ctx context.Context, dbName, table string) ([]Index, error) {
+	stmt := sqlstmt.AcquireStmt(d.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	info, err := d.serverInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d.dialect.GetIndexColumns(stmt, info, dbName, table)
+
+	rows, err := d.conn.QueryContext(ctx, stmt.String(), stmt.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	idxs := make([]Index, 0)
+	for rows.Next() {
+		var (
+			name      string
+			seq       int
+			column    sql.NullString
+			subPart   sql.NullInt64
+			collation sql.NullString
+			idxType   string
+			nonUnique bool
+			comment   string
+			visible   sql.NullString
+			expr      sql.NullString
+		)
+		dest := []interface{}{
+			&name,
+			&seq,
+			&column,
+			&subPart,
+			&collation,
+			&idxType,
+			&nonUnique,
+			&comment,
+			&visible,
+			&expr,
+		}
+		// `IS_VISIBLE` is not selected before 8.0 and `EXPRESSION` is not selected before 8.0.13
+		if err := rows.Scan(dest[:len(names)]...); err != nil {
+			return nil, err
+		}
+
+		if len(idxs) == 0 || idxs[len(idxs)-1].Name != name {
+			idxs = append(idxs, Index{
+				Name:      name,
+				Type:      strings.ToUpper(idxType),
+				IsUnique:  !nonUnique,
+				Comment:   comment,
+				Invisible: strings.EqualFold(visible.String, "NO"),
+			})
+		}
+
+		col := IndexColumn{
+			Name:       column.String,
+			Expression: expr.String,
+			Descending: collation.String == "D",
+		}
+		if subPart.Valid {
+			col.SubPart = &subPart.Int64
+		}
+		last := &idxs[len(idxs)-1]
+		last.Columns = append(last.Columns, col)
+	}
+	return idxs, rows.Err()
+}
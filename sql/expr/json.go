@@ -3,6 +3,7 @@ package expr
 import (
 	"encoding/json"
 
+	"github.com/Oskang09/sqlike/jsonb"
 	"github.com/Oskang09/sqlike/sqlike/primitive"
 )
 
@@ -98,6 +99,94 @@ func JSON_REPLACE(doc interface{}, path string, value interface{}, pathValues ..
 	return
 }
 
+// JSON_MERGE_PATCH : merge the json documents following RFC 7386, mysql 8.0.3
+func JSON_MERGE_PATCH(doc interface{}, patch interface{}, otherPatches ...interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_MERGE_PATCH
+	f.Args = append(f.Args, doc)
+	for _, p := range append([]interface{}{patch}, otherPatches...) {
+		f.Args = append(f.Args, wrapJSONColumn(p))
+	}
+	return
+}
+
+// JSONMergePatch : apply the RFC 7386 merge patch on the json column, the patch can be
+// a raw json or any value which can be encoded as json object, eg. struct or map.
+// The value of the column must not be NULL
+func JSONMergePatch(field string, patch interface{}) (kv primitive.KV) {
+	kv.Field = field
+	kv.Value = JSON_MERGE_PATCH(Column(field), patch)
+	return
+}
+
+// JSONDiff : diff the old and new value of the json column, and generate the minimal
+// `JSON_REMOVE`, `JSON_REPLACE` and `JSON_SET` update on it. It returns nothing if the values
+// are the same, or the whole value if the column is not an object or array. The values are
+// encoded by the default codec of `jsonb`, use `JSONDiffCodec` if the client has its own json codec
+func JSONDiff(field string, old, new interface{}) ([]primitive.KV, error) {
+	return JSONDiffCodec(jsonb.DefaultCodec(), field, old, new)
+}
+
+// JSONDiffCodec : same as `JSONDiff`, but the values are encoded by the codec, it should be the codec of
+// `Client.SetJSONCodec`, otherwise the paths and values may not match the stored document
+func JSONDiffCodec(codec *jsonb.Codec, field string, old, new interface{}) ([]primitive.KV, error) {
+	a, err := codec.Marshal(old)
+	if err != nil {
+		return nil, err
+	}
+	b, err := codec.Marshal(new)
+	if err != nil {
+		return nil, err
+	}
+	patches, err := jsonb.Diff(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return JSONPatch(field, patches), nil
+}
+
+// JSONPatch : generate the update of the json column from the patches of `jsonb.Diff`
+func JSONPatch(field string, patches []jsonb.Patch) []primitive.KV {
+	if len(patches) == 0 {
+		return nil
+	}
+
+	var (
+		doc      interface{} = Column(field)
+		removes  []string
+		replaces []interface{}
+		sets     []interface{}
+	)
+	for _, p := range patches {
+		if p.Path == "$" {
+			return []primitive.KV{ColumnValue(field, string(p.Value))}
+		}
+		switch p.Op {
+		case jsonb.PatchRemove:
+			removes = append(removes, p.Path)
+		case jsonb.PatchReplace:
+			replaces = append(replaces, wrapRaw(p.Path), castJSON(p.Value))
+		case jsonb.PatchSet:
+			sets = append(sets, wrapRaw(p.Path), castJSON(p.Value))
+		}
+	}
+	if len(removes) > 0 {
+		doc = JSON_REMOVE(doc, removes[0], removes[1:]...)
+	}
+	if len(replaces) > 0 {
+		doc = primitive.JSONFunc{Type: primitive.JSON_REPLACE, Args: append([]interface{}{doc}, replaces...)}
+	}
+	if len(sets) > 0 {
+		doc = primitive.JSONFunc{Type: primitive.JSON_SET, Args: append([]interface{}{doc}, sets...)}
+	}
+	return []primitive.KV{ColumnValue(field, doc)}
+}
+
+// castJSON : the value must be casted as json, otherwise it will be treated as json string. It's bound
+// as string, because the binary string can't be casted as json
+func castJSON(v json.RawMessage) primitive.CastAs {
+	return CastAs(primitive.Value{Raw: string(v)}, primitive.JSON)
+}
+
 // JSON_VALID :
 func JSON_VALID(val interface{}) (f primitive.JSONFunc) {
 	f.Type = primitive.JSON_VALID
@@ -143,6 +232,166 @@ func MemberOf(val interface{}, arr interface{}) (f primitive.JSONFunc) {
 	return
 }
 
+// JSON_MERGE_PRESERVE : merge the json documents and preserve the duplicate keys, mysql 8.0.3
+func JSON_MERGE_PRESERVE(doc interface{}, other interface{}, others ...interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_MERGE_PRESERVE
+	f.Args = append(f.Args, doc)
+	for _, d := range append([]interface{}{other}, others...) {
+		f.Args = append(f.Args, wrapJSONColumn(d))
+	}
+	return
+}
+
+// JSON_ARRAY_APPEND :
+func JSON_ARRAY_APPEND(doc interface{}, path string, value interface{}, pathValues ...interface{}) (f primitive.JSONFunc) {
+	length := len(pathValues)
+	if length > 0 && length%2 != 0 {
+		panic("invalid argument len for JSON_ARRAY_APPEND(json_doc, path, val[, path, val] ...)")
+	}
+	f.Type = primitive.JSON_ARRAY_APPEND
+	f.Args = append(f.Args, doc, wrapRaw(path), value)
+	f.Args = append(f.Args, pathValues...)
+	return
+}
+
+// JSON_ARRAY_INSERT :
+func JSON_ARRAY_INSERT(doc interface{}, path string, value interface{}, pathValues ...interface{}) (f primitive.JSONFunc) {
+	length := len(pathValues)
+	if length > 0 && length%2 != 0 {
+		panic("invalid argument len for JSON_ARRAY_INSERT(json_doc, path, val[, path, val] ...)")
+	}
+	f.Type = primitive.JSON_ARRAY_INSERT
+	f.Args = append(f.Args, doc, wrapRaw(path), value)
+	f.Args = append(f.Args, pathValues...)
+	return
+}
+
+// JSON_LENGTH :
+func JSON_LENGTH(doc interface{}, path ...string) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_LENGTH
+	f.Args = append(f.Args, doc)
+	if len(path) > 0 {
+		f.Args = append(f.Args, wrapRaw(path[0]))
+	}
+	return
+}
+
+// JSON_DEPTH :
+func JSON_DEPTH(doc interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_DEPTH
+	f.Args = append(f.Args, doc)
+	return
+}
+
+// JSON_SEARCH : search the string within the paths, oneOrAll is either `one` or `all`
+func JSON_SEARCH(doc interface{}, oneOrAll string, search string, paths ...string) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_SEARCH
+	f.Args = append(f.Args, doc, wrapRaw(oneOrAll), wrapRaw(search))
+	if len(paths) > 0 {
+		// the escape char is required before paths, NULL is the default escape char `\`
+		f.Args = append(f.Args, wrapRaw(nil))
+		for _, p := range paths {
+			f.Args = append(f.Args, wrapRaw(p))
+		}
+	}
+	return
+}
+
+// JSON_CONTAINS_PATH : oneOrAll is either `one` or `all`
+func JSON_CONTAINS_PATH(doc interface{}, oneOrAll string, path string, paths ...string) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_CONTAINS_PATH
+	f.Args = append(f.Args, doc, wrapRaw(oneOrAll))
+	for _, p := range append([]string{path}, paths...) {
+		f.Args = append(f.Args, wrapRaw(p))
+	}
+	return
+}
+
+// JSON_OVERLAPS : mysql 8.0.17
+func JSON_OVERLAPS(doc1, doc2 interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_OVERLAPS
+	f.Args = append(f.Args, wrapJSONColumn(doc1), wrapJSONColumn(doc2))
+	return
+}
+
+// JSON_OBJECT : create json object from key value pairs, eg. JSON_OBJECT("id", 1, "name", expr.Column("Name"))
+func JSON_OBJECT(keyValues ...interface{}) (f primitive.JSONFunc) {
+	if len(keyValues)%2 != 0 {
+		panic("invalid argument len for JSON_OBJECT([key, val[, key, val] ...])")
+	}
+	f.Type = primitive.JSON_OBJECT
+	for _, kv := range keyValues {
+		f.Args = append(f.Args, wrapRaw(kv))
+	}
+	return
+}
+
+// JSON_ARRAY : create json array from values
+func JSON_ARRAY(values ...interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_ARRAY
+	for _, v := range values {
+		f.Args = append(f.Args, wrapRaw(v))
+	}
+	return
+}
+
+// JSON_ARRAYAGG : aggregate the values of the column as json array
+func JSON_ARRAYAGG(field interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_ARRAYAGG
+	f.Args = append(f.Args, wrapColumn(field))
+	return
+}
+
+// JSON_OBJECTAGG : aggregate the key value pairs of the columns as json object
+func JSON_OBJECTAGG(key, value interface{}) (f primitive.JSONFunc) {
+	f.Type = primitive.JSON_OBJECTAGG
+	f.Args = append(f.Args, wrapColumn(key), wrapColumn(value))
+	return
+}
+
+// JSON_TABLE : extract the json document as table, use it as table source of select statement,
+// eg. sql.Select().From(expr.JSON_TABLE(...)), mysql 8.0.4
+func JSON_TABLE(doc interface{}, path string, alias string, columns ...primitive.JSONTableColumn) (f primitive.JSONFunc) {
+	if len(columns) == 0 {
+		panic("missing columns for JSON_TABLE(expr, path COLUMNS (column_list)) AS alias")
+	}
+	f.Type = primitive.JSON_TABLE
+	f.Args = append(f.Args, wrapRaw(doc), path, primitive.JSONTable{
+		Columns: columns,
+		Alias:   alias,
+	})
+	return
+}
+
+// JSONTableColumn : the column of `JSON_TABLE`, eg. `name` VARCHAR(100) PATH '$.name'
+func JSONTableColumn(name, dataType, path string) (c primitive.JSONTableColumn) {
+	c.Name = name
+	c.DataType = dataType
+	c.Path = path
+	return
+}
+
+// JSONTableExists : the column of `JSON_TABLE` which is 1 if the path exists, eg. `has_name` INT EXISTS PATH '$.name'
+func JSONTableExists(name, dataType, path string) (c primitive.JSONTableColumn) {
+	c = JSONTableColumn(name, dataType, path)
+	c.Exists = true
+	return
+}
+
+// JSONTableOrdinality : the row counter column of `JSON_TABLE`, eg. `id` FOR ORDINALITY
+func JSONTableOrdinality(name string) (c primitive.JSONTableColumn) {
+	c.Name = name
+	c.Ordinality = true
+	return
+}
+
+// JSONTableNested : the nested path columns of `JSON_TABLE`, eg. NESTED PATH '$.tags[*]' COLUMNS (...)
+func JSONTableNested(path string, columns ...primitive.JSONTableColumn) (c primitive.JSONTableColumn) {
+	c.Path = path
+	c.Nested = columns
+	return
+}
+
 // JSONColumn :
 func JSONColumn(column string, nested ...string) (c primitive.JSONColumn) {
 	c.Column = column
@@ -158,7 +407,7 @@ func wrapJSONColumn(it interface{}) interface{} {
 			Value:    vi,
 			DataType: primitive.JSON,
 		}
-	case primitive.JSONFunc:
+	case primitive.JSONFunc, primitive.JSONColumn, primitive.CastAs, primitive.Raw:
 		return vi
 	default:
 		return primitive.Value{Raw: vi}
@@ -52,4 +52,16 @@ func TestJSON(t *testing.T) {
 			},
 		}, it)
 	})
+
+	t.Run("JSON_MERGE_PATCH", func(tst *testing.T) {
+		it = JSON_MERGE_PATCH(Column("a"), `{"b":1}`, Column("c"))
+		require.Equal(tst, primitive.JSONFunc{
+			Type: primitive.JSON_MERGE_PATCH,
+			Args: []interface{}{
+				primitive.Column{Name: "a"},
+				primitive.Value{Raw: `{"b":1}`},
+				primitive.CastAs{Value: primitive.Column{Name: "c"}, DataType: primitive.JSON},
+			},
+		}, it)
+	})
 }
@@ -0,0 +1,58 @@
+package expr
+
+import (
+	"github.com/Oskang09/sqlike/sql/util"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/Oskang09/sqlike/sqlike/primitive"
+	"github.com/Oskang09/sqlike/types"
+)
+
+// Ancestor : filter the key itself and its descendants, eg. `Account,1` matches `Account,1/User,'a'`.
+// The wildcard characters of the key will be escaped, so the prefix can be served by the index of the column.
+func Ancestor(field interface{}, key *types.Key) (g primitive.Group) {
+	if key.Incomplete() {
+		panic("sqlike: ancestor key is incomplete")
+	}
+	k := key.String()
+	g = Or(Equal(field, k), Like(field, k+"/%"))
+	return
+}
+
+// KeyKind : filter the keys by the kind of the last path, eg. `Account,1/User,'a'` is kind of `User`
+func KeyKind(field interface{}, kind string) (c primitive.C) {
+	c = clause(keyKind(wrapColumn(field)), primitive.Equal, kind)
+	return
+}
+
+// KeyKindIndex : the functional key part of `indexes.Index` for `KeyKind` (^8.0.13), the field
+// should be the column name or `expr.Column`, the table of the column will be ignored
+func KeyKindIndex(field interface{}) indexes.Col {
+	col, ok := wrapColumn(field).(primitive.Column)
+	if !ok {
+		panic("sqlike: invalid field of key kind index")
+	}
+	return indexes.Expr(keyKindFormat(util.MySQLUtil{}.Quote(col.Name)))
+}
+
+// KeyNamespace : filter the keys by the namespace, eg. `tenant:Account,1/User,'a'` is in namespace of `tenant`.
+// The namespace is the prefix of the key, so it can be served by the index of the column,
+// the keys of the default namespace are matched when the namespace is empty.
+func KeyNamespace(field interface{}, ns string) (g primitive.Group) {
+	if ns == "" {
+		g = And(clause(Func("LOCATE", Raw("':'"), Func("SUBSTRING_INDEX", wrapColumn(field), Raw("','"), Raw("1"))), primitive.Equal, 0))
+		return
+	}
+	g = And(Like(field, types.NamespacePrefix(ns)+"%"))
+	return
+}
+
+// keyKind : the kind is the part before `,` of the last path and after the namespace prefix `:` of the root key, the
+// name id doesn't contain `/` and `,` since it's escaped, the literal arguments are required so the expression is the
+// same as the functional key part
+func keyKind(field interface{}) primitive.Func {
+	return Func("SUBSTRING_INDEX", Func("SUBSTRING_INDEX", Func("SUBSTRING_INDEX", field, Raw("'/'"), Raw("-1")), Raw("','"), Raw("1")), Raw("':'"), Raw("-1"))
+}
+
+func keyKindFormat(field string) string {
+	return "SUBSTRING_INDEX(SUBSTRING_INDEX(SUBSTRING_INDEX(" + field + ",'/',-1),',',1),':',-1)"
+}
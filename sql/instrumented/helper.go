@@ -1,6 +1,15 @@
 package instrumented
 
-import "database/sql/driver"
+import (
+	"database/sql/driver"
+	"regexp"
+	"strings"
+)
+
+var (
+	operationRegex = regexp.MustCompile(`(?i)^\s*(\w+)`)
+	tableRegex     = regexp.MustCompile("(?is)\\b(?:FROM|INTO|UPDATE|TABLE)\\s+((?:`[^`]+`|\\w+)(?:\\s*\\.\\s*(?:`[^`]+`|\\w+))?)")
+)
 
 func namedValueToValues(args []driver.NamedValue) []driver.Value {
 	vals := make([]driver.Value, len(args))
@@ -9,3 +18,16 @@ func namedValueToValues(args []driver.NamedValue) []driver.Value {
 	}
 	return vals
 }
+
+// ParseQuery : extract the statement type and the table name from the query, the table name
+// will be empty if the statement doesn't target on any table, eg. `SELECT VERSION();`
+func ParseQuery(query string) (operation, table string) {
+	if m := operationRegex.FindStringSubmatch(query); len(m) > 1 {
+		operation = strings.ToUpper(m[1])
+	}
+	if m := tableRegex.FindStringSubmatch(query); len(m) > 1 {
+		paths := strings.Split(m[1], ".")
+		table = strings.Trim(strings.TrimSpace(paths[len(paths)-1]), "`")
+	}
+	return
+}
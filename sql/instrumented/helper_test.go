@@ -0,0 +1,28 @@
+package instrumented
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query     string
+		operation string
+		table     string
+	}{
+		{"SELECT VERSION();", "SELECT", ""},
+		{"SELECT `Name` FROM `sqlike`.`User` WHERE `ID` = ?;", "SELECT", "User"},
+		{"  insert into `db`.`Table` (`a`) VALUES (?);", "INSERT", "Table"},
+		{"UPDATE `db`.`Table` SET `a` = ?;", "UPDATE", "Table"},
+		{"DELETE FROM Table WHERE a = ?;", "DELETE", "Table"},
+		{"REPLACE INTO `Table` SELECT * FROM `Other`;", "REPLACE", "Table"},
+		{"ALTER TABLE `db`.`Table` ADD INDEX `IX` (`a`);", "ALTER", "Table"},
+		{"SHOW DATABASES;", "SHOW", ""},
+	} {
+		operation, table := ParseQuery(tc.query)
+		require.Equal(t, tc.operation, operation, tc.query)
+		require.Equal(t, tc.table, table, tc.query)
+	}
+}
@@ -24,6 +24,12 @@ type DataTyper interface {
 	DataType(info driver.Info, sf reflext.StructFielder) columns.Column
 }
 
+// NullTyper : is the nullable wrapper of the type, eg. `types.Null[T]`, the column is
+// built by the wrapped type and it's always nullable
+type NullTyper interface {
+	NullType() reflect.Type
+}
+
 // DataTypeFunc :
 type DataTypeFunc func(sf reflext.StructFielder) columns.Column
 
@@ -74,6 +80,18 @@ func (sb *Builder) GetColumn(info driver.Info, sf reflext.StructFielder) (column
 		return x.DataType(info, sf), nil
 	}
 
+	if x, ok := v.Interface().(NullTyper); ok {
+		col, err := sb.GetColumn(info, nullField{sf, x.NullType()})
+		if err != nil {
+			return columns.Column{}, err
+		}
+		col.Nullable = true
+		if _, ok := sf.Tag().LookUp("default"); !ok {
+			col.DefaultValue = nil
+		}
+		return col, nil
+	}
+
 	if x, ok := sb.typeMap[t]; ok {
 		return sb.builders[x](sf), nil
 	}
@@ -122,3 +140,17 @@ func (sb *Builder) SetDefaultTypes() {
 	sb.SetType(reflect.Slice, sqltype.Slice)
 	sb.SetType(reflect.Map, sqltype.Map)
 }
+
+// nullField : is the field of the wrapped type of `NullTyper`
+type nullField struct {
+	reflext.StructFielder
+	t reflect.Type
+}
+
+func (f nullField) Type() reflect.Type {
+	return f.t
+}
+
+func (f nullField) IsNullable() bool {
+	return true
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	semver "github.com/Masterminds/semver/v3"
+	"github.com/Oskang09/sqlike/jsonb"
 	"github.com/Oskang09/sqlike/reflext"
 	"github.com/Oskang09/sqlike/sql/charset"
 	"github.com/Oskang09/sqlike/sql/codec"
@@ -53,6 +54,7 @@ type Client struct {
 	logger  logs.Logger
 	cache   reflext.StructMapper
 	codec   codec.Codecer
+	json    *jsonb.Codec
 	dialect dialect.Dialect
 }
 
@@ -92,11 +94,37 @@ func (c *Client) SetPrimaryKey(pk string) *Client {
 // SetCodec : Codec is a component which handling the :
 // 1. encoding between input data and driver.Valuer
 // 2. decoding between output data and sql.Scanner
+// the json codec of `SetJSONCodec` will be registered on it
 func (c *Client) SetCodec(cdc codec.Codecer) *Client {
 	c.codec = cdc
+	c.attachJSONCodec()
 	return c
 }
 
+// SetJSONCodec : use the json codec to encode and decode the struct, array and map column,
+// the other coders of the codec set by `SetCodec` are kept. It will panic if the codec set by
+// `SetCodec` is not `*codec.Registry`
+func (c *Client) SetJSONCodec(jc *jsonb.Codec) *Client {
+	c.json = jc
+	c.attachJSONCodec()
+	return c
+}
+
+// attachJSONCodec : the registry is shared by every client or owned by the caller, so the json
+// coders are registered on a copy of it
+func (c *Client) attachJSONCodec() {
+	if c.json == nil {
+		return
+	}
+	rg, ok := c.codec.(*codec.Registry)
+	if !ok {
+		panic("sqlike: json codec can only be attached to *codec.Registry")
+	}
+	rg = rg.Clone()
+	codec.RegisterJSONCodec(rg, c.json)
+	c.codec = rg
+}
+
 // SetStructMapper : StructMapper is a mapper to reflect a struct on runtime and provide struct info
 func (c *Client) SetStructMapper(mapper reflext.StructMapper) *Client {
 	c.cache = mapper
@@ -0,0 +1,68 @@
+package sqlike
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sql/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetJSONCodec(t *testing.T) {
+	type flag bool
+	type item struct {
+		Name string
+	}
+
+	jc := jsonb.NewCodec(jsonb.Config{NameFunc: strings.ToLower})
+	newRegistry := func() codec.Codecer {
+		rg := codec.NewDefaultRegistry(nil)
+		rg.RegisterTypeEncoder(reflect.TypeOf(flag(false)), func(_ reflext.StructFielder, v reflect.Value) (interface{}, error) {
+			return "Y", nil
+		})
+		return rg
+	}
+	encode := func(c *Client, it interface{}) interface{} {
+		v := reflect.ValueOf(it)
+		encoder, err := c.codec.LookupEncoder(v)
+		require.NoError(t, err)
+		x, err := encoder(nil, v)
+		require.NoError(t, err)
+		return x
+	}
+
+	// the shared default registry is never modified
+	c := &Client{codec: codec.DefaultRegistry}
+	c.SetJSONCodec(jc)
+	require.NotEqual(t, codec.DefaultRegistry, c.codec)
+	require.Equal(t, `{"name":"abc"}`, string(encode(c, item{Name: "abc"}).([]byte)))
+	require.Equal(t, `{"Name":"abc"}`, string(encode(&Client{codec: codec.DefaultRegistry}, item{Name: "abc"}).([]byte)))
+
+	// the result is same regardless of the call order
+	for _, c := range []*Client{
+		(&Client{codec: codec.DefaultRegistry}).SetCodec(newRegistry()).SetJSONCodec(jc),
+		(&Client{codec: codec.DefaultRegistry}).SetJSONCodec(jc).SetCodec(newRegistry()),
+	} {
+		require.Equal(t, `{"name":"abc"}`, string(encode(c, item{Name: "abc"}).([]byte)))
+		require.Equal(t, `{"name":"abc"}`, string(encode(c, &item{Name: "abc"}).([]byte)))
+		require.Equal(t, "Y", encode(c, flag(true)))
+	}
+
+	// the registry owned by the caller is never modified
+	rg := newRegistry()
+	c = (&Client{codec: codec.DefaultRegistry}).SetCodec(rg).SetJSONCodec(jc)
+	require.Equal(t, `{"name":"abc"}`, string(encode(c, item{Name: "abc"}).([]byte)))
+	require.Equal(t, `{"Name":"abc"}`, string(encode(&Client{codec: rg}, item{Name: "abc"}).([]byte)))
+	require.Equal(t, `{"Name":"abc"}`, string(encode(&Client{codec: rg}, &item{Name: "abc"}).([]byte)))
+
+	require.Panics(t, func() {
+		(&Client{codec: customCodec{rg}}).SetJSONCodec(jc)
+	})
+}
+
+type customCodec struct {
+	codec.Codecer
+}
@@ -39,6 +39,12 @@ type Column struct {
 
 	// extra information
 	Extra string
+
+	// expression of generated column
+	Expression string
+
+	// spatial reference system identifier of spatial column
+	SRID *uint32
 }
 
 // ColumnView :
@@ -15,3 +15,17 @@ type Column struct {
 	Collation    *string
 	Extra        string
 }
+
+// Definition : the definition of the existing column, which derived from the information schema
+type Definition struct {
+	Name         string
+	Type         string
+	Nullable     bool
+	DefaultValue *string
+	Charset      *string
+	Collation    *string
+	Comment      string
+	Extra        string
+	Expression   string
+	SRID         *uint32
+}
@@ -40,6 +40,33 @@ func (db *Database) Name() string {
 	return db.name
 }
 
+// ListTables : list all the base tables of the database
+func (db *Database) ListTables(ctx context.Context) ([]string, error) {
+	stmt := sqlstmt.AcquireStmt(db.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	db.dialect.GetTables(stmt, db.name)
+	rows, err := driver.Query(
+		ctx,
+		db.driver,
+		stmt,
+		db.logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := make([]string, 0)
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	return tables, rows.Err()
+}
+
 // Table : use the table under this database
 func (db *Database) Table(name string) *Table {
 	return &Table{
@@ -168,14 +195,22 @@ func (db *Database) RunInTransaction(ctx context.Context, cb txCallback, opts ..
 
 type indexDefinition struct {
 	Indexes []struct {
-		Table   string `yaml:"table"`
-		Name    string `yaml:"name"`
-		Type    string `yaml:"type"`
-		Cast    string `yaml:"cast"`
-		As      string `yaml:"as"`
-		Comment string `yaml:"comment"`
-		Columns []struct {
+		Table        string `yaml:"table"`
+		Name         string `yaml:"name"`
+		Type         string `yaml:"type"`
+		Cast         string `yaml:"cast"`
+		As           string `yaml:"as"`
+		Comment      string `yaml:"comment"`
+		Using        string `yaml:"using"`
+		KeyBlockSize uint   `yaml:"key_block_size"`
+		Parser       string `yaml:"parser"`
+		Visible      *bool  `yaml:"visible"`
+		Algorithm    string `yaml:"algorithm"`
+		Lock         string `yaml:"lock"`
+		Columns      []struct {
 			Name      string `yaml:"name"`
+			Expr      string `yaml:"expr"`
+			Length    uint   `yaml:"length"`
 			Direction string `yaml:"direction"`
 		} `yaml:"columns"`
 	} `yaml:"indexes"`
@@ -183,6 +218,59 @@ type indexDefinition struct {
 
 // BuildIndexes :
 func (db *Database) BuildIndexes(ctx context.Context, paths ...string) error {
+	return db.walkIndexes(paths, func(table string, index indexes.Index) error {
+		if exists, err := isIndexExists(
+			ctx,
+			db.name,
+			table,
+			index.GetName(),
+			db.driver,
+			db.dialect,
+			db.logger,
+		); err != nil {
+			return err
+		} else if exists {
+			return nil
+		}
+
+		iv := db.Table(table).Indexes()
+		return iv.CreateOne(ctx, index)
+	})
+}
+
+// SyncIndexes : compare the indexes which declared in yaml files with the existing indexes
+// of every declared table, it will create the missing indexes, recreate the changed indexes
+// and drop the orphaned indexes if `DropOrphans` is enabled. The yaml files will be the
+// source of truth, use `PlanOnly` to print the DDL without executing it.
+func (db *Database) SyncIndexes(ctx context.Context, opt *options.SyncIndexesOptions, paths ...string) ([]IndexDrift, error) {
+	if opt == nil {
+		opt = options.SyncIndexes()
+	}
+
+	tables := make([]string, 0)
+	declared := make(map[string][]indexes.Index)
+	if err := db.walkIndexes(paths, func(table string, index indexes.Index) error {
+		if _, ok := declared[table]; !ok {
+			tables = append(tables, table)
+		}
+		declared[table] = append(declared[table], index)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	drifts := make([]IndexDrift, 0, len(tables))
+	for _, table := range tables {
+		drift, err := db.Table(table).Indexes().Sync(ctx, declared[table], opt)
+		if err != nil {
+			return drifts, err
+		}
+		drifts = append(drifts, *drift)
+	}
+	return drifts, nil
+}
+
+func (db *Database) walkIndexes(paths []string, fn func(table string, index indexes.Index) error) error {
 	var (
 		path string
 		err  error
@@ -222,13 +310,13 @@ func (db *Database) BuildIndexes(ctx context.Context, paths ...string) error {
 				return nil
 			}
 
-			return db.readAndBuildIndexes(ctx, fp)
+			return readIndexes(fp, fn)
 		}); err != nil {
 			return err
 		}
 
 	case v.IsRegular():
-		if err := db.readAndBuildIndexes(ctx, path); err != nil {
+		if err := readIndexes(path, fn); err != nil {
 			return err
 		}
 	}
@@ -236,7 +324,7 @@ func (db *Database) BuildIndexes(ctx context.Context, paths ...string) error {
 	return nil
 }
 
-func (db *Database) readAndBuildIndexes(ctx context.Context, path string) error {
+func readIndexes(path string, fn func(table string, index indexes.Index) error) error {
 	var id indexDefinition
 	b, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -255,37 +343,46 @@ func (db *Database) readAndBuildIndexes(ctx context.Context, path string) error
 			if col.Direction == "desc" || col.Direction == "descending" {
 				dir = indexes.Descending
 			}
+			if col.Expr != "" {
+				columns[i] = indexes.Expr(col.Expr, dir)
+				continue
+			}
 			columns[i] = indexes.Col{
 				Name:      col.Name,
 				Direction: dir,
+				Length:    col.Length,
+			}
+			// support prefix length in name, eg. `Name(20)`
+			if strings.HasSuffix(col.Name, ")") {
+				c := indexes.Column(col.Name)
+				columns[i].Name = c.Name
+				if c.Length > 0 {
+					columns[i].Length = c.Length
+				}
 			}
 		}
 
 		index := indexes.Index{
-			Name:    strings.TrimSpace(idx.Name),
-			Type:    parseIndexType(idx.Type),
-			Cast:    strings.TrimSpace(idx.Cast),
-			As:      strings.TrimSpace(idx.As),
-			Columns: columns,
-			Comment: strings.TrimSpace(idx.Comment),
+			Name:         strings.TrimSpace(idx.Name),
+			Type:         parseIndexType(idx.Type),
+			Cast:         strings.TrimSpace(idx.Cast),
+			As:           strings.TrimSpace(idx.As),
+			Columns:      columns,
+			Comment:      strings.TrimSpace(idx.Comment),
+			Using:        strings.TrimSpace(idx.Using),
+			KeyBlockSize: idx.KeyBlockSize,
+			Parser:       strings.TrimSpace(idx.Parser),
+			Algorithm:    strings.TrimSpace(idx.Algorithm),
+			Lock:         strings.TrimSpace(idx.Lock),
 		}
-
-		if exists, err := isIndexExists(
-			ctx,
-			db.name,
-			idx.Table,
-			index.GetName(),
-			db.driver,
-			db.dialect,
-			db.logger,
-		); err != nil {
-			return err
-		} else if exists {
-			continue
+		if idx.Visible != nil {
+			index.Visibility = indexes.Invisible
+			if *idx.Visible {
+				index.Visibility = indexes.Visible
+			}
 		}
 
-		iv := db.Table(idx.Table).Indexes()
-		if err := iv.CreateOne(ctx, index); err != nil {
+		if err := fn(idx.Table, index); err != nil {
 			return err
 		}
 	}
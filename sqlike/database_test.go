@@ -28,3 +28,19 @@ func TestBuildIndexes(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestTableBind(t *testing.T) {
+	client := new(Client)
+	tb := &Table{dbName: "db", name: "User", client: client}
+
+	_, err := tb.Bind(nil)
+	require.Error(t, err)
+
+	_, err = tb.Bind(&Transaction{client: new(Client)})
+	require.Error(t, err)
+
+	bound, err := tb.Bind(&Transaction{dbName: "other", client: client})
+	require.NoError(t, err)
+	require.Equal(t, "db", bound.dbName)
+	require.Equal(t, "User", bound.name)
+}
@@ -0,0 +1,189 @@
+package ddl
+
+import (
+	"strings"
+)
+
+// Algorithm : the algorithm of the online DDL, the greater algorithm is the more expensive one
+type Algorithm int
+
+// algorithms :
+const (
+	DefaultAlgorithm Algorithm = iota
+	Instant
+	Inplace
+	Copy
+)
+
+func (a Algorithm) String() string {
+	switch a {
+	case Instant:
+		return "INSTANT"
+	case Inplace:
+		return "INPLACE"
+	case Copy:
+		return "COPY"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// Lock : the lock of the online DDL, the greater lock is the more restrictive one
+type Lock int
+
+// locks :
+const (
+	DefaultLock Lock = iota
+	NoneLock
+	SharedLock
+	ExclusiveLock
+)
+
+func (l Lock) String() string {
+	switch l {
+	case NoneLock:
+		return "NONE"
+	case SharedLock:
+		return "SHARED"
+	case ExclusiveLock:
+		return "EXCLUSIVE"
+	default:
+		return "DEFAULT"
+	}
+}
+
+// OperationType :
+type OperationType int
+
+// operation types :
+const (
+	AddColumn OperationType = iota + 1
+	ModifyColumn
+	DropColumn
+	AddIndex
+	DropIndex
+	AddPrimaryKey
+	AddCheck
+	DropCheck
+	TableOption
+)
+
+func (t OperationType) String() string {
+	switch t {
+	case AddColumn:
+		return "ADD COLUMN"
+	case ModifyColumn:
+		return "MODIFY COLUMN"
+	case DropColumn:
+		return "DROP COLUMN"
+	case AddIndex:
+		return "ADD INDEX"
+	case DropIndex:
+		return "DROP INDEX"
+	case AddPrimaryKey:
+		return "ADD PRIMARY KEY"
+	case AddCheck:
+		return "ADD CHECK"
+	case DropCheck:
+		return "DROP CHECK"
+	default:
+		return "TABLE OPTION"
+	}
+}
+
+// Operation : a clause of the `ALTER TABLE` statement
+type Operation struct {
+	Type OperationType
+
+	// column, index or constraint name of the operation
+	Name string
+
+	// sql clause of the operation, eg. ADD `Name` VARCHAR(191)
+	Clause string
+
+	// the cheapest algorithm and the least restrictive lock supported by the operation
+	Algorithm Algorithm
+	Lock      Lock
+
+	// whether the operation may lose data, eg. drop column or narrow the data type
+	Destructive bool
+
+	// explanation of the algorithm or destructiveness
+	Reason string
+}
+
+// Plan : the migration plan of a table
+type Plan struct {
+	Table string
+
+	// table is not exists and will be created
+	Create bool
+
+	// the DDL statement which will be executed
+	Statement string
+
+	// the algorithm and lock required by the statement
+	Algorithm Algorithm
+	Lock      Lock
+
+	Operations []Operation
+}
+
+// Destructive : return the operations which may lose data
+func (p *Plan) Destructive() []Operation {
+	ops := make([]Operation, 0)
+	for _, op := range p.Operations {
+		if op.Destructive {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// String : return the report of the plan
+func (p *Plan) String() string {
+	blr := new(strings.Builder)
+	if p.Create {
+		blr.WriteString("create table " + p.Table + "\n")
+		return blr.String()
+	}
+	blr.WriteString("alter table " + p.Table + " (algorithm: " + p.Algorithm.String() + ", lock: " + p.Lock.String() + ")\n")
+	for _, op := range p.Operations {
+		blr.WriteString("  " + op.Algorithm.String())
+		if op.Destructive {
+			blr.WriteString(" DESTRUCTIVE")
+		}
+		blr.WriteString(" " + op.Clause)
+		if op.Reason != "" {
+			blr.WriteString(" -- " + op.Reason)
+		}
+		blr.WriteByte('\n')
+	}
+	return blr.String()
+}
+
+// Error : the error of the refused operations
+type Error struct {
+	Table      string
+	Operations []Operation
+}
+
+// Error :
+func (e *Error) Error() string {
+	blr := new(strings.Builder)
+	blr.WriteString("ddl: refused to alter table " + e.Table + ": ")
+	for i, op := range e.Operations {
+		if i > 0 {
+			blr.WriteString(", ")
+		}
+		blr.WriteString(op.Type.String() + " " + op.Name + " [" + op.Algorithm.String())
+		if op.Destructive {
+			blr.WriteString(", DESTRUCTIVE")
+		}
+		blr.WriteByte(']')
+		if op.Reason != "" {
+			blr.WriteString(" (" + op.Reason + ")")
+		}
+	}
+	return blr.String()
+}
@@ -0,0 +1,545 @@
+package sqlike
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Oskang09/sqlike/sql/dialect"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/Oskang09/sqlike/sqlike/options"
+)
+
+// DiffKind :
+type DiffKind int
+
+// diff kinds :
+const (
+	// DiffAdded is the object which only exists on the source database, it will be created on the target database
+	DiffAdded DiffKind = iota + 1
+
+	// DiffRemoved is the object which only exists on the target database
+	DiffRemoved
+
+	// DiffChanged is the object which the definition is different
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "+"
+	case DiffRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// SchemaDiff : is the schema difference between the source and target database,
+// the statements will reconcile the target database with the source database
+type SchemaDiff struct {
+	// the source database name
+	Source string
+
+	// the target database name
+	Target string
+
+	// the tables which are different, sorted by table name
+	Tables []TableDiff
+}
+
+// TableDiff :
+type TableDiff struct {
+	Name       string
+	Kind       DiffKind
+	Columns    []ColumnDiff
+	Indexes    []IndexDiff
+	Statements []string
+}
+
+// ColumnDiff :
+type ColumnDiff struct {
+	Name string
+	Kind DiffKind
+
+	// Changes is the changed attributes in `attribute: target => source` format
+	Changes []string
+}
+
+// IndexDiff :
+type IndexDiff struct {
+	Name string
+	Kind DiffKind
+
+	// Changes is the changed attributes in `attribute: target => source` format
+	Changes []string
+}
+
+// HasDiff : return true if the schema of the databases are different
+func (d *SchemaDiff) HasDiff() bool {
+	return len(d.Tables) > 0
+}
+
+// Statements : return the statements which reconcile the target database with the source database,
+// the statements which drop the objects are commented out unless `DropExtras` is enabled
+func (d *SchemaDiff) Statements() []string {
+	stmts := make([]string, 0)
+	for _, tb := range d.Tables {
+		stmts = append(stmts, tb.Statements...)
+	}
+	return stmts
+}
+
+// WriteScript : write the statements into the writer
+func (d *SchemaDiff) WriteScript(w io.Writer) error {
+	for _, stmt := range d.Statements() {
+		if _, err := io.WriteString(w, stmt+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteReport : write the human readable report into the writer
+func (d *SchemaDiff) WriteReport(w io.Writer) error {
+	buf := new(bytes.Buffer)
+	buf.WriteString("--- source: " + d.Source + "\n")
+	buf.WriteString("+++ target: " + d.Target + "\n")
+	if !d.HasDiff() {
+		buf.WriteString("\nno difference\n")
+	}
+	for _, tb := range d.Tables {
+		buf.WriteString("\n" + tb.Kind.String() + " table " + tb.Name)
+		switch tb.Kind {
+		case DiffAdded:
+			buf.WriteString(" (missing on target)\n")
+			continue
+		case DiffRemoved:
+			buf.WriteString(" (only on target)\n")
+			continue
+		}
+		buf.WriteByte('\n')
+		for _, col := range tb.Columns {
+			buf.WriteString("  " + col.Kind.String() + " column " + col.Name + "\n")
+			for _, c := range col.Changes {
+				buf.WriteString("      " + c + "\n")
+			}
+		}
+		for _, idx := range tb.Indexes {
+			buf.WriteString("  " + idx.Kind.String() + " index " + idx.Name + "\n")
+			for _, c := range idx.Changes {
+				buf.WriteString("      " + c + "\n")
+			}
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// String : return the human readable report
+func (d *SchemaDiff) String() string {
+	buf := new(bytes.Buffer)
+	d.WriteReport(buf)
+	return buf.String()
+}
+
+// tableSchema : the columns and indexes of the table, it's nil if the table not exists
+type tableSchema struct {
+	options *tableOptions
+	columns []Column
+	indexes []IndexDetail
+}
+
+// tableOptions : the engine, default character set, collation and comment of the table
+type tableOptions struct {
+	engine    *string
+	charset   *string
+	collation *string
+	comment   string
+}
+
+// Diff : compare the tables, columns and indexes of the database (source) with the target database, and
+// generate the statements to reconcile the target database. Columns are compared on type, nullability,
+// default value, charset, collation, generated expression, extra and comment.
+func (db *Database) Diff(ctx context.Context, target *Database, opts ...*options.DiffOptions) (*SchemaDiff, error) {
+	opt := new(options.DiffOptions)
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+
+	source, err := db.loadSchemas(ctx, opt.Tables)
+	if err != nil {
+		return nil, err
+	}
+	dest, err := target.loadSchemas(ctx, opt.Tables)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &SchemaDiff{Source: db.name, Target: target.name}
+	names := make([]string, 0, len(source)+len(dest))
+	for name := range source {
+		names = append(names, name)
+	}
+	for name := range dest {
+		if _, ok := source[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		tb := diffTable(target.dialect, target.name, name, source[name], dest[name], opt.DropExtras)
+		if tb != nil {
+			diff.Tables = append(diff.Tables, *tb)
+		}
+	}
+	return diff, nil
+}
+
+func (db *Database) loadSchemas(ctx context.Context, patterns []string) (map[string]*tableSchema, error) {
+	tables, err := db.ListTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	schemas := make(map[string]*tableSchema, len(tables))
+	for _, table := range tables {
+		if len(patterns) > 0 && !isAllowed(patterns, table) {
+			continue
+		}
+		tb := db.Table(table)
+		tbOpts, err := tb.getTableOptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		columns, err := tb.ListColumns(ctx)
+		if err != nil {
+			return nil, err
+		}
+		idxs, err := tb.Indexes().ListDetails(ctx)
+		if err != nil {
+			return nil, err
+		}
+		schemas[table] = &tableSchema{options: tbOpts, columns: columns, indexes: idxs}
+	}
+	return schemas, nil
+}
+
+// diffTable : compare the source and target table, it will return nil if there is no difference
+func diffTable(dia dialect.Dialect, dbName, table string, src, dst *tableSchema, dropExtras bool) *TableDiff {
+	tb := &TableDiff{Name: table}
+	tableName := dia.TableName(dbName, table)
+	switch {
+	case src == nil:
+		tb.Kind = DiffRemoved
+		tb.Statements = append(tb.Statements, commentOut("DROP TABLE "+tableName+";", !dropExtras))
+		return tb
+
+	case dst == nil:
+		tb.Kind = DiffAdded
+		tb.Statements = createTableStatements(dia, tableName, src)
+		return tb
+	}
+
+	tb.Kind = DiffChanged
+	var (
+		clauses = make([]string, 0)
+		extras  = make([]string, 0)
+		notes   = make([]string, 0)
+	)
+
+	// indexes which are changed or removed have to be dropped before altering the columns
+	srcIdxs := make(map[string]IndexDetail, len(src.indexes))
+	for _, idx := range src.indexes {
+		srcIdxs[idx.Name] = idx
+	}
+	dstIdxs := make(map[string]IndexDetail, len(dst.indexes))
+	for _, idx := range dst.indexes {
+		dstIdxs[idx.Name] = idx
+	}
+	adds := make([]IndexDetail, 0)
+	for _, idx := range dst.indexes {
+		s, ok := srcIdxs[idx.Name]
+		if !ok {
+			tb.Indexes = append(tb.Indexes, IndexDiff{Name: idx.Name, Kind: DiffRemoved})
+			extras = append(extras, dropIndexClause(dia, idx.Name))
+			continue
+		}
+		if changes := compareIndex(s, idx); len(changes) > 0 {
+			tb.Indexes = append(tb.Indexes, IndexDiff{Name: idx.Name, Kind: DiffChanged, Changes: changes})
+			if !hasExpressions(s) {
+				notes = append(notes, "-- functional index "+dia.Quote(idx.Name)+" of "+tableName+" cannot be recreated, the expression is unknown")
+				continue
+			}
+			clauses = append(clauses, dropIndexClause(dia, idx.Name))
+			adds = append(adds, s)
+		}
+	}
+	for _, idx := range src.indexes {
+		if _, ok := dstIdxs[idx.Name]; ok {
+			continue
+		}
+		tb.Indexes = append(tb.Indexes, IndexDiff{Name: idx.Name, Kind: DiffAdded})
+		if !hasExpressions(idx) {
+			notes = append(notes, "-- functional index "+dia.Quote(idx.Name)+" of "+tableName+" cannot be created, the expression is unknown")
+			continue
+		}
+		adds = append(adds, idx)
+	}
+
+	dstCols := make(map[string]Column, len(dst.columns))
+	for _, col := range dst.columns {
+		dstCols[col.Name] = col
+	}
+	srcCols := make(map[string]bool, len(src.columns))
+	for i, col := range src.columns {
+		srcCols[col.Name] = true
+		d, ok := dstCols[col.Name]
+		if !ok {
+			tb.Columns = append(tb.Columns, ColumnDiff{Name: col.Name, Kind: DiffAdded})
+			clause := "ADD COLUMN " + columnDefinition(dia, col)
+			if i == 0 {
+				clause += " FIRST"
+			} else {
+				clause += " AFTER " + dia.Quote(src.columns[i-1].Name)
+			}
+			clauses = append(clauses, clause)
+			continue
+		}
+		if changes := compareColumn(col, d); len(changes) > 0 {
+			tb.Columns = append(tb.Columns, ColumnDiff{Name: col.Name, Kind: DiffChanged, Changes: changes})
+			clauses = append(clauses, "MODIFY COLUMN "+columnDefinition(dia, col))
+		}
+	}
+	for _, col := range dst.columns {
+		if srcCols[col.Name] {
+			continue
+		}
+		tb.Columns = append(tb.Columns, ColumnDiff{Name: col.Name, Kind: DiffRemoved})
+		extras = append(extras, "DROP COLUMN "+dia.Quote(col.Name))
+	}
+
+	for _, idx := range adds {
+		clauses = append(clauses, "ADD "+keyDefinition(dia, idx))
+	}
+
+	if len(tb.Columns) == 0 && len(tb.Indexes) == 0 {
+		return nil
+	}
+
+	if dropExtras {
+		clauses = append(clauses, extras...)
+		extras = nil
+	}
+	if len(clauses) > 0 {
+		tb.Statements = append(tb.Statements, "ALTER TABLE "+tableName+" "+strings.Join(clauses, ", ")+";")
+	}
+	if len(extras) > 0 {
+		tb.Statements = append(tb.Statements, commentOut("ALTER TABLE "+tableName+" "+strings.Join(extras, ", ")+";", true))
+	}
+	tb.Statements = append(tb.Statements, notes...)
+	return tb
+}
+
+func createTableStatements(dia dialect.Dialect, tableName string, src *tableSchema) []string {
+	defs := make([]string, 0, len(src.columns)+len(src.indexes))
+	notes := make([]string, 0)
+	for _, col := range src.columns {
+		defs = append(defs, columnDefinition(dia, col))
+	}
+	for _, idx := range src.indexes {
+		if !hasExpressions(idx) {
+			notes = append(notes, "-- functional index "+dia.Quote(idx.Name)+" of "+tableName+" cannot be created, the expression is unknown")
+			continue
+		}
+		defs = append(defs, keyDefinition(dia, idx))
+	}
+	return append([]string{"CREATE TABLE " + tableName + " (" + strings.Join(defs, ", ") + ")" + tableOptionsClause(dia, src.options) + ";"}, notes...)
+}
+
+// tableOptionsClause : the table options of the source table, the engine fallback to InnoDB if it's unknown
+func tableOptionsClause(dia dialect.Dialect, opt *tableOptions) string {
+	if opt == nil {
+		opt = new(tableOptions)
+	}
+	blr := new(strings.Builder)
+	if opt.engine != nil && *opt.engine != "" {
+		blr.WriteString(" ENGINE=" + *opt.engine)
+	} else {
+		blr.WriteString(" ENGINE=InnoDB")
+	}
+	if opt.charset != nil && *opt.charset != "" {
+		blr.WriteString(" DEFAULT CHARSET=" + *opt.charset)
+	}
+	if opt.collation != nil && *opt.collation != "" {
+		blr.WriteString(" COLLATE=" + *opt.collation)
+	}
+	if opt.comment != "" {
+		blr.WriteString(" COMMENT=" + dia.QuoteString(opt.comment))
+	}
+	return blr.String()
+}
+
+// compareColumn : return the changed attributes of the column
+func compareColumn(src, dst Column) []string {
+	changes := make([]string, 0)
+	compare := func(attr, target, source string) {
+		if target != source {
+			changes = append(changes, attr+": "+target+" => "+source)
+		}
+	}
+	compare("type", strings.ToUpper(dst.Type), strings.ToUpper(src.Type))
+	compare("nullable", strconv.FormatBool(bool(dst.IsNullable)), strconv.FormatBool(bool(src.IsNullable)))
+	compare("default", optional(dst.DefaultValue), optional(src.DefaultValue))
+	compare("charset", optional(dst.Charset), optional(src.Charset))
+	compare("collation", optional(dst.Collation), optional(src.Collation))
+	compare("expression", dst.Expression, src.Expression)
+	compare("extra", columnExtra(dst), columnExtra(src))
+	compare("comment", dst.Comment, src.Comment)
+	compare("srid", optionalUint(dst.SRID), optionalUint(src.SRID))
+	return changes
+}
+
+// compareIndex : return the changed attributes of the index
+func compareIndex(src, dst IndexDetail) []string {
+	changes := make([]string, 0)
+	compare := func(attr, target, source string) {
+		if target != source {
+			changes = append(changes, attr+": "+target+" => "+source)
+		}
+	}
+	compare("type", dst.Type, src.Type)
+	compare("unique", strconv.FormatBool(dst.IsUnique), strconv.FormatBool(src.IsUnique))
+	compare("columns", keyParts(dst), keyParts(src))
+	compare("comment", dst.Comment, src.Comment)
+	return changes
+}
+
+// columnDefinition : the column definition derived from the information schema
+func columnDefinition(dia dialect.Dialect, col Column) string {
+	return dia.ColumnDefinition(columns.Definition{
+		Name:         col.Name,
+		Type:         col.Type,
+		Nullable:     bool(col.IsNullable),
+		DefaultValue: col.DefaultValue,
+		Charset:      col.Charset,
+		Collation:    col.Collation,
+		Comment:      col.Comment,
+		Extra:        col.Extra,
+		Expression:   col.Expression,
+		SRID:         col.SRID,
+	})
+}
+
+// keyDefinition : the index definition derived from the information schema, eg. UNIQUE KEY `UX_Email` (`Email`)
+func keyDefinition(dia dialect.Dialect, idx IndexDetail) string {
+	blr := new(strings.Builder)
+	switch {
+	case idx.Name == "PRIMARY":
+		blr.WriteString("PRIMARY KEY ")
+	case idx.Type == "FULLTEXT":
+		blr.WriteString("FULLTEXT KEY " + dia.Quote(idx.Name) + " ")
+	case idx.Type == "SPATIAL":
+		blr.WriteString("SPATIAL KEY " + dia.Quote(idx.Name) + " ")
+	case idx.IsUnique:
+		blr.WriteString("UNIQUE KEY " + dia.Quote(idx.Name) + " ")
+	default:
+		blr.WriteString("KEY " + dia.Quote(idx.Name) + " ")
+	}
+	blr.WriteByte('(')
+	for i, col := range idx.Columns {
+		if i > 0 {
+			blr.WriteByte(',')
+		}
+		if col.Expr != "" {
+			blr.WriteString("(" + col.Expr + ")")
+		} else {
+			blr.WriteString(dia.Quote(col.Name))
+		}
+		if col.Length > 0 {
+			blr.WriteString("(" + strconv.FormatUint(uint64(col.Length), 10) + ")")
+		}
+		if col.Direction == indexes.Descending {
+			blr.WriteString(" DESC")
+		}
+	}
+	blr.WriteByte(')')
+	if idx.Comment != "" {
+		blr.WriteString(" COMMENT " + dia.QuoteString(idx.Comment))
+	}
+	return blr.String()
+}
+
+func dropIndexClause(dia dialect.Dialect, name string) string {
+	if name == "PRIMARY" {
+		return "DROP PRIMARY KEY"
+	}
+	return "DROP INDEX " + dia.Quote(name)
+}
+
+// keyParts : the comparable key parts of the index, eg. `Name(20),Age DESC`
+func keyParts(idx IndexDetail) string {
+	parts := make([]string, len(idx.Columns))
+	for i, col := range idx.Columns {
+		name := col.Name
+		switch {
+		case col.Expr != "":
+			name = "(" + col.Expr + ")"
+		case name == "":
+			name = "(expression)"
+		}
+		if col.Length > 0 {
+			name += "(" + strconv.FormatUint(uint64(col.Length), 10) + ")"
+		}
+		if col.Direction == indexes.Descending {
+			name += " DESC"
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, ",")
+}
+
+// hasExpressions : the expression of functional key part is only available on 8.0.13 and above
+func hasExpressions(idx IndexDetail) bool {
+	for _, col := range idx.Columns {
+		if col.Name == "" && col.Expr == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// columnExtra : the extra information without `DEFAULT_GENERATED`, which is implied by the default value
+func columnExtra(col Column) string {
+	extra := strings.ToUpper(col.Extra)
+	extra = strings.Replace(extra, "DEFAULT_GENERATED", "", 1)
+	return strings.Join(strings.Fields(extra), " ")
+}
+
+func commentOut(stmt string, comment bool) string {
+	if comment {
+		return "-- " + stmt
+	}
+	return stmt
+}
+
+func optional(v *string) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%q", *v)
+}
+
+func optionalUint(v *uint32) string {
+	if v == nil {
+		return "<nil>"
+	}
+	return strconv.FormatUint(uint64(*v), 10)
+}
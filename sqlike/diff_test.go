@@ -0,0 +1,165 @@
+package sqlike
+
+import (
+	"testing"
+
+	"github.com/Oskang09/sqlike/sql/dialect/mysql"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffTable(t *testing.T) {
+	var (
+		dia     = mysql.New()
+		utf8mb4 = "utf8mb4"
+		collate = "utf8mb4_unicode_ci"
+		empty   = ""
+		zero    = "0"
+	)
+
+	src := &tableSchema{
+		columns: []Column{
+			{Name: "ID", Type: "BIGINT UNSIGNED", DataType: "BIGINT", Extra: "auto_increment"},
+			{Name: "Name", Type: "VARCHAR(191)", DataType: "VARCHAR", DefaultValue: &empty, Charset: &utf8mb4, Collation: &collate},
+			{Name: "Age", Type: "INT", DataType: "INT", DefaultValue: &zero},
+			{Name: "Bio", Type: "TEXT", DataType: "TEXT", IsNullable: true, Charset: &utf8mb4, Collation: &collate},
+		},
+		indexes: []IndexDetail{
+			{Index: Index{Name: "PRIMARY", Type: "BTREE", IsUnique: true}, Columns: indexes.Columns("ID")},
+			{Index: Index{Name: "IX_Name", Type: "BTREE"}, Columns: indexes.Columns("Name", "-Age")},
+			{Index: Index{Name: "FTX_Bio", Type: "FULLTEXT"}, Columns: indexes.Columns("Bio")},
+		},
+	}
+	dst := &tableSchema{
+		columns: []Column{
+			{Name: "ID", Type: "BIGINT UNSIGNED", DataType: "BIGINT", Extra: "auto_increment"},
+			{Name: "Name", Type: "VARCHAR(100)", DataType: "VARCHAR", IsNullable: true, Charset: &utf8mb4, Collation: &collate},
+			{Name: "Legacy", Type: "INT", DataType: "INT", DefaultValue: &zero},
+		},
+		indexes: []IndexDetail{
+			{Index: Index{Name: "PRIMARY", Type: "BTREE", IsUnique: true}, Columns: indexes.Columns("ID")},
+			{Index: Index{Name: "IX_Name", Type: "BTREE"}, Columns: indexes.Columns("Name")},
+			{Index: Index{Name: "IX_Legacy", Type: "BTREE"}, Columns: indexes.Columns("Legacy")},
+		},
+	}
+
+	// same schema
+	{
+		require.Nil(t, diffTable(dia, "db", "User", src, src, false))
+	}
+
+	// missing table on target
+	{
+		tb := diffTable(dia, "db", "User", src, nil, false)
+		require.Equal(t, DiffAdded, tb.Kind)
+		require.Equal(t, []string{
+			"CREATE TABLE `db`.`User` (" +
+				"`ID` bigint unsigned NOT NULL AUTO_INCREMENT, " +
+				"`Name` varchar(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '', " +
+				"`Age` int NOT NULL DEFAULT '0', " +
+				"`Bio` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL, " +
+				"PRIMARY KEY (`ID`), " +
+				"KEY `IX_Name` (`Name`,`Age` DESC), " +
+				"FULLTEXT KEY `FTX_Bio` (`Bio`)" +
+				") ENGINE=InnoDB;",
+		}, tb.Statements)
+	}
+
+	// missing table on target with the table options
+	{
+		engine, latin1, latin1Bin := "MyISAM", "latin1", "latin1_bin"
+		s := &tableSchema{
+			options: &tableOptions{engine: &engine, charset: &latin1, collation: &latin1Bin, comment: `user's \ profile`},
+			columns: src.columns[:1],
+		}
+		tb := diffTable(dia, "db", "User", s, nil, false)
+		require.Equal(t, []string{
+			"CREATE TABLE `db`.`User` (`ID` bigint unsigned NOT NULL AUTO_INCREMENT) " +
+				"ENGINE=MyISAM DEFAULT CHARSET=latin1 COLLATE=latin1_bin COMMENT='user''s \\\\ profile';",
+		}, tb.Statements)
+	}
+
+	// table only exists on target
+	{
+		tb := diffTable(dia, "db", "User", nil, dst, false)
+		require.Equal(t, DiffRemoved, tb.Kind)
+		require.Equal(t, []string{"-- DROP TABLE `db`.`User`;"}, tb.Statements)
+	}
+
+	// changed table
+	{
+		tb := diffTable(dia, "db", "User", src, dst, false)
+		require.Equal(t, DiffChanged, tb.Kind)
+		require.Equal(t, []ColumnDiff{
+			{Name: "Name", Kind: DiffChanged, Changes: []string{
+				"type: VARCHAR(100) => VARCHAR(191)",
+				"nullable: true => false",
+				`default: <nil> => ""`,
+			}},
+			{Name: "Age", Kind: DiffAdded},
+			{Name: "Bio", Kind: DiffAdded},
+			{Name: "Legacy", Kind: DiffRemoved},
+		}, tb.Columns)
+		require.Equal(t, []IndexDiff{
+			{Name: "IX_Name", Kind: DiffChanged, Changes: []string{"columns: Name => Name,Age DESC"}},
+			{Name: "IX_Legacy", Kind: DiffRemoved},
+			{Name: "FTX_Bio", Kind: DiffAdded},
+		}, tb.Indexes)
+		require.Equal(t, []string{
+			"ALTER TABLE `db`.`User` " +
+				"DROP INDEX `IX_Name`, " +
+				"MODIFY COLUMN `Name` varchar(191) CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NOT NULL DEFAULT '', " +
+				"ADD COLUMN `Age` int NOT NULL DEFAULT '0' AFTER `Name`, " +
+				"ADD COLUMN `Bio` text CHARACTER SET utf8mb4 COLLATE utf8mb4_unicode_ci NULL AFTER `Age`, " +
+				"ADD KEY `IX_Name` (`Name`,`Age` DESC), " +
+				"ADD FULLTEXT KEY `FTX_Bio` (`Bio`);",
+			"-- ALTER TABLE `db`.`User` DROP INDEX `IX_Legacy`, DROP COLUMN `Legacy`;",
+		}, tb.Statements)
+
+		tb = diffTable(dia, "db", "User", src, dst, true)
+		require.Len(t, tb.Statements, 1)
+		require.Contains(t, tb.Statements[0], "ADD FULLTEXT KEY `FTX_Bio` (`Bio`), DROP INDEX `IX_Legacy`, DROP COLUMN `Legacy`;")
+
+		diff := &SchemaDiff{Source: "staging", Target: "production", Tables: []TableDiff{*tb}}
+		require.Equal(t, `--- source: staging
++++ target: production
+
+~ table User
+  ~ column Name
+      type: VARCHAR(100) => VARCHAR(191)
+      nullable: true => false
+      default: <nil> => ""
+  + column Age
+  + column Bio
+  - column Legacy
+  ~ index IX_Name
+      columns: Name => Name,Age DESC
+  - index IX_Legacy
+  + index FTX_Bio
+`, diff.String())
+	}
+
+	// functional index
+	{
+		fx := IndexDetail{Index: Index{Name: "FX_Name", Type: "BTREE"}, Columns: []indexes.Col{
+			{Expr: "lower(`Name`)", Direction: indexes.Ascending},
+			{Name: "Age", Direction: indexes.Ascending},
+		}}
+		s := &tableSchema{columns: src.columns, indexes: append(src.indexes[:len(src.indexes):len(src.indexes)], fx)}
+		tb := diffTable(dia, "db", "User", s, src, false)
+		require.Equal(t, []IndexDiff{{Name: "FX_Name", Kind: DiffAdded}}, tb.Indexes)
+		require.Equal(t, []string{
+			"ALTER TABLE `db`.`User` ADD KEY `FX_Name` ((lower(`Name`)),`Age`);",
+		}, tb.Statements)
+
+		tb = diffTable(dia, "db", "User", s, nil, false)
+		require.Contains(t, tb.Statements[0], ", KEY `FX_Name` ((lower(`Name`)),`Age`)) ENGINE=InnoDB;")
+
+		// the expression is unknown before 8.0.13
+		fx.Columns[0].Expr = ""
+		tb = diffTable(dia, "db", "User", s, src, false)
+		require.Equal(t, []string{
+			"-- functional index `FX_Name` of `db`.`User` cannot be created, the expression is unknown",
+		}, tb.Statements)
+	}
+}
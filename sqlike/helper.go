@@ -6,11 +6,12 @@ import (
 	"github.com/Oskang09/sqlike/sqlike/logs"
 )
 
+// getLogger : the statement logger still able to log slow query and error when debug is off
 func getLogger(logger logs.Logger, debug bool) logs.Logger {
 	if debug {
 		return logger
 	}
-	return nil
+	return logs.Quiet(logger)
 }
 
 // we should skip column generated by virtual & stored columns on insertion and migration
@@ -2,6 +2,14 @@ package sqlike
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
 
 	semver "github.com/Masterminds/semver/v3"
 	sqldialect "github.com/Oskang09/sqlike/sql/dialect"
@@ -9,6 +17,7 @@ import (
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 	"github.com/Oskang09/sqlike/sqlike/indexes"
 	"github.com/Oskang09/sqlike/sqlike/logs"
+	"github.com/Oskang09/sqlike/sqlike/options"
 )
 
 var mysql8 = semver.MustParse("8.0.0")
@@ -20,6 +29,20 @@ type Index struct {
 	IsUnique bool
 }
 
+// Indexer : is the entity which declares its own indexes, such as composite, fulltext, spatial
+// and multi-valued indexes. The indexes will be created or recreated by the same statement of `Migrate`,
+// so they are classified and refused along with the other operations of the migration plan.
+//
+//	func (u User) Indexes() []indexes.Index {
+//		return []indexes.Index{
+//			{Name: "IX_Name_Age", Columns: indexes.Columns("Name", "-Age")},
+//			{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio")},
+//		}
+//	}
+type Indexer interface {
+	Indexes() []indexes.Index
+}
+
 // IndexView :
 type IndexView struct {
 	tb          *Table
@@ -136,6 +159,405 @@ func (idv *IndexView) DropAll(ctx context.Context) error {
 	return nil
 }
 
+// IndexDrift : is the difference between the declared indexes and the existing indexes of the table
+type IndexDrift struct {
+	// the table name
+	Table string
+
+	// Added is the declared indexes which not exists in the table
+	Added []indexes.Index
+
+	// Changed is the declared indexes which the definition is different from the existing index,
+	// it will be recreated except the primary key
+	Changed []indexes.Index
+
+	// Orphaned is the existing indexes which are not declared, primary key and the indexes
+	// which match the allowlist are excluded
+	Orphaned []Index
+
+	// Statements is the DDL which is executed, or will be executed on plan only mode
+	Statements []string
+}
+
+// HasDrift : return true if the existing indexes are different from the declared indexes
+func (d IndexDrift) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Changed) > 0 || len(d.Orphaned) > 0
+}
+
+// Sync : sync the indexes of the table with the declared indexes, the missing indexes will be created
+// and the changed indexes will be recreated, the orphaned indexes will only be dropped if `DropOrphans`
+// is enabled. The changes are applied by a single `ALTER TABLE`, so a changed index is never missing
+// in between. On plan only mode, the DDL will be printed without executing.
+func (idv *IndexView) Sync(ctx context.Context, idxs []indexes.Index, opts ...*options.SyncIndexesOptions) (*IndexDrift, error) {
+	opt := new(options.SyncIndexesOptions)
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+
+	for _, idx := range idxs {
+		if idx.Type != indexes.MultiValued && len(idx.Columns) < 1 {
+			return nil, ErrNoColumn
+		}
+	}
+
+	existing, err := idv.ListDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	supportDesc := idv.isSupportDesc()
+	drift := diffIndexes(idv.tb.name, idxs, existing, supportDesc, opt.Allowlist)
+
+	drops := make([]string, 0)
+	creates := make([]indexes.Index, 0, len(drift.Added)+len(drift.Changed))
+	creates = append(creates, drift.Added...)
+	for _, idx := range drift.Changed {
+		// primary key will never be altered
+		if idx.Type == indexes.Primary {
+			continue
+		}
+		drops = append(drops, idx.GetName())
+		creates = append(creates, idx)
+	}
+	if opt.DropOrphans {
+		for _, idx := range drift.Orphaned {
+			drops = append(drops, idx.Name)
+		}
+	}
+
+	stmt := sqlstmt.AcquireStmt(idv.tb.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	if len(drops) > 0 || len(creates) > 0 {
+		idv.tb.dialect.AlterIndexes(stmt, idv.tb.dbName, idv.tb.name, drops, creates, supportDesc)
+		drift.Statements = append(drift.Statements, stmt.String())
+		stmt.Reset()
+	}
+
+	w := opt.Output
+	if w == nil && opt.PlanOnly {
+		w = os.Stdout
+	}
+	for _, query := range drift.Statements {
+		if w != nil {
+			fmt.Fprintln(w, query)
+		}
+		if opt.PlanOnly {
+			continue
+		}
+		stmt.Reset()
+		stmt.WriteString(query)
+		if _, err := sqldriver.Execute(
+			ctx,
+			idv.tb.driver,
+			stmt,
+			idv.tb.logger,
+		); err != nil {
+			return drift, err
+		}
+	}
+	return drift, nil
+}
+
+// IndexDetail : is the existing index including the key parts, the name of functional key part is empty
+type IndexDetail struct {
+	Index
+	Comment    string
+	Columns    []indexes.Col
+	Functional bool
+	Invisible  bool
+	Parser     string
+
+	// KeyBlockSize is read from `SHOW CREATE TABLE`, it's zero when it's not specified
+	KeyBlockSize uint
+}
+
+// ListDetails : list all the indexes including the key parts in sequence order
+func (idv *IndexView) ListDetails(ctx context.Context) ([]IndexDetail, error) {
+	stmt := sqlstmt.AcquireStmt(idv.tb.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	idv.tb.dialect.GetIndexColumns(stmt, idv.tb.client.DriverInfo, idv.tb.dbName, idv.tb.name)
+	rows, err := sqldriver.Query(
+		ctx,
+		idv.tb.driver,
+		stmt,
+		idv.tb.logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	idxs := make([]IndexDetail, 0)
+	for rows.Next() {
+		var (
+			name      string
+			seq       int
+			column    sql.NullString
+			subPart   sql.NullInt64
+			collation sql.NullString
+			idxType   string
+			nonUnique bool
+			comment   string
+			visible   sql.NullString
+			expr      sql.NullString
+		)
+		dest := []interface{}{
+			&name,
+			&seq,
+			&column,
+			&subPart,
+			&collation,
+			&idxType,
+			&nonUnique,
+			&comment,
+			&visible,
+			&expr,
+		}
+		// `IS_VISIBLE` is not selected before 8.0 and `EXPRESSION` is not selected before 8.0.13
+		if err := rows.Scan(dest[:len(names)]...); err != nil {
+			return nil, err
+		}
+
+		if len(idxs) == 0 || idxs[len(idxs)-1].Name != name {
+			idxs = append(idxs, IndexDetail{
+				Index: Index{
+					Name:     name,
+					Type:     strings.ToUpper(idxType),
+					IsUnique: !nonUnique,
+				},
+				Comment:   comment,
+				Invisible: strings.EqualFold(visible.String, "NO"),
+			})
+		}
+
+		last := &idxs[len(idxs)-1]
+		col := indexes.Col{Name: column.String}
+		if collation.String == "D" {
+			col.Direction = indexes.Descending
+		}
+		if subPart.Valid {
+			col.Length = uint(subPart.Int64)
+		}
+		// the column name of functional key part is null
+		if !column.Valid {
+			last.Functional = true
+			col.Expr = expr.String
+		}
+		last.Columns = append(last.Columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return idxs, idv.fillOptions(ctx, idxs)
+}
+
+var (
+	indexLine    = regexp.MustCompile("(?m)^\\s*(?:PRIMARY KEY|(?:UNIQUE |FULLTEXT |SPATIAL )?KEY `([^`]+)`)\\s*\\((.*)$")
+	parserOption = regexp.MustCompile("WITH PARSER `?(\\w+)`?")
+	blockOption  = regexp.MustCompile("KEY_BLOCK_SIZE=(\\d+)")
+)
+
+// indexOptions : the index options which are not exposed by `INFORMATION_SCHEMA`
+type indexOptions struct {
+	parser       string
+	keyBlockSize uint
+}
+
+// fillOptions : the parser of fulltext index and the key block size are not exposed by
+// `INFORMATION_SCHEMA`, so it's read from `SHOW CREATE TABLE`
+func (idv *IndexView) fillOptions(ctx context.Context, idxs []IndexDetail) error {
+	if len(idxs) == 0 {
+		return nil
+	}
+
+	stmt := sqlstmt.AcquireStmt(idv.tb.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	idv.tb.dialect.ShowCreateTable(stmt, idv.tb.dbName, idv.tb.name)
+	var table, ddl string
+	if err := sqldriver.QueryRowContext(
+		ctx,
+		idv.tb.driver,
+		stmt,
+		idv.tb.logger,
+	).Scan(&table, &ddl); err != nil {
+		return err
+	}
+
+	opts := parseIndexOptions(ddl)
+	for i := range idxs {
+		opt := opts[idxs[i].Name]
+		idxs[i].Parser = opt.parser
+		idxs[i].KeyBlockSize = opt.keyBlockSize
+	}
+	return nil
+}
+
+func parseIndexOptions(ddl string) map[string]indexOptions {
+	opts := make(map[string]indexOptions)
+	for _, m := range indexLine.FindAllStringSubmatch(ddl, -1) {
+		name := m[1]
+		if name == "" {
+			name = "PRIMARY"
+		}
+		var opt indexOptions
+		if p := parserOption.FindStringSubmatch(m[2]); p != nil {
+			opt.parser = p[1]
+		}
+		if b := blockOption.FindStringSubmatch(m[2]); b != nil {
+			size, _ := strconv.ParseUint(b[1], 10, 64)
+			opt.keyBlockSize = uint(size)
+		}
+		if opt != (indexOptions{}) {
+			opts[name] = opt
+		}
+	}
+	return opts
+}
+
+func diffIndexes(table string, declared []indexes.Index, existing []IndexDetail, supportDesc bool, allowlist []string) *IndexDrift {
+	drift := &IndexDrift{Table: table}
+	lookup := make(map[string]IndexDetail, len(existing))
+	for _, idx := range existing {
+		lookup[idx.Name] = idx
+	}
+
+	names := make(map[string]bool, len(declared))
+	for _, idx := range declared {
+		name := idx.GetName()
+		if idx.Type == indexes.Primary {
+			name = "PRIMARY"
+		}
+		names[name] = true
+
+		detail, ok := lookup[name]
+		if !ok {
+			drift.Added = append(drift.Added, idx)
+			continue
+		}
+		if !detail.matches(idx, supportDesc) {
+			drift.Changed = append(drift.Changed, idx)
+		}
+	}
+
+	for _, idx := range existing {
+		if idx.Name == "PRIMARY" || names[idx.Name] || isAllowed(allowlist, idx.Name) {
+			continue
+		}
+		drift.Orphaned = append(drift.Orphaned, idx.Index)
+	}
+	return drift
+}
+
+func (d IndexDetail) matches(idx indexes.Index, supportDesc bool) bool {
+	// the index is visible unless it's declared as invisible
+	if d.Invisible != (idx.Visibility == indexes.Invisible) {
+		return false
+	}
+
+	if d.KeyBlockSize != idx.KeyBlockSize {
+		return false
+	}
+
+	using := "BTREE"
+	if idx.Using != "" {
+		using = strings.ToUpper(idx.Using)
+	}
+	// InnoDB doesn't support hash index, it's created as btree silently
+	if using == "HASH" && d.Type == "BTREE" {
+		using = d.Type
+	}
+	switch idx.Type {
+	case indexes.Primary:
+		if d.Name != "PRIMARY" || d.Type != using {
+			return false
+		}
+	case indexes.Unique:
+		if !d.IsUnique || d.Type != using {
+			return false
+		}
+	case indexes.FullText:
+		if d.Type != "FULLTEXT" || !strings.EqualFold(d.Parser, idx.Parser) {
+			return false
+		}
+	case indexes.Spatial:
+		if d.Type != "SPATIAL" {
+			return false
+		}
+	case indexes.MultiValued:
+		// the key part of multi-valued index is an expression, which is not comparable
+		return d.Functional && d.Comment == idx.Comment
+	default:
+		if d.IsUnique || d.Type != using {
+			return false
+		}
+	}
+
+	if d.Comment != idx.Comment || len(d.Columns) != len(idx.Columns) {
+		return false
+	}
+	for i, col := range idx.Columns {
+		if supportDesc && d.Columns[i].Direction != col.Direction {
+			return false
+		}
+		if col.Expr != "" {
+			// the expression of functional key part is unknown before 8.0.13
+			if d.Columns[i].Name != "" ||
+				(d.Columns[i].Expr != "" && normalizeExpr(d.Columns[i].Expr) != normalizeExpr(col.Expr)) {
+				return false
+			}
+			continue
+		}
+		if d.Columns[i].Name != col.Name || d.Columns[i].Length != col.Length {
+			return false
+		}
+	}
+	return true
+}
+
+var introducer = regexp.MustCompile(`(?i)_[a-z0-9]+'`)
+
+// normalizeExpr : the expression is stored in its canonical form, eg. `LOWER(Email)` is stored as
+// lower(`Email`), so the identifier quotes, spaces, charset introducers and the case out of the string
+// literals are ignored
+func normalizeExpr(expr string) string {
+	expr = introducer.ReplaceAllString(expr, "'")
+	blr := new(strings.Builder)
+	var quote rune
+	for _, c := range expr {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+			blr.WriteRune(c)
+		case c == '\'' || c == '"':
+			quote = c
+			blr.WriteRune(c)
+		case c == '`' || c == ' ' || c == '\t' || c == '\n':
+		default:
+			blr.WriteRune(unicode.ToLower(c))
+		}
+	}
+	return blr.String()
+}
+
+func isAllowed(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if pattern == name {
+			return true
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (idv *IndexView) isSupportDesc() bool {
 	if idv.supportDesc != nil {
 		return *idv.supportDesc
@@ -0,0 +1,182 @@
+package sqlike
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Oskang09/sqlike/sqlike/indexes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIndexes(t *testing.T) {
+	declared := []indexes.Index{
+		{Type: indexes.Primary, Columns: indexes.Columns("ID")},
+		{Name: "IX_Name", Columns: indexes.Columns("Name", "-Age")},
+		{Name: "UX_Email", Type: indexes.Unique, Columns: indexes.Columns("Email")},
+		{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio")},
+	}
+	existing := []IndexDetail{
+		{Index: Index{Name: "PRIMARY", Type: "BTREE", IsUnique: true}, Columns: indexes.Columns("ID")},
+		{Index: Index{Name: "IX_Name", Type: "BTREE"}, Columns: indexes.Columns("Name", "Age")},
+		{Index: Index{Name: "UX_Email", Type: "BTREE"}, Columns: indexes.Columns("Email")},
+		{Index: Index{Name: "IX_Legacy", Type: "BTREE"}, Columns: indexes.Columns("Legacy")},
+		{Index: Index{Name: "FK_Role", Type: "BTREE"}, Columns: indexes.Columns("RoleID")},
+	}
+
+	// direction is ignored when descending index is not supported
+	{
+		drift := diffIndexes("User", declared, existing, false, []string{"FK_*"})
+		require.Equal(t, "User", drift.Table)
+		require.True(t, drift.HasDrift())
+		require.Equal(t, []indexes.Index{declared[3]}, drift.Added)
+		require.Equal(t, []indexes.Index{declared[2]}, drift.Changed)
+		require.Equal(t, []Index{{Name: "IX_Legacy", Type: "BTREE"}}, drift.Orphaned)
+	}
+
+	{
+		drift := diffIndexes("User", declared, existing, true, nil)
+		require.Equal(t, []indexes.Index{declared[1], declared[2]}, drift.Changed)
+		require.Equal(t, []Index{
+			{Name: "IX_Legacy", Type: "BTREE"},
+			{Name: "FK_Role", Type: "BTREE"},
+		}, drift.Orphaned)
+	}
+
+	{
+		drift := diffIndexes("User", declared[:2], existing[:2], false, nil)
+		require.False(t, drift.HasDrift())
+	}
+}
+
+func TestIndexDetailMatches(t *testing.T) {
+	detail := IndexDetail{
+		Index:      Index{Name: "IX_Email", Type: "BTREE"},
+		Columns:    []indexes.Col{{}, {Name: "Name", Length: 20}},
+		Functional: true,
+	}
+	require.True(t, detail.matches(indexes.Index{
+		Name:    "IX_Email",
+		Columns: []indexes.Col{indexes.Expr("LOWER(`Email`)"), indexes.Column("Name(20)")},
+	}, false))
+	require.False(t, detail.matches(indexes.Index{
+		Name:    "IX_Email",
+		Columns: indexes.Columns("Email", "Name(20)"),
+	}, false))
+	require.False(t, detail.matches(indexes.Index{
+		Name:    "IX_Email",
+		Columns: []indexes.Col{indexes.Expr("LOWER(`Email`)"), indexes.Column("Name")},
+	}, false))
+
+	// the expression is compared when it's available (^8.0.13)
+	detail.Columns[0].Expr = "lower(`Email`)"
+	require.True(t, detail.matches(indexes.Index{
+		Name:    "IX_Email",
+		Columns: []indexes.Col{indexes.Expr("LOWER(Email)"), indexes.Column("Name(20)")},
+	}, false))
+	require.False(t, detail.matches(indexes.Index{
+		Name:    "IX_Email",
+		Columns: []indexes.Col{indexes.Expr("UPPER(Email)"), indexes.Column("Name(20)")},
+	}, false))
+
+	detail = IndexDetail{
+		Index:     Index{Name: "IX_Name", Type: "HASH"},
+		Columns:   indexes.Columns("Name"),
+		Invisible: true,
+	}
+	idx := indexes.Index{Name: "IX_Name", Using: "hash", Visibility: indexes.Invisible, Columns: indexes.Columns("Name")}
+	require.True(t, detail.matches(idx, false))
+	idx.Visibility = indexes.DefaultVisibility
+	require.False(t, detail.matches(idx, false))
+	detail.Invisible = false
+	require.True(t, detail.matches(idx, false))
+	idx.Using = ""
+	require.False(t, detail.matches(idx, false))
+
+	// hash index is created as btree on InnoDB
+	detail.Type = "BTREE"
+	idx.Using = "HASH"
+	require.True(t, detail.matches(idx, false))
+
+	// key block size
+	idx.KeyBlockSize = 8
+	require.False(t, detail.matches(idx, false))
+	detail.KeyBlockSize = 8
+	require.True(t, detail.matches(idx, false))
+
+	detail = IndexDetail{
+		Index:   Index{Name: "FTX_Bio", Type: "FULLTEXT"},
+		Columns: indexes.Columns("Bio"),
+		Parser:  "ngram",
+	}
+	idx = indexes.Index{Name: "FTX_Bio", Type: indexes.FullText, Parser: "ngram", Columns: indexes.Columns("Bio")}
+	require.True(t, detail.matches(idx, false))
+	idx.Parser = ""
+	require.False(t, detail.matches(idx, false))
+}
+
+func TestParseIndexOptions(t *testing.T) {
+	ddl := "CREATE TABLE `User` (\n" +
+		"  `ID` bigint NOT NULL,\n" +
+		"  `Bio` text NOT NULL,\n" +
+		"  `Title` varchar(191) NOT NULL,\n" +
+		"  PRIMARY KEY (`ID`) KEY_BLOCK_SIZE=4,\n" +
+		"  KEY `IX_Title` (`Title`) KEY_BLOCK_SIZE=8 COMMENT 'title',\n" +
+		"  FULLTEXT KEY `FTX_Bio` (`Bio`) /*!50100 WITH PARSER `ngram` */ ,\n" +
+		"  FULLTEXT KEY `FTX_Title` (`Title`)\n" +
+		") ENGINE=InnoDB"
+	require.Equal(t, map[string]indexOptions{
+		"PRIMARY":  {keyBlockSize: 4},
+		"IX_Title": {keyBlockSize: 8},
+		"FTX_Bio":  {parser: "ngram"},
+	}, parseIndexOptions(ddl))
+}
+
+func TestNormalizeExpr(t *testing.T) {
+	require.Equal(t, normalizeExpr("lower(`Email`)"), normalizeExpr("LOWER( Email )"))
+	require.Equal(t,
+		normalizeExpr("json_unquote(json_extract(`Meta`,_utf8mb4'$.Name'))"),
+		normalizeExpr("JSON_UNQUOTE(JSON_EXTRACT(Meta, '$.Name'))"),
+	)
+	// the string literal is case sensitive
+	require.NotEqual(t, normalizeExpr("concat(`A`,'X Y')"), normalizeExpr("concat(`A`,'x y')"))
+}
+
+type indexedEntity struct {
+	Name string
+	Age  int
+}
+
+func (indexedEntity) Indexes() []indexes.Index {
+	return []indexes.Index{
+		{Name: "IX_Name_Age", Columns: indexes.Columns("Name", "-Age")},
+	}
+}
+
+type pointerIndexedEntity struct {
+	Bio string
+}
+
+func (*pointerIndexedEntity) Indexes() []indexes.Index {
+	return []indexes.Index{
+		{Name: "FTX_Bio", Type: indexes.FullText, Columns: indexes.Columns("Bio")},
+	}
+}
+
+func TestGetEntityIndexes(t *testing.T) {
+	{
+		idxs := getEntityIndexes(indexedEntity{}, reflect.TypeOf(indexedEntity{}))
+		require.Len(t, idxs, 1)
+		require.Equal(t, "IX_Name_Age", idxs[0].Name)
+	}
+
+	{
+		idxs := getEntityIndexes(pointerIndexedEntity{}, reflect.TypeOf(pointerIndexedEntity{}))
+		require.Len(t, idxs, 1)
+		require.Equal(t, "FTX_Bio", idxs[0].Name)
+	}
+
+	{
+		type entity struct{ Name string }
+		require.Nil(t, getEntityIndexes(entity{}, reflect.TypeOf(entity{})))
+	}
+}
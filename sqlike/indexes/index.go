@@ -4,6 +4,8 @@ import (
 	"crypto/md5"
 	"fmt"
 	"io"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/valyala/bytebufferpool"
@@ -45,6 +47,16 @@ func (t Type) String() string {
 	}
 }
 
+// Visibility :
+type Visibility int
+
+// visibilities :
+const (
+	DefaultVisibility Visibility = iota
+	Visible
+	Invisible
+)
+
 // Index :
 type Index struct {
 	Name    string
@@ -53,6 +65,24 @@ type Index struct {
 	Type    Type
 	Columns []Col
 	Comment string
+
+	// Using is the index structure, eg. `BTREE` or `HASH`
+	Using string
+
+	// KeyBlockSize is the size in bytes to use for index key blocks
+	KeyBlockSize uint
+
+	// Parser is the full-text parser plugin, eg. `ngram`
+	Parser string
+
+	// Visibility will mark the index as `VISIBLE` or `INVISIBLE` to the optimizer
+	Visibility Visibility
+
+	// Algorithm and Lock are the online DDL options, eg. `INPLACE` and `NONE`. They are
+	// table options, so only the first non-empty value will be used when creating
+	// multiple indexes in a single statement
+	Algorithm string
+	Lock      string
 }
 
 // Direction :
@@ -73,7 +103,10 @@ func Columns(names ...string) []Col {
 	return columns
 }
 
-// Column :
+var prefixRegexp = regexp.MustCompile(`^(.+?)\s*\((\d+)\)$`)
+
+// Column : create column with name, prefix with `-` for descending order and suffix
+// with `(n)` for prefix length, eg. `-Name(20)`
 func Column(name string) Col {
 	dir := Ascending
 	name = strings.TrimSpace(name)
@@ -81,16 +114,37 @@ func Column(name string) Col {
 		name = name[1:]
 		dir = Descending
 	}
-	return Col{
+	col := Col{
 		Name:      name,
 		Direction: dir,
 	}
+	if paths := prefixRegexp.FindStringSubmatch(name); paths != nil {
+		col.Name = paths[1]
+		length, _ := strconv.ParseUint(paths[2], 10, 64)
+		col.Length = uint(length)
+	}
+	return col
+}
+
+// Expr : create functional key part with expression, eg. `LOWER(Email)`
+func Expr(expr string, dir ...Direction) Col {
+	col := Col{Expr: strings.TrimSpace(expr)}
+	if len(dir) > 0 {
+		col.Direction = dir[0]
+	}
+	return col
 }
 
 // Col :
 type Col struct {
 	Name      string
 	Direction Direction
+
+	// Length is the prefix length of the column, only applicable for string and binary column
+	Length uint
+
+	// Expr is the expression of functional key part, `Name` will be ignored if it's not empty
+	Expr string
 }
 
 // GetName :
@@ -124,7 +178,14 @@ func (idx Index) buildName(w writer) {
 		if i > 0 {
 			w.WriteByte(';')
 		}
-		w.WriteString(col.Name)
+		if col.Expr != "" {
+			w.WriteString("(" + col.Expr + ")")
+		} else {
+			w.WriteString(col.Name)
+		}
+		if col.Length > 0 {
+			w.WriteString("(" + strconv.FormatUint(uint64(col.Length), 10) + ")")
+		}
 		w.WriteByte('@')
 		if col.Direction == 0 {
 			w.WriteString("ASC")
@@ -18,3 +18,17 @@ func TestIndex(t *testing.T) {
 	require.Equal(t, `587bc84ba16ffe5618f4864bcea6c9a6`, idx.GetName())
 	require.Equal(t, `587bc84ba16ffe5618f4864bcea6c9a6`, idx.HashName())
 }
+
+func TestColumn(t *testing.T) {
+	require.Equal(t, Col{Name: "Name"}, Column("Name"))
+	require.Equal(t, Col{Name: "Name", Direction: Descending}, Column("-Name"))
+	require.Equal(t, Col{Name: "Name", Length: 20}, Column("Name(20)"))
+	require.Equal(t, Col{Name: "Name", Direction: Descending, Length: 20}, Column(" -Name (20) "))
+	require.Equal(t, Col{Expr: "LOWER(`Email`)"}, Expr("LOWER(`Email`)"))
+	require.Equal(t, Col{Expr: "LOWER(`Email`)", Direction: Descending}, Expr("LOWER(`Email`)", Descending))
+
+	// name will be different when prefix length or expression present
+	idx := Index{Columns: Columns("a", "b2")}
+	require.NotEqual(t, idx.HashName(), Index{Columns: Columns("a(10)", "b2")}.HashName())
+	require.NotEqual(t, idx.HashName(), Index{Columns: []Col{Expr("a"), Column("b2")}}.HashName())
+}
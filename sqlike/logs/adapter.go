@@ -0,0 +1,61 @@
+package logs
+
+import "context"
+
+// SlogLogger : is the logger which follow the api of `log/slog`, *slog.Logger satisfy this interface
+type SlogLogger interface {
+	DebugContext(ctx context.Context, msg string, args ...interface{})
+	InfoContext(ctx context.Context, msg string, args ...interface{})
+	WarnContext(ctx context.Context, msg string, args ...interface{})
+	ErrorContext(ctx context.Context, msg string, args ...interface{})
+}
+
+// ZapLogger : is the logger which follow the api of zap, *zap.SugaredLogger satisfy this interface
+type ZapLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+// SlogHandler : adapt a slog-style logger into Handler
+func SlogHandler(logger SlogLogger) Handler {
+	return HandlerFunc(func(ctx context.Context, level Level, msg string, fields []Field) {
+		args := keysAndValues(fields)
+		switch level {
+		case InfoLevel:
+			logger.InfoContext(ctx, msg, args...)
+		case WarnLevel:
+			logger.WarnContext(ctx, msg, args...)
+		case ErrorLevel:
+			logger.ErrorContext(ctx, msg, args...)
+		default:
+			logger.DebugContext(ctx, msg, args...)
+		}
+	})
+}
+
+// ZapHandler : adapt a zap-style sugared logger into Handler
+func ZapHandler(logger ZapLogger) Handler {
+	return HandlerFunc(func(ctx context.Context, level Level, msg string, fields []Field) {
+		args := keysAndValues(fields)
+		switch level {
+		case InfoLevel:
+			logger.Infow(msg, args...)
+		case WarnLevel:
+			logger.Warnw(msg, args...)
+		case ErrorLevel:
+			logger.Errorw(msg, args...)
+		default:
+			logger.Debugw(msg, args...)
+		}
+	})
+}
+
+func keysAndValues(fields []Field) []interface{} {
+	args := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		args = append(args, f.Key, f.Value)
+	}
+	return args
+}
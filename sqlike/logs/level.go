@@ -0,0 +1,41 @@
+package logs
+
+import "strings"
+
+// Level :
+type Level int
+
+// levels :
+const (
+	DebugLevel Level = iota
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+)
+
+func (l Level) String() string {
+	switch l {
+	case InfoLevel:
+		return "INFO"
+	case WarnLevel:
+		return "WARN"
+	case ErrorLevel:
+		return "ERROR"
+	default:
+		return "DEBUG"
+	}
+}
+
+// ParseLevel :
+func ParseLevel(name string) Level {
+	switch strings.TrimSpace(strings.ToLower(name)) {
+	case "info":
+		return InfoLevel
+	case "warn", "warning":
+		return WarnLevel
+	case "error":
+		return ErrorLevel
+	default:
+		return DebugLevel
+	}
+}
@@ -0,0 +1,160 @@
+package logs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+)
+
+// Field : is a key value pair of structured log
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Handler : is where the structured log goes to, see `SlogHandler` and `ZapHandler`
+type Handler interface {
+	Handle(ctx context.Context, level Level, msg string, fields []Field)
+}
+
+// HandlerFunc :
+type HandlerFunc func(ctx context.Context, level Level, msg string, fields []Field)
+
+// Handle :
+func (f HandlerFunc) Handle(ctx context.Context, level Level, msg string, fields []Field) {
+	f(ctx, level, msg, fields)
+}
+
+// Options :
+type Options struct {
+	// Level is the minimum level to log, default is `DebugLevel`
+	Level Level
+
+	// SlowThreshold is the duration which the statement considered slow, it will be logged on
+	// `WarnLevel` even the debug option is off, zero value will disable it
+	SlowThreshold time.Duration
+
+	// RedactColumns is the column name which the argument value should be redacted
+	RedactColumns []string
+
+	// Redaction is the replacement of the redacted argument, default is `[REDACTED]`
+	Redaction string
+}
+
+// Option :
+type Option func(*Options)
+
+// WithLevel :
+func WithLevel(level Level) Option {
+	return func(opt *Options) {
+		opt.Level = level
+	}
+}
+
+// WithSlowThreshold :
+func WithSlowThreshold(d time.Duration) Option {
+	return func(opt *Options) {
+		opt.SlowThreshold = d
+	}
+}
+
+// WithRedactColumns :
+func WithRedactColumns(columns ...string) Option {
+	return func(opt *Options) {
+		opt.RedactColumns = append(opt.RedactColumns, columns...)
+	}
+}
+
+// WithRedaction :
+func WithRedaction(replacement string) Option {
+	return func(opt *Options) {
+		opt.Redaction = replacement
+	}
+}
+
+// SQLLogger : is a leveled and structured StatementLogger, it logs
+// 1. failed statement on `ErrorLevel`
+// 2. slow statement on `WarnLevel`
+// 3. every statement on `DebugLevel` when the debug option is on
+type SQLLogger struct {
+	opts    Options
+	handler Handler
+	redact  map[string]struct{}
+}
+
+var _ StatementLogger = (*SQLLogger)(nil)
+
+// New :
+func New(handler Handler, opts ...Option) *SQLLogger {
+	if handler == nil {
+		panic("sqlike: logger handler cannot be nil")
+	}
+	l := new(SQLLogger)
+	l.handler = handler
+	l.opts.Redaction = "[REDACTED]"
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+	l.redact = make(map[string]struct{}, len(l.opts.RedactColumns))
+	for _, col := range l.opts.RedactColumns {
+		l.redact[col] = struct{}{}
+	}
+	return l
+}
+
+// Debug :
+func (l *SQLLogger) Debug(stmt *sqlstmt.Statement) {
+	l.LogStatement(context.Background(), stmt, nil, true)
+}
+
+// LogStatement :
+func (l *SQLLogger) LogStatement(ctx context.Context, stmt *sqlstmt.Statement, err error, debug bool) {
+	var (
+		level   Level
+		msg     string
+		elapsed = stmt.TimeElapsed()
+	)
+	switch {
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		level, msg = ErrorLevel, "sqlike: statement failed"
+	case l.opts.SlowThreshold > 0 && elapsed >= l.opts.SlowThreshold:
+		level, msg = WarnLevel, "sqlike: slow statement"
+	case debug:
+		level, msg = DebugLevel, "sqlike: statement executed"
+	default:
+		return
+	}
+	if level < l.opts.Level {
+		return
+	}
+
+	fields := []Field{
+		{Key: "sql", Value: stmt.String()},
+		{Key: "args", Value: l.redactArgs(stmt.String(), stmt.Args())},
+		{Key: "elapsed", Value: elapsed},
+	}
+	if level == ErrorLevel {
+		fields = append(fields, Field{Key: "error", Value: err})
+	}
+	l.handler.Handle(ctx, level, msg, fields)
+}
+
+func (l *SQLLogger) redactArgs(query string, args []interface{}) []interface{} {
+	values := make([]interface{}, len(args))
+	copy(values, args)
+	if len(l.redact) == 0 {
+		return values
+	}
+	for i, col := range argColumns(query) {
+		if i >= len(values) {
+			break
+		}
+		if _, ok := l.redact[col]; ok {
+			values[i] = l.opts.Redaction
+		}
+	}
+	return values
+}
@@ -1,6 +1,8 @@
 package logs
 
 import (
+	"context"
+
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
 )
 
@@ -8,3 +10,46 @@ import (
 type Logger interface {
 	Debug(stmt *sqlstmt.Statement)
 }
+
+// StatementLogger : is a logger which able to decide by itself whether the statement should be logged,
+// it will receive every statement even when the debug option is off, so it can log slow query and error
+type StatementLogger interface {
+	Logger
+	LogStatement(ctx context.Context, stmt *sqlstmt.Statement, err error, debug bool)
+}
+
+// quietLogger : is a StatementLogger which the debug option is off
+type quietLogger struct {
+	StatementLogger
+}
+
+// Debug : debug is off, so it only log when the statement is slow or failed
+func (l quietLogger) Debug(stmt *sqlstmt.Statement) {
+	l.StatementLogger.LogStatement(context.Background(), stmt, nil, false)
+}
+
+// Quiet : turn off the debug log of the logger, it will return nil if the logger
+// is not a StatementLogger, because there is nothing to log
+func Quiet(logger Logger) Logger {
+	switch v := logger.(type) {
+	case quietLogger:
+		return v
+	case StatementLogger:
+		return quietLogger{v}
+	default:
+		return nil
+	}
+}
+
+// Log : log the executed statement with the result error
+func Log(ctx context.Context, logger Logger, stmt *sqlstmt.Statement, err error) {
+	switch v := logger.(type) {
+	case nil:
+	case quietLogger:
+		v.StatementLogger.LogStatement(ctx, stmt, err, false)
+	case StatementLogger:
+		v.LogStatement(ctx, stmt, err, true)
+	default:
+		v.Debug(stmt)
+	}
+}
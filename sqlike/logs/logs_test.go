@@ -0,0 +1,112 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgColumns(t *testing.T) {
+	require.Equal(t, []string{"Name", "Password", "Name", "Password"}, argColumns(
+		"INSERT INTO `db`.`User` (`Name`,`Password`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `Name`=VALUES(`Name`);",
+	))
+	require.Equal(t, []string{"Point", "Name"}, argColumns(
+		"INSERT INTO `db`.`Geo` (`Point`,`Name`) VALUES (ST_PointFromText(?,4326),?);",
+	))
+	require.Equal(t, []string{"Password", "ID", "ID", "Age", "Age", ""}, argColumns(
+		"UPDATE `db`.`User` SET `Password` = ? WHERE (`ID` IN (?,?) AND `Age` BETWEEN ? AND ?) LIMIT ?;",
+	))
+	require.Equal(t, []string{"Name"}, argColumns(
+		"SELECT * FROM `db`.`User` WHERE `Name` = ? AND `Remark` = 'what?';",
+	))
+}
+
+type recorder struct {
+	level  Level
+	msg    string
+	fields []Field
+	count  int
+}
+
+func (r *recorder) Handle(ctx context.Context, level Level, msg string, fields []Field) {
+	r.level, r.msg, r.fields = level, msg, fields
+	r.count++
+}
+
+func (r *recorder) field(key string) interface{} {
+	for _, f := range r.fields {
+		if f.Key == key {
+			return f.Value
+		}
+	}
+	return nil
+}
+
+func TestSQLLogger(t *testing.T) {
+	var (
+		ctx = context.Background()
+		rec = new(recorder)
+	)
+
+	logger := New(rec, WithSlowThreshold(time.Second), WithRedactColumns("Password"))
+	stmt := sqlstmt.NewStatement(nil)
+	stmt.WriteString("UPDATE `db`.`User` SET `Password` = ? WHERE `ID` = ?;")
+	stmt.AppendArgs("secret", int64(1))
+
+	// debug is off, fast and success statement should be skipped
+	Log(ctx, Quiet(logger), stmt, nil)
+	require.Equal(t, 0, rec.count)
+
+	Log(ctx, logger, stmt, nil)
+	require.Equal(t, 1, rec.count)
+	require.Equal(t, DebugLevel, rec.level)
+	require.Equal(t, []interface{}{"[REDACTED]", int64(1)}, rec.field("args"))
+	require.Equal(t, []interface{}{"secret", int64(1)}, stmt.Args())
+
+	err := errors.New("deadlock")
+	Log(ctx, Quiet(logger), stmt, err)
+	require.Equal(t, 2, rec.count)
+	require.Equal(t, ErrorLevel, rec.level)
+	require.Equal(t, err, rec.field("error"))
+	require.Equal(t, stmt.String(), rec.field("sql"))
+
+	stmt.StartTimer()
+	time.Sleep(5 * time.Millisecond)
+	stmt.StopTimer()
+	logger.opts.SlowThreshold = time.Millisecond
+	Log(ctx, Quiet(logger), stmt, nil)
+	require.Equal(t, 3, rec.count)
+	require.Equal(t, WarnLevel, rec.level)
+
+	logger.opts.Level = ErrorLevel
+	Log(ctx, logger, stmt, nil)
+	require.Equal(t, 3, rec.count)
+
+	// plain logger is not able to log when debug is off
+	require.Nil(t, Quiet(plainLogger{}))
+}
+
+type plainLogger struct{}
+
+func (plainLogger) Debug(stmt *sqlstmt.Statement) {}
+
+type zapLogger struct {
+	method string
+	args   []interface{}
+}
+
+func (z *zapLogger) Debugw(msg string, kv ...interface{}) { z.method, z.args = "debug", kv }
+func (z *zapLogger) Infow(msg string, kv ...interface{})  { z.method, z.args = "info", kv }
+func (z *zapLogger) Warnw(msg string, kv ...interface{})  { z.method, z.args = "warn", kv }
+func (z *zapLogger) Errorw(msg string, kv ...interface{}) { z.method, z.args = "error", kv }
+
+func TestZapHandler(t *testing.T) {
+	z := new(zapLogger)
+	ZapHandler(z).Handle(context.Background(), WarnLevel, "slow", []Field{{Key: "sql", Value: "SELECT 1;"}})
+	require.Equal(t, "warn", z.method)
+	require.Equal(t, []interface{}{"sql", "SELECT 1;"}, z.args)
+}
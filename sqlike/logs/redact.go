@@ -0,0 +1,100 @@
+package logs
+
+import "strings"
+
+// argColumns : map every placeholder `?` of the query to the column it belongs to, the result
+// is best effort, it recognise `col` = ?, `col` IN (?,?) and INSERT INTO (`col`,...) VALUES (?,...)
+func argColumns(query string) []string {
+	var (
+		columns   = make([]string, 0)
+		insert    []string
+		ident     string
+		list      []string
+		inValues  bool
+		seen      bool
+		depth     int
+		pos       int
+		lastIdent string
+	)
+
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// skip the string literal
+			for i++; i < len(query) && query[i] != c; i++ {
+				if query[i] == '\\' {
+					i++
+				}
+			}
+
+		case c == '`':
+			end := strings.IndexByte(query[i+1:], '`')
+			if end < 0 {
+				return columns
+			}
+			ident = query[i+1 : i+1+end]
+			lastIdent = ident
+			if depth == 1 && !inValues {
+				list = append(list, ident)
+			}
+			i += end + 1
+
+		case c == '(':
+			depth++
+			if depth == 1 {
+				pos = 0
+				if !inValues {
+					list = list[:0]
+				}
+			}
+
+		case c == ')':
+			depth--
+			if depth == 0 && !inValues && len(list) > 0 {
+				insert = append(insert[:0], list...)
+			}
+
+		case c == ',':
+			if depth == 1 {
+				pos++
+			}
+
+		case c == '?':
+			if inValues && depth > 0 {
+				if pos < len(insert) {
+					columns = append(columns, insert[pos])
+				} else {
+					columns = append(columns, "")
+				}
+				continue
+			}
+			columns = append(columns, lastIdent)
+
+		case isWordChar(c):
+			j := i
+			for j < len(query) && isWordChar(query[j]) {
+				j++
+			}
+			switch strings.ToUpper(query[i:j]) {
+			case "VALUES":
+				// `VALUES(col)` on duplicate key is a function, not the values clause
+				if depth == 0 && !seen && len(insert) > 0 {
+					inValues, seen = true, true
+				}
+			case "ON", "SELECT", "WHERE":
+				if depth == 0 {
+					inValues = false
+				}
+			case "LIMIT", "OFFSET":
+				lastIdent = ""
+			}
+			i = j - 1
+		}
+	}
+	return columns
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
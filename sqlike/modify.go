@@ -6,6 +6,7 @@ import (
 	"reflect"
 
 	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sql/codec"
 	sqldialect "github.com/Oskang09/sqlike/sql/dialect"
 	sqldriver "github.com/Oskang09/sqlike/sql/driver"
 	"github.com/Oskang09/sqlike/sql/expr"
@@ -23,6 +24,7 @@ func (tb *Table) ModifyOne(ctx context.Context, update interface{}, opts ...*opt
 		tb.name,
 		tb.pk,
 		tb.client.cache,
+		tb.codec,
 		tb.dialect,
 		tb.driver,
 		tb.logger,
@@ -31,7 +33,7 @@ func (tb *Table) ModifyOne(ctx context.Context, update interface{}, opts ...*opt
 	)
 }
 
-func modifyOne(ctx context.Context, dbName, tbName, pk string, cache reflext.StructMapper, dialect sqldialect.Dialect, driver sqldriver.Driver, logger logs.Logger, update interface{}, opts []*options.ModifyOneOptions) error {
+func modifyOne(ctx context.Context, dbName, tbName, pk string, cache reflext.StructMapper, cdc codec.Codecer, dialect sqldialect.Dialect, driver sqldriver.Driver, logger logs.Logger, update interface{}, opts []*options.ModifyOneOptions) error {
 	v := reflext.ValueOf(update)
 	if !v.IsValid() {
 		return ErrInvalidInput
@@ -46,13 +48,12 @@ func modifyOne(ctx context.Context, dbName, tbName, pk string, cache reflext.Str
 		return ErrNilEntity
 	}
 
-	cdc := cache.CodecByType(t)
 	opt := new(options.ModifyOneOptions)
 	if len(opts) > 0 && opts[0] != nil {
 		opt = opts[0]
 	}
 
-	fields := skipColumns(cdc.Properties(), opt.Omits)
+	fields := skipColumns(cache.CodecByType(t).Properties(), opt.Omits)
 	x := new(actions.UpdateActions)
 	x.Table = tbName
 
@@ -72,6 +73,16 @@ func modifyOne(ctx context.Context, dbName, tbName, pk string, cache reflext.Str
 			pkv[1] = fv.Interface()
 			continue
 		}
+		if _, ok := sf.Tag().LookUp("json_schema"); ok {
+			// the value will be encoded without struct field, so validate the json document here
+			encoder, err := cdc.LookupEncoder(fv)
+			if err != nil {
+				return err
+			}
+			if _, err := encoder(sf, fv); err != nil {
+				return err
+			}
+		}
 		x.Set(expr.ColumnValue(sf.Name(), fv.Interface()))
 	}
 
@@ -0,0 +1,29 @@
+package options
+
+// Diff :
+func Diff() *DiffOptions {
+	return &DiffOptions{}
+}
+
+// DiffOptions :
+type DiffOptions struct {
+	// Tables is the table name patterns which should be compared, all tables will be compared if it's empty,
+	// the syntax of the pattern is same as `path.Match`
+	Tables []string
+
+	// DropExtras will generate the `DROP` statement for the tables, columns and indexes which only exist on
+	// the target database, otherwise the statement will be commented out in the script
+	DropExtras bool
+}
+
+// SetTables :
+func (opts *DiffOptions) SetTables(patterns ...string) *DiffOptions {
+	opts.Tables = append(opts.Tables, patterns...)
+	return opts
+}
+
+// SetDropExtras :
+func (opts *DiffOptions) SetDropExtras(drop bool) *DiffOptions {
+	opts.DropExtras = drop
+	return opts
+}
@@ -0,0 +1,47 @@
+package options
+
+import "io"
+
+// SyncIndexes :
+func SyncIndexes() *SyncIndexesOptions {
+	return &SyncIndexesOptions{}
+}
+
+// SyncIndexesOptions :
+type SyncIndexesOptions struct {
+	// DropOrphans will drop the indexes which are not declared
+	DropOrphans bool
+
+	// Allowlist is the index name patterns which will never be dropped, the syntax of the pattern is same as `path.Match`
+	Allowlist []string
+
+	// PlanOnly will only print the DDL without executing it
+	PlanOnly bool
+
+	// Output is where the DDL will be printed, default is `os.Stdout` on plan only mode
+	Output io.Writer
+}
+
+// SetDropOrphans :
+func (opts *SyncIndexesOptions) SetDropOrphans(drop bool) *SyncIndexesOptions {
+	opts.DropOrphans = drop
+	return opts
+}
+
+// SetAllowlist :
+func (opts *SyncIndexesOptions) SetAllowlist(patterns ...string) *SyncIndexesOptions {
+	opts.Allowlist = append(opts.Allowlist, patterns...)
+	return opts
+}
+
+// SetPlanOnly :
+func (opts *SyncIndexesOptions) SetPlanOnly(planOnly bool) *SyncIndexesOptions {
+	opts.PlanOnly = planOnly
+	return opts
+}
+
+// SetOutput :
+func (opts *SyncIndexesOptions) SetOutput(w io.Writer) *SyncIndexesOptions {
+	opts.Output = w
+	return opts
+}
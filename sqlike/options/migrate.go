@@ -0,0 +1,41 @@
+package options
+
+import "github.com/Oskang09/sqlike/sqlike/ddl"
+
+// Migrate :
+func Migrate() *MigrateOptions {
+	return &MigrateOptions{}
+}
+
+// MigrateOptions :
+type MigrateOptions struct {
+	// ConvertCharset will convert the character set and collation of the existing columns to the
+	// `charset` and `collate` tag, the column data will be rewritten, so it's disabled by default
+	ConvertCharset bool
+
+	// Algorithm will append `ALGORITHM` (and `LOCK=NONE` for `INPLACE`) to the `ALTER TABLE` statement,
+	// the migration will be refused if any operation requires a more expensive algorithm
+	Algorithm ddl.Algorithm
+
+	// AllowDestructive will allow the operations which may lose data, such as narrowing the data type,
+	// dropping columns is always allowed on unsafe migration
+	AllowDestructive bool
+}
+
+// SetConvertCharset :
+func (opts *MigrateOptions) SetConvertCharset(convert bool) *MigrateOptions {
+	opts.ConvertCharset = convert
+	return opts
+}
+
+// SetAlgorithm :
+func (opts *MigrateOptions) SetAlgorithm(algorithm ddl.Algorithm) *MigrateOptions {
+	opts.Algorithm = algorithm
+	return opts
+}
+
+// SetAllowDestructive :
+func (opts *MigrateOptions) SetAllowDestructive(allow bool) *MigrateOptions {
+	opts.AllowDestructive = allow
+	return opts
+}
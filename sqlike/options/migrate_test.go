@@ -0,0 +1,27 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/Oskang09/sqlike/sqlike/ddl"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateOptions(t *testing.T) {
+	opt := Migrate()
+	require.False(t, opt.ConvertCharset)
+	require.Equal(t, ddl.DefaultAlgorithm, opt.Algorithm)
+	require.False(t, opt.AllowDestructive)
+
+	opt.SetConvertCharset(true)
+	require.True(t, opt.ConvertCharset)
+
+	opt.SetConvertCharset(false)
+	require.False(t, opt.ConvertCharset)
+
+	opt.SetAlgorithm(ddl.Inplace)
+	require.Equal(t, ddl.Inplace, opt.Algorithm)
+
+	opt.SetAllowDestructive(true)
+	require.True(t, opt.AllowDestructive)
+}
@@ -17,6 +17,20 @@ const (
 	JSON_REPLACE
 	JSON_REMOVE
 	MEMBER_OF
+	JSON_MERGE_PATCH
+	JSON_MERGE_PRESERVE
+	JSON_ARRAY_APPEND
+	JSON_ARRAY_INSERT
+	JSON_LENGTH
+	JSON_DEPTH
+	JSON_SEARCH
+	JSON_CONTAINS_PATH
+	JSON_OVERLAPS
+	JSON_OBJECT
+	JSON_ARRAY
+	JSON_ARRAYAGG
+	JSON_OBJECTAGG
+	JSON_TABLE
 )
 
 var jsonFuncNames = [...]string{
@@ -33,12 +47,52 @@ var jsonFuncNames = [...]string{
 	"JSON_REPLACE",
 	"JSON_REMOVE",
 	"MEMBER OF",
+	"JSON_MERGE_PATCH",
+	"JSON_MERGE_PRESERVE",
+	"JSON_ARRAY_APPEND",
+	"JSON_ARRAY_INSERT",
+	"JSON_LENGTH",
+	"JSON_DEPTH",
+	"JSON_SEARCH",
+	"JSON_CONTAINS_PATH",
+	"JSON_OVERLAPS",
+	"JSON_OBJECT",
+	"JSON_ARRAY",
+	"JSON_ARRAYAGG",
+	"JSON_OBJECTAGG",
+	"JSON_TABLE",
 }
 
 func (f jsonFunction) String() string {
 	id := int(f) - 1
-	if id > len(jsonFuncNames) {
+	if id < 0 || id >= len(jsonFuncNames) {
 		return "Unknown JSON Function"
 	}
 	return jsonFuncNames[id]
 }
+
+// JSONTableColumn : the column definition of `JSON_TABLE`
+type JSONTableColumn struct {
+	Name     string
+	DataType string
+	Path     string
+
+	// EXISTS PATH, the column will be 1 if the path exists, otherwise 0
+	Exists bool
+
+	// FOR ORDINALITY, the column is the row counter
+	Ordinality bool
+
+	// the behaviour on empty or error, eg. NULL, ERROR, DEFAULT '0'
+	OnEmpty string
+	OnError string
+
+	// NESTED PATH, the columns of the nested path, the nested path is `Path`
+	Nested []JSONTableColumn
+}
+
+// JSONTable : the `COLUMNS` clause and alias of `JSON_TABLE`
+type JSONTable struct {
+	Columns []JSONTableColumn
+	Alias   string
+}
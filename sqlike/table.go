@@ -13,7 +13,11 @@ import (
 	"github.com/Oskang09/sqlike/sql/dialect"
 	sqldriver "github.com/Oskang09/sqlike/sql/driver"
 	sqlstmt "github.com/Oskang09/sqlike/sql/stmt"
+	sqlcolumns "github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/Oskang09/sqlike/sqlike/ddl"
+	"github.com/Oskang09/sqlike/sqlike/indexes"
 	"github.com/Oskang09/sqlike/sqlike/logs"
+	"github.com/Oskang09/sqlike/sqlike/options"
 )
 
 // ErrNoRecordAffected :
@@ -49,6 +53,49 @@ type Table struct {
 	logger logs.Logger
 }
 
+// bindTransaction : return the table which operates within the transaction, the database name is kept
+func (tb *Table) bindTransaction(tx *Transaction) *Table {
+	return &Table{
+		dbName:  tb.dbName,
+		name:    tb.name,
+		pk:      tb.pk,
+		client:  tb.client,
+		driver:  tx.driver,
+		dialect: tb.dialect,
+		codec:   tb.codec,
+		logger:  tb.logger,
+	}
+}
+
+// Bind : return the table which operates within the transaction of the session, the database name
+// is kept, so the tables of the different databases on the same client can be operated in a transaction
+func (tb *Table) Bind(sess SessionContext) (*Table, error) {
+	tx, ok := sess.(*Transaction)
+	if !ok {
+		return nil, errors.New("sqlike: session is not a transaction")
+	}
+	if tx.client != tb.client {
+		return nil, errors.New("sqlike: the tables of transaction should be on the same client")
+	}
+	return tb.bindTransaction(tx), nil
+}
+
+// RunInTransaction : run the callback in a new transaction on the database of the table, use `Bind`
+// to operate the tables within the transaction
+func (tb *Table) RunInTransaction(ctx context.Context, cb txCallback, opts ...*options.TransactionOptions) error {
+	db := &Database{
+		driverName: tb.client.driverName,
+		name:       tb.dbName,
+		pk:         tb.pk,
+		client:     tb.client,
+		dialect:    tb.dialect,
+		driver:     tb.client.DB,
+		logger:     tb.logger,
+		codec:      tb.codec,
+	}
+	return db.RunInTransaction(ctx, cb, opts...)
+}
+
 // Rename : rename the current table name to new table name
 func (tb *Table) Rename(ctx context.Context, name string) error {
 	stmt := sqlstmt.AcquireStmt(tb.dialect)
@@ -89,7 +136,7 @@ func (tb *Table) Columns() *ColumnView {
 func (tb *Table) ListColumns(ctx context.Context) ([]Column, error) {
 	stmt := sqlstmt.AcquireStmt(tb.dialect)
 	defer sqlstmt.ReleaseStmt(stmt)
-	tb.dialect.GetColumns(stmt, tb.dbName, tb.name)
+	tb.dialect.GetColumns(stmt, tb.client.DriverInfo, tb.dbName, tb.name)
 	rows, err := sqldriver.Query(
 		ctx,
 		tb.driver,
@@ -101,11 +148,15 @@ func (tb *Table) ListColumns(ctx context.Context) ([]Column, error) {
 	}
 	defer rows.Close()
 
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
 	columns := make([]Column, 0)
 	for i := 0; rows.Next(); i++ {
 		col := Column{}
-
-		if err := rows.Scan(
+		dest := []interface{}{
 			&col.Position,
 			&col.Name,
 			&col.Type,
@@ -116,7 +167,11 @@ func (tb *Table) ListColumns(ctx context.Context) ([]Column, error) {
 			&col.Collation,
 			&col.Comment,
 			&col.Extra,
-		); err != nil {
+			&col.Expression,
+			&col.SRID,
+		}
+		// `SRS_ID` is not selected before 8.0
+		if err := rows.Scan(dest[:len(names)]...); err != nil {
 			return nil, err
 		}
 
@@ -161,31 +216,44 @@ func (tb *Table) ListIndexes(ctx context.Context) ([]Index, error) {
 }
 
 // MustMigrate : this will ensure the migrate is complete, otherwise it will panic
-func (tb Table) MustMigrate(ctx context.Context, entity interface{}) {
-	err := tb.Migrate(ctx, entity)
+func (tb Table) MustMigrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) {
+	err := tb.Migrate(ctx, entity, opts...)
 	if err != nil {
 		panic(err)
 	}
 }
 
 // Migrate : migrate will create a new table follows by the definition of struct tag, alter when the table already exists
-func (tb *Table) Migrate(ctx context.Context, entity interface{}) error {
-	return tb.migrateOne(ctx, tb.client.cache, entity, false)
+func (tb *Table) Migrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) error {
+	_, err := tb.migrateOne(ctx, tb.client.cache, entity, false, opts, false)
+	return err
 }
 
 // UnsafeMigrate : unsafe migration will delete non-exist index and columns, beware when you use this
-func (tb *Table) UnsafeMigrate(ctx context.Context, entity interface{}) error {
-	return tb.migrateOne(ctx, tb.client.cache, entity, true)
+func (tb *Table) UnsafeMigrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) error {
+	_, err := tb.migrateOne(ctx, tb.client.cache, entity, true, opts, false)
+	return err
 }
 
 // MustUnsafeMigrate : this will panic if it get error on unsafe migrate
-func (tb *Table) MustUnsafeMigrate(ctx context.Context, entity interface{}) {
-	err := tb.migrateOne(ctx, tb.client.cache, entity, true)
+func (tb *Table) MustUnsafeMigrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) {
+	_, err := tb.migrateOne(ctx, tb.client.cache, entity, true, opts, false)
 	if err != nil {
 		panic(err)
 	}
 }
 
+// PlanMigrate : return the migration plan without executing it, the plan consists of the DDL statement, and the
+// algorithm, lock and destructiveness of each operation, including the indexes declared by the entity
+func (tb *Table) PlanMigrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) (*ddl.Plan, error) {
+	return tb.migrateOne(ctx, tb.client.cache, entity, false, opts, true)
+}
+
+// PlanUnsafeMigrate : same as `PlanMigrate`, but for unsafe migration
+func (tb *Table) PlanUnsafeMigrate(ctx context.Context, entity interface{}, opts ...*options.MigrateOptions) (*ddl.Plan, error) {
+	return tb.migrateOne(ctx, tb.client.cache, entity, true, opts, true)
+}
+
 // Truncate : delete all the table data.
 func (tb *Table) Truncate(ctx context.Context) (err error) {
 	stmt := sqlstmt.AcquireStmt(tb.dialect)
@@ -271,39 +339,80 @@ func (tb *Table) HasIndexByName(ctx context.Context, name string) (bool, error)
 	)
 }
 
-func (tb *Table) migrateOne(ctx context.Context, cache reflext.StructMapper, entity interface{}, unsafe bool) error {
+func (tb *Table) migrateOne(ctx context.Context, cache reflext.StructMapper, entity interface{}, unsafe bool, opts []*options.MigrateOptions, dryRun bool) (*ddl.Plan, error) {
+	opt := new(options.MigrateOptions)
+	if len(opts) > 0 && opts[0] != nil {
+		opt = opts[0]
+	}
+
 	v := reflext.ValueOf(entity)
 	if !v.IsValid() {
-		return ErrInvalidInput
+		return nil, ErrInvalidInput
 	}
 
 	t := reflext.Deref(v.Type())
 	if !reflext.IsKind(t, reflect.Struct) {
-		return ErrExpectedStruct
+		return nil, ErrExpectedStruct
 	}
 
 	cdc := cache.CodecByType(t)
 	fields := skipColumns(cdc.Properties(), nil)
 	if len(fields) < 1 {
-		return ErrEmptyFields
+		return nil, ErrEmptyFields
+	}
+
+	// the indexes declared by the entity are created or recreated within the same statement
+	idxs := getEntityIndexes(entity, t)
+	for _, idx := range idxs {
+		if idx.Type != indexes.MultiValued && len(idx.Columns) < 1 {
+			return nil, ErrNoColumn
+		}
 	}
 
 	if !tb.Exists(ctx) {
-		return tb.createTable(ctx, fields)
+		return tb.createTable(ctx, fields, idxs, dryRun)
 	}
 
 	columns, err := tb.ListColumns(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	idxs, err := tb.ListIndexes(ctx)
+	existing, err := tb.ListIndexes(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	drops, adds := make([]string, 0), make([]indexes.Index, 0)
+	if len(idxs) > 0 {
+		details, err := tb.Indexes().ListDetails(ctx)
+		if err != nil {
+			return nil, err
+		}
+		drift := diffIndexes(tb.name, idxs, details, tb.Indexes().isSupportDesc(), nil)
+		adds = append(adds, drift.Added...)
+		for _, idx := range drift.Changed {
+			// primary key will never be altered
+			if idx.Type == indexes.Primary {
+				continue
+			}
+			drops = append(drops, idx.GetName())
+			adds = append(adds, idx)
+		}
 	}
-	return tb.alterTable(ctx, fields, columns, idxs, unsafe)
+	return tb.alterTable(ctx, fields, columns, existing, drops, adds, unsafe, opt, dryRun)
 }
 
-func (tb *Table) createTable(ctx context.Context, fields []reflext.StructFielder) error {
+func getEntityIndexes(entity interface{}, t reflect.Type) []indexes.Index {
+	if it, ok := entity.(Indexer); ok {
+		return it.Indexes()
+	}
+	// the method might declare with pointer receiver
+	if it, ok := reflect.New(t).Interface().(Indexer); ok {
+		return it.Indexes()
+	}
+	return nil
+}
+
+func (tb *Table) createTable(ctx context.Context, fields []reflext.StructFielder, idxs []indexes.Index, dryRun bool) (*ddl.Plan, error) {
 	stmt := sqlstmt.AcquireStmt(tb.dialect)
 	defer sqlstmt.ReleaseStmt(stmt)
 	if err := tb.dialect.CreateTable(
@@ -313,8 +422,17 @@ func (tb *Table) createTable(ctx context.Context, fields []reflext.StructFielder
 		tb.pk,
 		tb.client.DriverInfo,
 		fields,
+		idxs,
 	); err != nil {
-		return err
+		return nil, err
+	}
+	plan := &ddl.Plan{
+		Table:     tb.dialect.TableName(tb.dbName, tb.name),
+		Create:    true,
+		Statement: stmt.String(),
+	}
+	if dryRun {
+		return plan, nil
 	}
 	if _, err := sqldriver.Execute(
 		ctx,
@@ -322,15 +440,24 @@ func (tb *Table) createTable(ctx context.Context, fields []reflext.StructFielder
 		stmt,
 		tb.logger,
 	); err != nil {
-		return err
+		return plan, err
 	}
-	return nil
+	return plan, nil
 }
 
-func (tb *Table) alterTable(ctx context.Context, fields []reflext.StructFielder, columns []Column, indexs []Index, unsafe bool) error {
-	cols := make([]string, len(columns))
+func (tb *Table) alterTable(ctx context.Context, fields []reflext.StructFielder, columns []Column, indexs []Index, drops []string, adds []indexes.Index, unsafe bool, opt *options.MigrateOptions, dryRun bool) (*ddl.Plan, error) {
+	cols := make([]sqlcolumns.Column, len(columns))
 	for i, col := range columns {
-		cols[i] = col.Name
+		cols[i] = sqlcolumns.Column{
+			Name:         col.Name,
+			DataType:     col.DataType,
+			Type:         col.Type,
+			Nullable:     bool(col.IsNullable),
+			DefaultValue: col.DefaultValue,
+			Charset:      col.Charset,
+			Collation:    col.Collation,
+			Extra:        col.Extra,
+		}
 	}
 	idxs := make([]string, len(indexs))
 	for i, idx := range indexs {
@@ -346,16 +473,30 @@ func (tb *Table) alterTable(ctx context.Context, fields []reflext.StructFielder,
 		stmt,
 		tb.logger,
 	).Scan(&count); err != nil {
-		return err
+		return nil, err
+	}
+	checks, err := tb.listCheckConstraints(ctx)
+	if err != nil {
+		return nil, err
 	}
 	stmt.Reset()
-	if err := tb.dialect.AlterTable(
-		stmt,
-		tb.dbName, tb.name, tb.pk, count > 0,
-		tb.client.DriverInfo,
-		fields, cols, idxs, unsafe,
-	); err != nil {
-		return err
+	plan, err := tb.dialect.AlterTable(stmt, dialect.AlterTableInput{
+		DB:          tb.dbName,
+		Table:       tb.name,
+		PK:          tb.pk,
+		HasPK:       count > 0,
+		Info:        tb.client.DriverInfo,
+		Fields:      fields,
+		Columns:     cols,
+		Indexes:     idxs,
+		Checks:      checks,
+		DropIndexes: drops,
+		AddIndexes:  adds,
+		Unsafe:      unsafe,
+		Options:     opt,
+	})
+	if err != nil || dryRun {
+		return plan, err
 	}
 	if _, err := sqldriver.Execute(
 		ctx,
@@ -363,7 +504,49 @@ func (tb *Table) alterTable(ctx context.Context, fields []reflext.StructFielder,
 		stmt,
 		tb.logger,
 	); err != nil {
-		return err
+		return plan, err
 	}
-	return nil
+	return plan, nil
+}
+
+func (tb *Table) listCheckConstraints(ctx context.Context) ([]string, error) {
+	stmt := sqlstmt.AcquireStmt(tb.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	tb.dialect.GetCheckConstraints(stmt, tb.dbName, tb.name)
+	rows, err := sqldriver.Query(
+		ctx,
+		tb.driver,
+		stmt,
+		tb.logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+func (tb *Table) getTableOptions(ctx context.Context) (*tableOptions, error) {
+	stmt := sqlstmt.AcquireStmt(tb.dialect)
+	defer sqlstmt.ReleaseStmt(stmt)
+	tb.dialect.GetTableOptions(stmt, tb.dbName, tb.name)
+	opt := new(tableOptions)
+	if err := sqldriver.QueryRowContext(
+		ctx,
+		tb.driver,
+		stmt,
+		tb.logger,
+	).Scan(&opt.engine, &opt.charset, &opt.collation, &opt.comment); err != nil {
+		return nil, err
+	}
+	return opt, nil
 }
@@ -0,0 +1,405 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/Oskang09/sqlike/reflext"
+	sqldriver "github.com/Oskang09/sqlike/sql/driver"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+)
+
+const (
+	maxDecimalPrecision = 65
+	maxDecimalScale     = 30
+
+	// the exponent beyond `DECIMAL(65,30)` is rejected, so it won't be expanded into a huge number
+	maxDecimalExponent = maxDecimalPrecision + maxDecimalScale
+)
+
+var (
+	// ErrDivisionByZero :
+	ErrDivisionByZero = errors.New("types: decimal division by zero")
+
+	bigTen = big.NewInt(10)
+)
+
+// Decimal : arbitrary precision decimal number, which is `unscaled * 10^-scale`.
+// The zero value is 0, and every operation returns a new value.
+type Decimal struct {
+	unscaled *big.Int
+	scale    int32
+}
+
+var (
+	_ driver.Valuer            = Decimal{}
+	_ sql.Scanner              = (*Decimal)(nil)
+	_ fmt.Stringer             = Decimal{}
+	_ encoding.TextMarshaler   = Decimal{}
+	_ encoding.TextUnmarshaler = (*Decimal)(nil)
+	_ json.Marshaler           = Decimal{}
+	_ json.Unmarshaler         = (*Decimal)(nil)
+)
+
+// NewDecimal : eg. NewDecimal(12345, 2) is 123.45
+func NewDecimal(unscaled int64, scale int32) Decimal {
+	return NewDecimalFromBigInt(big.NewInt(unscaled), scale)
+}
+
+// NewDecimalFromBigInt : the negative scale is expanded, so it will panic if the scale is less than -95
+func NewDecimalFromBigInt(unscaled *big.Int, scale int32) Decimal {
+	if scale < -maxDecimalExponent {
+		panic("types: decimal scale should not be less than -95")
+	}
+	d := Decimal{unscaled: new(big.Int).Set(unscaled), scale: scale}
+	if scale < 0 {
+		d.unscaled.Mul(d.unscaled, pow10(-scale))
+		d.scale = 0
+	}
+	return d
+}
+
+// NewDecimalFromFloat : the shortest decimal representation of the float will be used,
+// eg. 0.1 is 0.1 instead of 0.1000000000000000055511151231257827
+func NewDecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %v", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// ParseDecimal : parse the decimal string, the exponent is supported, eg. `-12.34` or `1.5e-3`,
+// the exponent should be within -95 and 95, which is the range of `DECIMAL(65,30)`
+func ParseDecimal(str string) (Decimal, error) {
+	s := strings.TrimSpace(str)
+	exp := int64(0)
+	if i := strings.IndexAny(s, "eE"); i > -1 {
+		e, err := strconv.ParseInt(s[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("types: invalid decimal %q", str)
+		}
+		if e > maxDecimalExponent || e < -maxDecimalExponent {
+			return Decimal{}, fmt.Errorf("types: decimal exponent of %q is out of range", str)
+		}
+		exp = e
+		s = s[:i]
+	}
+
+	var scale int64
+	if i := strings.IndexByte(s, '.'); i > -1 {
+		scale = int64(len(s) - i - 1)
+		s = s[:i] + s[i+1:]
+	}
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || len(s)-len(digits) > 1 || strings.IndexFunc(digits, func(r rune) bool {
+		return r < '0' || r > '9'
+	}) > -1 {
+		return Decimal{}, fmt.Errorf("types: invalid decimal %q", str)
+	}
+
+	unscaled, _ := new(big.Int).SetString(s, 10)
+	scale -= exp
+	if scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("types: decimal %q is out of range", str)
+	}
+	return NewDecimalFromBigInt(unscaled, int32(scale)), nil
+}
+
+// MustParseDecimal :
+func MustParseDecimal(str string) Decimal {
+	d, err := ParseDecimal(str)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func pow10(n int32) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func (d Decimal) value() *big.Int {
+	if d.unscaled == nil {
+		return new(big.Int)
+	}
+	return d.unscaled
+}
+
+// rescale : only increase the scale, so the value won't be changed
+func (d Decimal) rescale(scale int32) *big.Int {
+	v := d.value()
+	if scale <= d.scale {
+		return new(big.Int).Set(v)
+	}
+	return new(big.Int).Mul(v, pow10(scale-d.scale))
+}
+
+func align(d1, d2 Decimal) (*big.Int, *big.Int, int32) {
+	scale := d1.scale
+	if d2.scale > scale {
+		scale = d2.scale
+	}
+	return d1.rescale(scale), d2.rescale(scale), scale
+}
+
+// Scale : the number of digits after the decimal point
+func (d Decimal) Scale() int32 {
+	return d.scale
+}
+
+// Unscaled :
+func (d Decimal) Unscaled() *big.Int {
+	return new(big.Int).Set(d.value())
+}
+
+// Add :
+func (d Decimal) Add(d2 Decimal) Decimal {
+	x, y, scale := align(d, d2)
+	return Decimal{unscaled: x.Add(x, y), scale: scale}
+}
+
+// Sub :
+func (d Decimal) Sub(d2 Decimal) Decimal {
+	x, y, scale := align(d, d2)
+	return Decimal{unscaled: x.Sub(x, y), scale: scale}
+}
+
+// Mul : the scale of the result is the sum of both scales
+func (d Decimal) Mul(d2 Decimal) Decimal {
+	return Decimal{unscaled: new(big.Int).Mul(d.value(), d2.value()), scale: d.scale + d2.scale}
+}
+
+// Div : the result is rounded half away from zero to the scale, same as mysql
+func (d Decimal) Div(d2 Decimal, scale int32) (Decimal, error) {
+	if d2.Sign() == 0 {
+		return Decimal{}, ErrDivisionByZero
+	}
+	if scale < 0 {
+		scale = 0
+	}
+	// d / d2 = (x * 10^(scale + d2.scale - d.scale)) / y * 10^-scale
+	num := new(big.Int).Set(d.value())
+	den := new(big.Int).Set(d2.value())
+	if shift := scale + d2.scale - d.scale; shift >= 0 {
+		num.Mul(num, pow10(shift))
+	} else {
+		den.Mul(den, pow10(-shift))
+	}
+	return Decimal{unscaled: quoRound(num, den), scale: scale}, nil
+}
+
+// quoRound : the quotient is rounded half away from zero
+func quoRound(num, den *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+	r.Abs(r).Lsh(r, 1)
+	if r.Cmp(new(big.Int).Abs(den)) >= 0 {
+		if num.Sign() == den.Sign() {
+			q.Add(q, big.NewInt(1))
+		} else {
+			q.Sub(q, big.NewInt(1))
+		}
+	}
+	return q
+}
+
+// Round : round half away from zero to the scale, eg. 1.245 => 1.25, -1.245 => -1.25
+func (d Decimal) Round(scale int32) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return Decimal{unscaled: d.rescale(scale), scale: scale}
+	}
+	return Decimal{unscaled: quoRound(d.value(), pow10(d.scale-scale)), scale: scale}
+}
+
+// Truncate : drop the digits after the scale without rounding
+func (d Decimal) Truncate(scale int32) Decimal {
+	if scale < 0 {
+		scale = 0
+	}
+	if scale >= d.scale {
+		return Decimal{unscaled: d.rescale(scale), scale: scale}
+	}
+	return Decimal{unscaled: new(big.Int).Quo(d.value(), pow10(d.scale-scale)), scale: scale}
+}
+
+// Neg :
+func (d Decimal) Neg() Decimal {
+	return Decimal{unscaled: new(big.Int).Neg(d.value()), scale: d.scale}
+}
+
+// Abs :
+func (d Decimal) Abs() Decimal {
+	return Decimal{unscaled: new(big.Int).Abs(d.value()), scale: d.scale}
+}
+
+// Sign : -1 if d < 0, 0 if d == 0 and +1 if d > 0
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// IsZero :
+func (d Decimal) IsZero() bool {
+	return d.Sign() == 0
+}
+
+// Cmp : -1 if d < d2, 0 if d == d2 and +1 if d > d2, the scale is not compared
+func (d Decimal) Cmp(d2 Decimal) int {
+	x, y, _ := align(d, d2)
+	return x.Cmp(y)
+}
+
+// Equal : the scale is not compared, eg. 1.50 is equal to 1.5
+func (d Decimal) Equal(d2 Decimal) bool {
+	return d.Cmp(d2) == 0
+}
+
+// Rat :
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.value(), pow10(d.scale))
+}
+
+// Float64 : the value may lose precision
+func (d Decimal) Float64() float64 {
+	f, _ := d.Rat().Float64()
+	return f
+}
+
+// String : the trailing zeros of the scale are kept, eg. 12.30
+func (d Decimal) String() string {
+	v := d.value()
+	str := new(big.Int).Abs(v).String()
+	if d.scale > 0 {
+		if n := int(d.scale) - len(str) + 1; n > 0 {
+			str = strings.Repeat("0", n) + str
+		}
+		i := len(str) - int(d.scale)
+		str = str[:i] + "." + str[i:]
+	}
+	if v.Sign() < 0 {
+		return "-" + str
+	}
+	return str
+}
+
+// DataType : the `precision` and `scale` tags are the precision and scale of `DECIMAL`,
+// the default is `DECIMAL(65,30)`
+func (d Decimal) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	tag := sf.Tag()
+	precision, scale := maxDecimalPrecision, maxDecimalScale
+	if v, ok := tag.LookUp("precision"); ok {
+		precision, _ = strconv.Atoi(v)
+		if _, ok := tag.LookUp("scale"); !ok {
+			scale = 0
+		}
+	}
+	if v, ok := tag.LookUp("scale"); ok {
+		scale, _ = strconv.Atoi(v)
+	}
+	if precision < 1 || precision > maxDecimalPrecision {
+		panic("decimal precision should be between 1 and 65")
+	}
+	if scale < 0 || scale > maxDecimalScale || scale > precision {
+		panic("decimal scale should be between 0 and 30, and not greater than precision")
+	}
+
+	dflt := "0"
+	if v, ok := tag.LookUp("default"); ok {
+		if _, err := ParseDecimal(v); err != nil {
+			panic("decimal default value should be decimal number")
+		}
+		dflt = v
+	}
+	typ := "DECIMAL(" + strconv.Itoa(precision) + "," + strconv.Itoa(scale) + ")"
+	if _, ok := tag.LookUp("unsigned"); ok {
+		typ += " UNSIGNED"
+	}
+	return columns.Column{
+		Name:         sf.Name(),
+		DataType:     "DECIMAL",
+		Type:         typ,
+		Nullable:     reflext.IsNullable(sf.Type()),
+		DefaultValue: &dflt,
+	}
+}
+
+// Value : the decimal is sent as string, so it won't be rounded by float
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan :
+func (d *Decimal) Scan(it interface{}) (err error) {
+	switch vi := it.(type) {
+	case []byte:
+		*d, err = ParseDecimal(string(vi))
+	case string:
+		*d, err = ParseDecimal(vi)
+	case int64:
+		*d = NewDecimal(vi, 0)
+	case float64:
+		*d, err = NewDecimalFromFloat(vi)
+	case nil:
+		*d = Decimal{}
+	default:
+		err = fmt.Errorf("types: unable to scan %T into decimal", it)
+	}
+	return
+}
+
+// MarshalText :
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText :
+func (d *Decimal) UnmarshalText(b []byte) (err error) {
+	*d, err = ParseDecimal(string(b))
+	return
+}
+
+// MarshalJSON :
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return d.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	return d.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB : marshal as json string, so the precision won't be lost by the json parser
+func (d Decimal) MarshalJSONB() ([]byte, error) {
+	return []byte(`"` + d.String() + `"`), nil
+}
+
+// MarshalJSONBNumber : marshal as json number, it's used by `jsonb` when `DecimalAsNumber` is enabled
+func (d Decimal) MarshalJSONBNumber() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSONB : both json string and number are accepted
+func (d *Decimal) UnmarshalJSONB(b []byte) (err error) {
+	str := string(b)
+	if str == "null" {
+		*d = Decimal{}
+		return nil
+	}
+	if n := len(str); n > 1 && str[0] == '"' && str[n-1] == '"' {
+		str = str[1 : n-1]
+	}
+	*d, err = ParseDecimal(str)
+	return
+}
@@ -0,0 +1,221 @@
+package types
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimal(t *testing.T) {
+	t.Run("ParseDecimal", func(t *testing.T) {
+		for str, result := range map[string]string{
+			"0":          "0",
+			"12.30":      "12.30",
+			"-0.001":     "-0.001",
+			"+.5":        "0.5",
+			"7.":         "7",
+			"1.5e-3":     "0.0015",
+			"-1.5E2":     "-150",
+			"1e95":       "1" + strings.Repeat("0", 95),
+			"5e-95":      "0." + strings.Repeat("0", 94) + "5",
+			" 10 ":       "10",
+			"0.00000000": "0.00000000",
+			"123456789012345678901234567890.123456789": "123456789012345678901234567890.123456789",
+		} {
+			d, err := ParseDecimal(str)
+			require.NoError(t, err, str)
+			require.Equal(t, result, d.String(), str)
+		}
+
+		for _, str := range []string{"", ".", "abc", "1.2.3", "+-1", "1e", "1e1.5", "--1", "1e96", "1e-96", "1e999999999"} {
+			_, err := ParseDecimal(str)
+			require.Error(t, err, str)
+		}
+
+		require.Panics(t, func() {
+			MustParseDecimal("x")
+		})
+	})
+
+	t.Run("Constructors", func(t *testing.T) {
+		var zero Decimal
+		require.Equal(t, "0", zero.String())
+		require.True(t, zero.IsZero())
+		require.Equal(t, "123.45", NewDecimal(12345, 2).String())
+		require.Equal(t, "-0.05", NewDecimal(-5, 2).String())
+		require.Equal(t, "1200", NewDecimal(12, -2).String())
+		require.Equal(t, "1.0", NewDecimalFromBigInt(big.NewInt(10), 1).String())
+		require.Panics(t, func() {
+			NewDecimalFromBigInt(big.NewInt(1), -999999999)
+		})
+
+		d, err := NewDecimalFromFloat(0.1)
+		require.NoError(t, err)
+		require.Equal(t, "0.1", d.String())
+		d, err = NewDecimalFromFloat(1e21)
+		require.NoError(t, err)
+		require.Equal(t, "1000000000000000000000", d.String())
+		_, err = NewDecimalFromFloat(math.NaN())
+		require.Error(t, err)
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		a, b := MustParseDecimal("0.1"), MustParseDecimal("0.2")
+		require.Equal(t, "0.3", a.Add(b).String())
+		require.True(t, a.Add(b).Equal(MustParseDecimal("0.30")))
+		require.Equal(t, "-0.1", a.Sub(b).String())
+		require.Equal(t, "0.02", a.Mul(b).String())
+		require.Equal(t, "-0.1", a.Neg().String())
+		require.Equal(t, "0.1", a.Neg().Abs().String())
+		require.Equal(t, -1, a.Cmp(b))
+		require.Equal(t, 1, b.Cmp(a))
+		require.Equal(t, 0, a.Cmp(MustParseDecimal("0.100")))
+		require.Equal(t, -1, a.Neg().Sign())
+		require.Equal(t, 0.1, a.Float64())
+		require.Equal(t, big.NewRat(1, 10), a.Rat())
+		require.Equal(t, int32(1), a.Scale())
+		require.Equal(t, big.NewInt(1), a.Unscaled())
+
+		d, err := MustParseDecimal("10").Div(MustParseDecimal("3"), 4)
+		require.NoError(t, err)
+		require.Equal(t, "3.3333", d.String())
+		d, err = MustParseDecimal("2").Div(MustParseDecimal("3"), 2)
+		require.NoError(t, err)
+		require.Equal(t, "0.67", d.String())
+		d, err = MustParseDecimal("-2").Div(MustParseDecimal("0.03"), 0)
+		require.NoError(t, err)
+		require.Equal(t, "-67", d.String())
+		d, err = MustParseDecimal("1.25").Div(MustParseDecimal("-0.5"), 1)
+		require.NoError(t, err)
+		require.Equal(t, "-2.5", d.String())
+		_, err = a.Div(Decimal{}, 2)
+		require.Equal(t, ErrDivisionByZero, err)
+
+		require.Equal(t, "1.25", MustParseDecimal("1.245").Round(2).String())
+		require.Equal(t, "-1.25", MustParseDecimal("-1.245").Round(2).String())
+		require.Equal(t, "1.24", MustParseDecimal("1.2449").Round(2).String())
+		require.Equal(t, "1.2000", MustParseDecimal("1.2").Round(4).String())
+		require.Equal(t, "-1.24", MustParseDecimal("-1.249").Truncate(2).String())
+		require.Equal(t, "3", MustParseDecimal("3.99").Truncate(-1).String())
+
+		// the operands are not mutated
+		require.Equal(t, "0.1", a.String())
+		require.Equal(t, "0.2", b.String())
+	})
+
+	t.Run("DataType", func(t *testing.T) {
+		type ledger struct {
+			Amount Decimal
+			Fee    *Decimal `sqlike:",precision=12,scale=2,default=1.50,unsigned"`
+			Rate   Decimal  `sqlike:",precision=10"`
+		}
+
+		fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(ledger{})).Properties()
+		col := Decimal{}.DataType(nil, fields[0])
+		require.Equal(t, "Amount", col.Name)
+		require.Equal(t, "DECIMAL", col.DataType)
+		require.Equal(t, "DECIMAL(65,30)", col.Type)
+		require.Equal(t, "0", *col.DefaultValue)
+		require.False(t, col.Nullable)
+
+		col = Decimal{}.DataType(nil, fields[1])
+		require.Equal(t, "DECIMAL(12,2) UNSIGNED", col.Type)
+		require.Equal(t, "1.50", *col.DefaultValue)
+		require.True(t, col.Nullable)
+
+		col = Decimal{}.DataType(nil, fields[2])
+		require.Equal(t, "DECIMAL(10,0)", col.Type)
+
+		for _, it := range []interface{}{
+			struct {
+				D Decimal `sqlike:",precision=66"`
+			}{},
+			struct {
+				D Decimal `sqlike:",precision=abc"`
+			}{},
+			struct {
+				D Decimal `sqlike:",scale=31"`
+			}{},
+			struct {
+				D Decimal `sqlike:",precision=5,scale=6"`
+			}{},
+			struct {
+				D Decimal `sqlike:",default=x"`
+			}{},
+		} {
+			sf := reflext.DefaultMapper.CodecByType(reflect.TypeOf(it)).Properties()[0]
+			require.Panics(t, func() {
+				Decimal{}.DataType(nil, sf)
+			}, sf.Tag())
+		}
+	})
+
+	t.Run("Value and Scan", func(t *testing.T) {
+		v, err := MustParseDecimal("12345678901234567890.123456789").Value()
+		require.NoError(t, err)
+		require.Equal(t, "12345678901234567890.123456789", v)
+
+		var d Decimal
+		for src, result := range map[interface{}]string{
+			"1.10":        "1.10",
+			int64(-20):    "-20",
+			float64(0.25): "0.25",
+			nil:           "0",
+		} {
+			require.NoError(t, d.Scan(src))
+			require.Equal(t, result, d.String())
+		}
+		require.NoError(t, d.Scan([]byte("99.990")))
+		require.Equal(t, "99.990", d.String())
+		require.Error(t, d.Scan([]byte("abc")))
+		require.Error(t, d.Scan(true))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type ledger struct {
+			Amount Decimal
+			Fee    *Decimal
+		}
+
+		fee := MustParseDecimal("0.01")
+		src := ledger{Amount: MustParseDecimal("1000000000000000000.05"), Fee: &fee}
+		b, err := jsonb.Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Amount":"1000000000000000000.05","Fee":"0.01"}`, string(b))
+
+		b, err = json.Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Amount":"1000000000000000000.05","Fee":"0.01"}`, string(b))
+
+		b, err = jsonb.NewCodec(jsonb.Config{DecimalAsNumber: true}).Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Amount":1000000000000000000.05,"Fee":0.01}`, string(b))
+
+		var dest ledger
+		require.NoError(t, jsonb.Unmarshal(b, &dest))
+		require.Equal(t, "1000000000000000000.05", dest.Amount.String())
+		require.Equal(t, "0.01", dest.Fee.String())
+
+		dest = ledger{}
+		require.NoError(t, jsonb.Unmarshal([]byte(`{"Amount":"-3.50","Fee":null}`), &dest))
+		require.Equal(t, "-3.50", dest.Amount.String())
+		require.Nil(t, dest.Fee)
+
+		dest = ledger{}
+		require.NoError(t, json.Unmarshal([]byte(`{"Amount":1e-2}`), &dest))
+		require.Equal(t, "0.01", dest.Amount.String())
+
+		text, err := MustParseDecimal("7.70").MarshalText()
+		require.NoError(t, err)
+		var d Decimal
+		require.NoError(t, d.UnmarshalText(text))
+		require.Equal(t, "7.70", d.String())
+	})
+}
@@ -10,12 +10,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
-	"time"
 
 	"errors"
 
@@ -24,7 +22,6 @@ import (
 	sqldriver "github.com/Oskang09/sqlike/sql/driver"
 	"github.com/Oskang09/sqlike/sqlike/columns"
 	"github.com/Oskang09/sqlike/util"
-	"github.com/segmentio/ksuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
@@ -357,11 +354,26 @@ func (k Key) String() string {
 	return b.String()
 }
 
-// marshal marshals the key's string representation to the buffer.
+// NamespacePrefix : the prefix of the string representation of the keys in the namespace,
+// eg. `tenant:Account,1`, it's empty for the default namespace
+func NamespacePrefix(ns string) string {
+	if ns == "" {
+		return ""
+	}
+	return url.QueryEscape(ns) + ":"
+}
+
+// marshal marshals the key's string representation to the buffer, the namespace of the root key is the prefix.
 func marshal(k *Key, w writer, escape bool) {
 	if k.Parent != nil {
 		marshal(k.Parent, w, escape)
 		w.WriteByte('/')
+	} else if k.Namespace != "" {
+		if escape {
+			w.WriteString(NamespacePrefix(k.Namespace))
+		} else {
+			w.WriteString(k.Namespace + ":")
+		}
 	}
 	w.WriteString(k.Kind)
 	w.WriteByte(',')
@@ -378,12 +390,33 @@ func marshal(k *Key, w writer, escape bool) {
 	}
 }
 
+// unmarshal : the namespace prefix is before the kind of the root key, it's applied to every key of the path
 func (k *Key) unmarshal(str string) error {
 	if str == "null" {
-		k = nil
 		return nil
 	}
 
+	ns := ""
+	if i := strings.IndexByte(str, ','); i > -1 {
+		if j := strings.IndexByte(str[:i], ':'); j > -1 {
+			v, err := url.QueryUnescape(str[:j])
+			if err != nil {
+				return err
+			}
+			ns, str = v, str[j+1:]
+		}
+	}
+	if err := k.unmarshalPath(str); err != nil {
+		return err
+	}
+	for ; k != nil; k = k.Parent {
+		k.Namespace = ns
+	}
+	return nil
+}
+
+func (k *Key) unmarshalPath(str string) error {
+
 	var (
 		idx    int
 		path   string
@@ -587,16 +620,9 @@ func IDKey(kind string, id int64, parent *Key) *Key {
 	}
 }
 
-const (
-	minSeed = int64(100000000)
-	maxSeed = int64(999999999)
-)
-
-// NewIDKey :
+// NewIDKey : the id is generated by the generator of `SetKeyIDGenerator`
 func NewIDKey(kind string, parent *Key) *Key {
-	rand.Seed(time.Now().UnixNano())
-	strID := strconv.FormatInt(time.Now().Unix(), 10) + strconv.FormatInt(rand.Int63n(maxSeed-minSeed)+minSeed, 10)
-	id, err := strconv.ParseInt(strID, 10, 64)
+	id, err := getKeyIDGenerator().NextID()
 	if err != nil {
 		panic(err)
 	}
@@ -609,12 +635,17 @@ func NewIDKey(kind string, parent *Key) *Key {
 	}
 }
 
-// NewNameKey :
+// NewNameKey : the name is generated by the generator of `SetKeyNameGenerator`
 func NewNameKey(kind string, parent *Key) *Key {
+	name, err := getKeyNameGenerator().NextName()
+	if err != nil {
+		panic(err)
+	}
+
 	return &Key{
 		Namespace: os.Getenv(keyEnv),
 		Kind:      kind,
-		NameID:    ksuid.New().String(),
+		NameID:    name,
 		Parent:    parent,
 	}
 }
@@ -129,6 +129,27 @@ func TestKey(t *testing.T) {
 		require.True(it, nk.Equal(k))
 	})
 
+	t.Run("Namespace", func(it *testing.T) {
+		nk := NameKey("User", "a:b", IDKey("Account", 1, nil))
+		nk.Namespace, nk.Parent.Namespace = "tenant/1:x", "tenant/1:x"
+		require.Equal(it, "tenant%2F1%3Ax:", NamespacePrefix(nk.Namespace))
+		require.Equal(it, "", NamespacePrefix(""))
+		require.Equal(it, `tenant%2F1%3Ax:Account,1/User,'a:b'`, nk.String())
+		require.Equal(it, `tenant/1:x:Account,1/User,'a:b'`, nk.GoString())
+
+		v, err := nk.Value()
+		require.NoError(it, err)
+		k := new(Key)
+		require.NoError(it, k.Scan(v))
+		require.Equal(it, nk, k)
+		require.True(it, nk.Equal(k))
+
+		k, err = ParseKey(`Account,1/User,'a:b'`)
+		require.NoError(it, err)
+		require.Equal(it, "", k.Namespace)
+		require.Equal(it, "", k.Parent.Namespace)
+	})
+
 	t.Run("Clone", func(it *testing.T) {
 		str := `Parent,1288888/Name,'sianloong'`
 		k, err = ParseKey(str)
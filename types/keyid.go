@@ -0,0 +1,293 @@
+package types
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/ksuid"
+)
+
+const nodeEnv = "SQLIKE_NODE_ID"
+
+// KeyIDGenerator : generates the int id of `NewIDKey`, it must be safe for concurrent use
+type KeyIDGenerator interface {
+	NextID() (int64, error)
+}
+
+// KeyNameGenerator : generates the name of `NewNameKey`, it must be safe for concurrent use
+type KeyNameGenerator interface {
+	NextName() (string, error)
+}
+
+var (
+	keyGenMu      sync.RWMutex
+	idGenerator   KeyIDGenerator
+	nameGenerator KeyNameGenerator = KSUID{}
+)
+
+// SetKeyIDGenerator : set the process wide generator of `NewIDKey`,
+// the default is `Snowflake` with the node id of `SQLIKE_NODE_ID` environment variable
+func SetKeyIDGenerator(gen KeyIDGenerator) {
+	keyGenMu.Lock()
+	defer keyGenMu.Unlock()
+	idGenerator = gen
+}
+
+// SetKeyNameGenerator : set the process wide generator of `NewNameKey`, the default is `KSUID`
+func SetKeyNameGenerator(gen KeyNameGenerator) {
+	keyGenMu.Lock()
+	defer keyGenMu.Unlock()
+	nameGenerator = gen
+}
+
+func getKeyIDGenerator() KeyIDGenerator {
+	keyGenMu.RLock()
+	gen := idGenerator
+	keyGenMu.RUnlock()
+	if gen != nil {
+		return gen
+	}
+
+	keyGenMu.Lock()
+	defer keyGenMu.Unlock()
+	if idGenerator == nil {
+		idGenerator = defaultSnowflake()
+	}
+	return idGenerator
+}
+
+func getKeyNameGenerator() KeyNameGenerator {
+	keyGenMu.RLock()
+	defer keyGenMu.RUnlock()
+	return nameGenerator
+}
+
+// defaultSnowflake : every instance should have an unique node id, otherwise the random
+// node id is used and collision is possible when there are many instances
+func defaultSnowflake() *Snowflake {
+	var node int64
+	if v, ok := os.LookupEnv(nodeEnv); ok {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			panic(fmt.Errorf("types: invalid %s %q: %w", nodeEnv, v, err))
+		}
+		node = n
+	} else {
+		var b [2]byte
+		if _, err := io.ReadFull(rand.Reader, b[:]); err != nil {
+			panic(err)
+		}
+		node = int64(binary.BigEndian.Uint16(b[:])) & maxNode
+	}
+	sf, err := NewSnowflake(node)
+	if err != nil {
+		panic(err)
+	}
+	return sf
+}
+
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+	maxNode      = -1 ^ (-1 << nodeBits)
+	maxSequence  = -1 ^ (-1 << sequenceBits)
+)
+
+var (
+	// ErrInvalidNodeID :
+	ErrInvalidNodeID = errors.New("types: snowflake node id must be between 0 and 1023")
+	// ErrClockSkew : the clock moved backwards more than the tolerance
+	ErrClockSkew = errors.New("types: clock moved backwards")
+)
+
+// DefaultSnowflakeEpoch : the ids generated since this epoch are always greater than
+// the legacy ids of `NewIDKey`, which are the unix second concatenated with 9 random digits
+var DefaultSnowflakeEpoch = time.Unix(0, 1288834974657*int64(time.Millisecond))
+
+// SnowflakeOption :
+type SnowflakeOption func(*Snowflake)
+
+// WithEpoch : the custom epoch, it cannot be changed once there are generated ids
+func WithEpoch(epoch time.Time) SnowflakeOption {
+	return func(sf *Snowflake) {
+		sf.epoch = epoch.UnixNano() / int64(time.Millisecond)
+	}
+}
+
+// WithMaxClockSkew : wait for the clock when it moved backwards within the duration,
+// otherwise `ErrClockSkew` is returned, the default is 10 milliseconds
+func WithMaxClockSkew(d time.Duration) SnowflakeOption {
+	return func(sf *Snowflake) {
+		sf.maxSkew = d
+	}
+}
+
+// WithClock :
+func WithClock(now func() time.Time) SnowflakeOption {
+	return func(sf *Snowflake) {
+		sf.now = now
+	}
+}
+
+// Snowflake : the id consists of 41 bits milliseconds since epoch, 10 bits node id
+// and 12 bits sequence, so every node can generate 4096 ids per millisecond
+type Snowflake struct {
+	mu       sync.Mutex
+	epoch    int64
+	node     int64
+	sequence int64
+	last     int64
+	maxSkew  time.Duration
+	now      func() time.Time
+	sleep    func(time.Duration)
+}
+
+// NewSnowflake :
+func NewSnowflake(node int64, opts ...SnowflakeOption) (*Snowflake, error) {
+	if node < 0 || node > maxNode {
+		return nil, ErrInvalidNodeID
+	}
+	sf := &Snowflake{
+		node:    node,
+		maxSkew: 10 * time.Millisecond,
+		now:     time.Now,
+		sleep:   time.Sleep,
+	}
+	WithEpoch(DefaultSnowflakeEpoch)(sf)
+	for _, opt := range opts {
+		opt(sf)
+	}
+	return sf, nil
+}
+
+// Node :
+func (sf *Snowflake) Node() int64 {
+	return sf.node
+}
+
+// NextID :
+func (sf *Snowflake) NextID() (int64, error) {
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+
+	ts := sf.millis()
+	if ts < 0 {
+		return 0, errors.New("types: clock is before the snowflake epoch")
+	}
+	if ts < sf.last {
+		skew := time.Duration(sf.last-ts) * time.Millisecond
+		if skew > sf.maxSkew {
+			return 0, fmt.Errorf("%w by %v", ErrClockSkew, skew)
+		}
+		sf.sleep(skew)
+		if ts = sf.millis(); ts < sf.last {
+			return 0, fmt.Errorf("%w by %v", ErrClockSkew, time.Duration(sf.last-ts)*time.Millisecond)
+		}
+	}
+
+	if ts == sf.last {
+		sf.sequence = (sf.sequence + 1) & maxSequence
+		// sequence is exhausted, wait for the next millisecond
+		for sf.sequence == 0 && ts <= sf.last {
+			sf.sleep(time.Millisecond / 10)
+			ts = sf.millis()
+		}
+	} else {
+		sf.sequence = 0
+	}
+	sf.last = ts
+
+	if ts>>(63-nodeBits-sequenceBits) > 0 {
+		return 0, errors.New("types: snowflake timestamp is out of range")
+	}
+	return ts<<(nodeBits+sequenceBits) | sf.node<<sequenceBits | sf.sequence, nil
+}
+
+func (sf *Snowflake) millis() int64 {
+	return sf.now().UnixNano()/int64(time.Millisecond) - sf.epoch
+}
+
+// KSUID : the name is 27 characters which sortable by the generated second
+type KSUID struct{}
+
+// NextName :
+func (KSUID) NextName() (string, error) {
+	id, err := ksuid.NewRandom()
+	if err != nil {
+		return "", err
+	}
+	return id.String(), nil
+}
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID : the name is 26 characters which consists of 48 bits milliseconds and 80 bits randomness,
+// the randomness is incremented within the same millisecond, so the names are monotonic
+type ULID struct {
+	mu      sync.Mutex
+	entropy io.Reader
+	now     func() time.Time
+	last    uint64
+	rand    [10]byte
+}
+
+// NewULID : the entropy is `crypto/rand` if it's nil
+func NewULID(entropy io.Reader) *ULID {
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+	return &ULID{entropy: entropy, now: time.Now}
+}
+
+// NextName :
+func (u *ULID) NextName() (string, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	ms := uint64(u.now().UnixNano() / int64(time.Millisecond))
+	if ms <= u.last {
+		// increment the randomness as 80 bits big endian integer
+		ms = u.last
+		i := len(u.rand) - 1
+		for ; i >= 0; i-- {
+			u.rand[i]++
+			if u.rand[i] != 0 {
+				break
+			}
+		}
+		if i < 0 {
+			return "", errors.New("types: ulid randomness overflow")
+		}
+	} else {
+		if _, err := io.ReadFull(u.entropy, u.rand[:]); err != nil {
+			return "", err
+		}
+		u.last = ms
+	}
+
+	var id [16]byte
+	id[0], id[1], id[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	id[3], id[4], id[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(id[6:], u.rand[:])
+	return encodeULID(id), nil
+}
+
+// encodeULID : encode 128 bits with crockford's base32, the first character only has 3 bits
+func encodeULID(id [16]byte) string {
+	var dst [26]byte
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	for i := 25; i >= 0; i-- {
+		dst[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(dst[:])
+}
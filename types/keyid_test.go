@@ -0,0 +1,167 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnowflake(t *testing.T) {
+	_, err := NewSnowflake(-1)
+	require.Equal(t, ErrInvalidNodeID, err)
+	_, err = NewSnowflake(1024)
+	require.Equal(t, ErrInvalidNodeID, err)
+
+	t.Run("Layout", func(t *testing.T) {
+		now := DefaultSnowflakeEpoch.Add(1500 * time.Millisecond)
+		sf, err := NewSnowflake(5, WithClock(func() time.Time { return now }))
+		require.NoError(t, err)
+		require.Equal(t, int64(5), sf.Node())
+
+		id, err := sf.NextID()
+		require.NoError(t, err)
+		require.Equal(t, int64(1500<<22|5<<12), id)
+
+		id, err = sf.NextID()
+		require.NoError(t, err)
+		require.Equal(t, int64(1500<<22|5<<12|1), id)
+	})
+
+	t.Run("Greater than legacy id", func(t *testing.T) {
+		sf, err := NewSnowflake(0)
+		require.NoError(t, err)
+		id, err := sf.NextID()
+		require.NoError(t, err)
+		require.Greater(t, id, int64(1999999999999999999))
+	})
+
+	t.Run("Sequence exhausted", func(t *testing.T) {
+		now := time.Unix(100, 0)
+		sf, err := NewSnowflake(1, WithEpoch(time.Unix(0, 0)), WithClock(func() time.Time { return now }))
+		require.NoError(t, err)
+		sf.sleep = func(time.Duration) { now = now.Add(time.Millisecond) }
+
+		var last int64
+		for i := 0; i <= maxSequence+1; i++ {
+			id, err := sf.NextID()
+			require.NoError(t, err)
+			require.Greater(t, id, last)
+			last = id
+		}
+		require.Equal(t, int64(100001<<22|1<<12), last)
+	})
+
+	t.Run("Clock skew", func(t *testing.T) {
+		now := DefaultSnowflakeEpoch.Add(time.Hour)
+		sf, err := NewSnowflake(1, WithClock(func() time.Time { return now }), WithMaxClockSkew(5*time.Millisecond))
+		require.NoError(t, err)
+		slept := time.Duration(0)
+		sf.sleep = func(d time.Duration) {
+			slept += d
+			now = now.Add(d)
+		}
+
+		first, err := sf.NextID()
+		require.NoError(t, err)
+
+		// wait for the clock within the tolerance
+		now = now.Add(-3 * time.Millisecond)
+		id, err := sf.NextID()
+		require.NoError(t, err)
+		require.Greater(t, id, first)
+		require.Equal(t, 3*time.Millisecond, slept)
+
+		now = now.Add(-time.Second)
+		_, err = sf.NextID()
+		require.True(t, errors.Is(err, ErrClockSkew))
+
+		now = DefaultSnowflakeEpoch.Add(-time.Millisecond)
+		_, err = sf.NextID()
+		require.Error(t, err)
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		sf, err := NewSnowflake(10)
+		require.NoError(t, err)
+
+		var (
+			wg  sync.WaitGroup
+			mu  sync.Mutex
+			ids = make(map[int64]struct{})
+		)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 5000; j++ {
+					id, err := sf.NextID()
+					if err != nil {
+						panic(err)
+					}
+					mu.Lock()
+					ids[id] = struct{}{}
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		require.Len(t, ids, 40000)
+	})
+}
+
+func TestULID(t *testing.T) {
+	now := time.Unix(1469918176, 385000000)
+	u := NewULID(bytes.NewReader(bytes.Repeat([]byte{0xff}, 9)))
+	u.now = func() time.Time { return now }
+
+	// the entropy is not enough
+	_, err := u.NextName()
+	require.Error(t, err)
+
+	u = NewULID(bytes.NewReader(make([]byte, 10)))
+	u.now = func() time.Time { return now }
+	name, err := u.NextName()
+	require.NoError(t, err)
+	require.Equal(t, "01ARYZ6S410000000000000000", name)
+
+	// monotonic within the same millisecond
+	name, err = u.NextName()
+	require.NoError(t, err)
+	require.Equal(t, "01ARYZ6S410000000000000001", name)
+
+	u = NewULID(nil)
+	names := make([]string, 1000)
+	for i := range names {
+		names[i], err = u.NextName()
+		require.NoError(t, err)
+		require.Len(t, names[i], 26)
+	}
+	require.True(t, sort.StringsAreSorted(names))
+}
+
+func TestKeyGenerator(t *testing.T) {
+	defer SetKeyIDGenerator(nil)
+	defer SetKeyNameGenerator(KSUID{})
+
+	name, err := KSUID{}.NextName()
+	require.NoError(t, err)
+	require.Len(t, name, 27)
+
+	sf, err := NewSnowflake(7, WithEpoch(time.Unix(0, 0)))
+	require.NoError(t, err)
+	SetKeyIDGenerator(sf)
+	SetKeyNameGenerator(NewULID(nil))
+
+	k1, k2 := NewIDKey("ID", nil), NewIDKey("ID", nil)
+	require.Greater(t, k2.IntID, k1.IntID)
+	require.Equal(t, int64(7), k1.IntID>>12&maxNode)
+
+	n1, n2 := NewNameKey("Name", nil), NewNameKey("Name", nil)
+	require.Len(t, n1.NameID, 26)
+	require.Less(t, n1.NameID, n2.NameID)
+}
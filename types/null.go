@@ -0,0 +1,978 @@
+package types
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/Oskang09/sqlike/reflext"
+	"github.com/Oskang09/sqlike/sql/charset"
+	sqldriver "github.com/Oskang09/sqlike/sql/driver"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+var (
+	_ driver.Valuer         = (*NullString)(nil)
+	_ sql.Scanner           = (*NullString)(nil)
+	_ json.Marshaler        = (*NullString)(nil)
+	_ json.Unmarshaler      = (*NullString)(nil)
+	_ bson.ValueMarshaler   = (*NullString)(nil)
+	_ bson.ValueUnmarshaler = (*NullString)(nil)
+	_ driver.Valuer         = (*NullInt64)(nil)
+	_ sql.Scanner           = (*NullInt64)(nil)
+	_ bson.ValueMarshaler   = (*NullInt64)(nil)
+	_ bson.ValueUnmarshaler = (*NullInt64)(nil)
+	_ driver.Valuer         = (*NullFloat64)(nil)
+	_ sql.Scanner           = (*NullFloat64)(nil)
+	_ bson.ValueMarshaler   = (*NullFloat64)(nil)
+	_ bson.ValueUnmarshaler = (*NullFloat64)(nil)
+	_ driver.Valuer         = (*NullBool)(nil)
+	_ sql.Scanner           = (*NullBool)(nil)
+	_ bson.ValueMarshaler   = (*NullBool)(nil)
+	_ bson.ValueUnmarshaler = (*NullBool)(nil)
+	_ driver.Valuer         = (*NullTime)(nil)
+	_ sql.Scanner           = (*NullTime)(nil)
+	_ bson.ValueMarshaler   = (*NullTime)(nil)
+	_ bson.ValueUnmarshaler = (*NullTime)(nil)
+	_ driver.Valuer         = (*NullKey)(nil)
+	_ sql.Scanner           = (*NullKey)(nil)
+	_ bson.ValueMarshaler   = (*NullKey)(nil)
+	_ bson.ValueUnmarshaler = (*NullKey)(nil)
+	_ driver.Valuer         = (*Null[int])(nil)
+	_ sql.Scanner           = (*Null[int])(nil)
+	_ bson.ValueMarshaler   = (*Null[int])(nil)
+	_ bson.ValueUnmarshaler = (*Null[int])(nil)
+)
+
+var null = []byte(`null`)
+
+func isNull(b []byte) bool {
+	return string(b) == "null"
+}
+
+// nullColumn : the nullable types are always nullable columns, so the default value is not applicable
+func nullColumn(sf reflext.StructFielder, dataType, typ string) columns.Column {
+	return columns.Column{
+		Name:     sf.Name(),
+		DataType: dataType,
+		Type:     typ,
+		Nullable: true,
+	}
+}
+
+func unmarshalNullGQL(it interface{}, unmarshal func([]byte) error) error {
+	switch vi := it.(type) {
+	case []byte:
+		return unmarshal(vi)
+	case json.Number:
+		return unmarshal([]byte(vi))
+	case nil:
+		return unmarshal(null)
+	default:
+		b, err := json.Marshal(vi)
+		if err != nil {
+			return err
+		}
+		return unmarshal(b)
+	}
+}
+
+// NullString : the string which is `NULL` when `Valid` is false
+type NullString struct {
+	String string
+	Valid  bool
+}
+
+// NewNullString :
+func NewNullString(s string) NullString {
+	return NullString{String: s, Valid: true}
+}
+
+// DataType : the `size`, `charset` and `collate` tags are same as string
+func (n NullString) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	tag := sf.Tag()
+	size := "191"
+	if v, ok := tag.LookUp("size"); ok {
+		if _, err := strconv.Atoi(v); err == nil {
+			size = v
+		}
+	}
+	cs, _ := tag.LookUp("charset")
+	cl, _ := tag.LookUp("collate")
+	chset, collation := charset.Resolve(cs, cl)
+	col := nullColumn(sf, "VARCHAR", "VARCHAR("+size+")")
+	col.Charset = &chset
+	col.Collation = &collation
+	// unknown charset without `collate` tag, leave it to the server default
+	if collation == "" {
+		col.Collation = nil
+	}
+	return col
+}
+
+// Value :
+func (n NullString) Value() (driver.Value, error) {
+	return sql.NullString{String: n.String, Valid: n.Valid}.Value()
+}
+
+// Scan :
+func (n *NullString) Scan(it interface{}) error {
+	var x sql.NullString
+	if err := x.Scan(it); err != nil {
+		return err
+	}
+	n.String, n.Valid = x.String, x.Valid
+	return nil
+}
+
+// MarshalJSON :
+func (n NullString) MarshalJSON() ([]byte, error) {
+	return n.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB :
+func (n NullString) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.String)
+}
+
+// UnmarshalJSONB :
+func (n *NullString) UnmarshalJSONB(b []byte) error {
+	*n = NullString{}
+	if isNull(b) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.String); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue :
+func (n NullString) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return bsontype.String, bsoncore.AppendString(nil, n.String), nil
+}
+
+// UnmarshalBSONValue :
+func (n *NullString) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullString{}
+	if t == bsontype.Null {
+		return nil
+	}
+	v, _, ok := bsoncore.ReadString(b)
+	if !ok {
+		return errors.New("types: invalid bson string value")
+	}
+	n.String, n.Valid = v, true
+	return nil
+}
+
+// MarshalGQL :
+func (n NullString) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSONB()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *NullString) UnmarshalGQL(it interface{}) error {
+	if vi, ok := it.(string); ok {
+		*n = NewNullString(vi)
+		return nil
+	}
+	return unmarshalNullGQL(it, n.UnmarshalJSONB)
+}
+
+// NullInt64 : the int64 which is `NULL` when `Valid` is false
+type NullInt64 struct {
+	Int64 int64
+	Valid bool
+}
+
+// NewNullInt64 :
+func NewNullInt64(i int64) NullInt64 {
+	return NullInt64{Int64: i, Valid: true}
+}
+
+// DataType :
+func (n NullInt64) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	return nullColumn(sf, "BIGINT", "BIGINT")
+}
+
+// Value :
+func (n NullInt64) Value() (driver.Value, error) {
+	return sql.NullInt64{Int64: n.Int64, Valid: n.Valid}.Value()
+}
+
+// Scan :
+func (n *NullInt64) Scan(it interface{}) error {
+	var x sql.NullInt64
+	if err := x.Scan(it); err != nil {
+		return err
+	}
+	n.Int64, n.Valid = x.Int64, x.Valid
+	return nil
+}
+
+// MarshalJSON :
+func (n NullInt64) MarshalJSON() ([]byte, error) {
+	return n.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (n *NullInt64) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB :
+func (n NullInt64) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return strconv.AppendInt(nil, n.Int64, 10), nil
+}
+
+// UnmarshalJSONB :
+func (n *NullInt64) UnmarshalJSONB(b []byte) error {
+	*n = NullInt64{}
+	if isNull(b) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.Int64); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue :
+func (n NullInt64) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return bsontype.Int64, bsoncore.AppendInt64(nil, n.Int64), nil
+}
+
+// UnmarshalBSONValue : both bson int32 and int64 are accepted
+func (n *NullInt64) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullInt64{}
+	switch t {
+	case bsontype.Null:
+		return nil
+	case bsontype.Int32:
+		v, _, ok := bsoncore.ReadInt32(b)
+		if !ok {
+			return errors.New("types: invalid bson int32 value")
+		}
+		n.Int64 = int64(v)
+	default:
+		v, _, ok := bsoncore.ReadInt64(b)
+		if !ok {
+			return errors.New("types: invalid bson int64 value")
+		}
+		n.Int64 = v
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalGQL :
+func (n NullInt64) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSONB()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *NullInt64) UnmarshalGQL(it interface{}) error {
+	if vi, ok := it.(string); ok {
+		i, err := strconv.ParseInt(vi, 10, 64)
+		if err != nil {
+			return err
+		}
+		*n = NewNullInt64(i)
+		return nil
+	}
+	return unmarshalNullGQL(it, n.UnmarshalJSONB)
+}
+
+// NullFloat64 : the float64 which is `NULL` when `Valid` is false
+type NullFloat64 struct {
+	Float64 float64
+	Valid   bool
+}
+
+// NewNullFloat64 :
+func NewNullFloat64(f float64) NullFloat64 {
+	return NullFloat64{Float64: f, Valid: true}
+}
+
+// DataType : the `unsigned` tag is same as float
+func (n NullFloat64) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	typ := "REAL"
+	if _, ok := sf.Tag().LookUp("unsigned"); ok {
+		typ += " UNSIGNED"
+	}
+	return nullColumn(sf, "REAL", typ)
+}
+
+// Value :
+func (n NullFloat64) Value() (driver.Value, error) {
+	return sql.NullFloat64{Float64: n.Float64, Valid: n.Valid}.Value()
+}
+
+// Scan :
+func (n *NullFloat64) Scan(it interface{}) error {
+	var x sql.NullFloat64
+	if err := x.Scan(it); err != nil {
+		return err
+	}
+	n.Float64, n.Valid = x.Float64, x.Valid
+	return nil
+}
+
+// MarshalJSON :
+func (n NullFloat64) MarshalJSON() ([]byte, error) {
+	return n.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (n *NullFloat64) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB :
+func (n NullFloat64) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Float64)
+}
+
+// UnmarshalJSONB :
+func (n *NullFloat64) UnmarshalJSONB(b []byte) error {
+	*n = NullFloat64{}
+	if isNull(b) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.Float64); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue :
+func (n NullFloat64) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return bsontype.Double, bsoncore.AppendDouble(nil, n.Float64), nil
+}
+
+// UnmarshalBSONValue :
+func (n *NullFloat64) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullFloat64{}
+	if t == bsontype.Null {
+		return nil
+	}
+	v, _, ok := bsoncore.ReadDouble(b)
+	if !ok {
+		return errors.New("types: invalid bson double value")
+	}
+	n.Float64, n.Valid = v, true
+	return nil
+}
+
+// MarshalGQL :
+func (n NullFloat64) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSONB()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *NullFloat64) UnmarshalGQL(it interface{}) error {
+	if vi, ok := it.(string); ok {
+		f, err := strconv.ParseFloat(vi, 64)
+		if err != nil {
+			return err
+		}
+		*n = NewNullFloat64(f)
+		return nil
+	}
+	return unmarshalNullGQL(it, n.UnmarshalJSONB)
+}
+
+// NullBool : the bool which is `NULL` when `Valid` is false
+type NullBool struct {
+	Bool  bool
+	Valid bool
+}
+
+// NewNullBool :
+func NewNullBool(b bool) NullBool {
+	return NullBool{Bool: b, Valid: true}
+}
+
+// DataType :
+func (n NullBool) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	return nullColumn(sf, "TINYINT", "TINYINT(1)")
+}
+
+// Value :
+func (n NullBool) Value() (driver.Value, error) {
+	return sql.NullBool{Bool: n.Bool, Valid: n.Valid}.Value()
+}
+
+// Scan :
+func (n *NullBool) Scan(it interface{}) error {
+	var x sql.NullBool
+	if err := x.Scan(it); err != nil {
+		return err
+	}
+	n.Bool, n.Valid = x.Bool, x.Valid
+	return nil
+}
+
+// MarshalJSON :
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	return n.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB :
+func (n NullBool) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return strconv.AppendBool(nil, n.Bool), nil
+}
+
+// UnmarshalJSONB :
+func (n *NullBool) UnmarshalJSONB(b []byte) error {
+	*n = NullBool{}
+	if isNull(b) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.Bool); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue :
+func (n NullBool) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return bsontype.Boolean, bsoncore.AppendBoolean(nil, n.Bool), nil
+}
+
+// UnmarshalBSONValue :
+func (n *NullBool) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullBool{}
+	if t == bsontype.Null {
+		return nil
+	}
+	v, _, ok := bsoncore.ReadBoolean(b)
+	if !ok {
+		return errors.New("types: invalid bson boolean value")
+	}
+	n.Bool, n.Valid = v, true
+	return nil
+}
+
+// MarshalGQL :
+func (n NullBool) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSONB()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *NullBool) UnmarshalGQL(it interface{}) error {
+	if vi, ok := it.(string); ok {
+		v, err := strconv.ParseBool(vi)
+		if err != nil {
+			return err
+		}
+		*n = NewNullBool(v)
+		return nil
+	}
+	return unmarshalNullGQL(it, n.UnmarshalJSONB)
+}
+
+// NullTime : the time which is `NULL` when `Valid` is false, it's stored as UTC
+type NullTime struct {
+	Time  time.Time
+	Valid bool
+}
+
+// NewNullTime :
+func NewNullTime(t time.Time) NullTime {
+	return NullTime{Time: t, Valid: true}
+}
+
+// DataType : the `size` tag is the fractional seconds precision, the default is 6
+func (n NullTime) DataType(_ sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	size := "6"
+	if v, ok := sf.Tag().LookUp("size"); ok {
+		if _, err := strconv.Atoi(v); err == nil {
+			size = v
+		}
+	}
+	return nullColumn(sf, "DATETIME", "DATETIME("+size+")")
+}
+
+// Value :
+func (n NullTime) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Time.UTC(), nil
+}
+
+// Scan : the driver returns []byte when `parseTime` is not enabled
+func (n *NullTime) Scan(it interface{}) (err error) {
+	*n = NullTime{}
+	switch vi := it.(type) {
+	case time.Time:
+		n.Time = vi
+	case []byte:
+		n.Time, err = parseNullTime(string(vi))
+	case string:
+		n.Time, err = parseNullTime(vi)
+	case int64:
+		n.Time = time.Unix(vi, 0)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("types: unsupported scan type %T for NullTime", it)
+	}
+	if err != nil {
+		return err
+	}
+	n.Time, n.Valid = n.Time.UTC(), true
+	return nil
+}
+
+func parseNullTime(str string) (time.Time, error) {
+	for _, layout := range []string{
+		"2006-01-02 15:04:05.999999",
+		"2006-01-02",
+		time.RFC3339Nano,
+	} {
+		if t, err := time.Parse(layout, str); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("types: invalid time value %q", str)
+}
+
+// MarshalJSON :
+func (n NullTime) MarshalJSON() ([]byte, error) {
+	return n.MarshalJSONB()
+}
+
+// UnmarshalJSON :
+func (n *NullTime) UnmarshalJSON(b []byte) error {
+	return n.UnmarshalJSONB(b)
+}
+
+// MarshalJSONB : the time is marshalled as RFC3339 with nanoseconds
+func (n NullTime) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return n.Time.MarshalJSON()
+}
+
+// UnmarshalJSONB :
+func (n *NullTime) UnmarshalJSONB(b []byte) error {
+	*n = NullTime{}
+	if isNull(b) {
+		return nil
+	}
+	if err := n.Time.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalBSONValue : the time is truncated to milliseconds by bson datetime
+func (n NullTime) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	ms := n.Time.Unix()*1e3 + int64(n.Time.Nanosecond()/1e6)
+	return bsontype.DateTime, bsoncore.AppendDateTime(nil, ms), nil
+}
+
+// UnmarshalBSONValue :
+func (n *NullTime) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullTime{}
+	if t == bsontype.Null {
+		return nil
+	}
+	ms, _, ok := bsoncore.ReadDateTime(b)
+	if !ok {
+		return errors.New("types: invalid bson datetime value")
+	}
+	n.Time, n.Valid = time.Unix(ms/1e3, ms%1e3*1e6).UTC(), true
+	return nil
+}
+
+// MarshalGQL :
+func (n NullTime) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSONB()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *NullTime) UnmarshalGQL(it interface{}) error {
+	switch vi := it.(type) {
+	case time.Time:
+		*n = NewNullTime(vi)
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, vi)
+		if err != nil {
+			return err
+		}
+		*n = NewNullTime(t)
+	default:
+		return unmarshalNullGQL(it, n.UnmarshalJSONB)
+	}
+	return nil
+}
+
+// NullKey : the key which is `NULL` when `Valid` is false, the tags are same as `Key`
+type NullKey struct {
+	Key   Key
+	Valid bool
+}
+
+// NewNullKey : the key is invalid if it's nil or incomplete
+func NewNullKey(k *Key) NullKey {
+	if k == nil || k.Incomplete() {
+		return NullKey{}
+	}
+	return NullKey{Key: *k, Valid: true}
+}
+
+// DataType :
+func (n NullKey) DataType(t sqldriver.Info, sf reflext.StructFielder) columns.Column {
+	col := n.Key.DataType(t, sf)
+	col.Nullable = true
+	return col
+}
+
+// Value :
+func (n NullKey) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.Key.Value()
+}
+
+// Scan :
+func (n *NullKey) Scan(it interface{}) error {
+	*n = NullKey{}
+	if it == nil {
+		return nil
+	}
+	if err := n.Key.Scan(it); err != nil {
+		return err
+	}
+	n.Valid = !n.Key.Incomplete()
+	return nil
+}
+
+// MarshalJSON :
+func (n NullKey) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return n.Key.MarshalJSON()
+}
+
+// UnmarshalJSON :
+func (n *NullKey) UnmarshalJSON(b []byte) error {
+	*n = NullKey{}
+	if err := n.Key.UnmarshalJSON(b); err != nil {
+		return err
+	}
+	n.Valid = !n.Key.Incomplete()
+	return nil
+}
+
+// MarshalJSONB :
+func (n NullKey) MarshalJSONB() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return n.Key.MarshalJSONB()
+}
+
+// UnmarshalJSONB :
+func (n *NullKey) UnmarshalJSONB(b []byte) error {
+	*n = NullKey{}
+	if err := n.Key.UnmarshalJSONB(b); err != nil {
+		return err
+	}
+	n.Valid = !n.Key.Incomplete()
+	return nil
+}
+
+// MarshalBSONValue :
+func (n NullKey) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return n.Key.MarshalBSONValue()
+}
+
+// UnmarshalBSONValue :
+func (n *NullKey) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = NullKey{}
+	if err := n.Key.UnmarshalBSONValue(t, b); err != nil {
+		return err
+	}
+	n.Valid = !n.Key.Incomplete()
+	return nil
+}
+
+// MarshalGQL :
+func (n NullKey) MarshalGQL(w io.Writer) {
+	if !n.Valid {
+		w.Write(null)
+		return
+	}
+	n.Key.MarshalGQL(w)
+}
+
+// UnmarshalGQL :
+func (n *NullKey) UnmarshalGQL(it interface{}) error {
+	*n = NullKey{}
+	if err := n.Key.UnmarshalGQL(it); err != nil {
+		return err
+	}
+	n.Valid = !n.Key.Incomplete()
+	return nil
+}
+
+// Null : the value of any type which is `NULL` when `Valid` is false, the column follows the
+// data type and tags of `T`, eg. `Null[int32]` is a nullable `INT`
+type Null[T any] struct {
+	V     T
+	Valid bool
+}
+
+// NewNull :
+func NewNull[T any](v T) Null[T] {
+	return Null[T]{V: v, Valid: true}
+}
+
+// NullType : the wrapped type, it's used to build the column
+func (n Null[T]) NullType() reflect.Type {
+	return reflect.TypeOf(&n.V).Elem()
+}
+
+// Value : `T` is converted by its `driver.Valuer`, or the default parameter converter,
+// the struct, map and slice (except []byte) are stored as json
+func (n Null[T]) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	var it interface{} = n.V
+	if v, ok := it.(driver.Valuer); ok {
+		return v.Value()
+	}
+	switch reflect.ValueOf(it).Kind() {
+	case reflect.Struct, reflect.Map, reflect.Array:
+		return json.Marshal(it)
+	case reflect.Slice:
+		if _, ok := it.([]byte); !ok {
+			return json.Marshal(it)
+		}
+	}
+	return driver.DefaultParameterConverter.ConvertValue(it)
+}
+
+// Scan : `T` is scanned by its `sql.Scanner`, or converted same as `database/sql`
+func (n *Null[T]) Scan(it interface{}) error {
+	*n = Null[T]{}
+	if it == nil {
+		return nil
+	}
+	if x, ok := interface{}(&n.V).(sql.Scanner); ok {
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		n.Valid = true
+		return nil
+	}
+	if err := scanNull(reflect.ValueOf(&n.V).Elem(), it); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// scanNull : the conversion is delegated to the `sql.Null*` types
+func scanNull(v reflect.Value, it interface{}) error {
+	if _, ok := v.Interface().(time.Time); ok {
+		var x sql.NullTime
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(x.Time))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		var x sql.NullString
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		v.SetString(x.String)
+	case reflect.Bool:
+		var x sql.NullBool
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		v.SetBool(x.Bool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var x sql.NullInt64
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		if v.OverflowInt(x.Int64) {
+			return fmt.Errorf("types: value %d overflows %v", x.Int64, v.Type())
+		}
+		v.SetInt(x.Int64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var x sql.NullString
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		u, err := strconv.ParseUint(x.String, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		var x sql.NullFloat64
+		if err := x.Scan(it); err != nil {
+			return err
+		}
+		if v.OverflowFloat(x.Float64) {
+			return fmt.Errorf("types: value %v overflows %v", x.Float64, v.Type())
+		}
+		v.SetFloat(x.Float64)
+	case reflect.Slice, reflect.Map, reflect.Struct, reflect.Array:
+		var x sql.RawBytes
+		switch vi := it.(type) {
+		case []byte:
+			x = vi
+		case string:
+			x = []byte(vi)
+		default:
+			return fmt.Errorf("types: unsupported scan type %T for %v", it, v.Type())
+		}
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes(append([]byte(nil), x...))
+			return nil
+		}
+		return json.Unmarshal(x, v.Addr().Interface())
+	default:
+		return fmt.Errorf("types: unsupported scan type %T for %v", it, v.Type())
+	}
+	return nil
+}
+
+// MarshalJSON :
+func (n Null[T]) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.V)
+}
+
+// UnmarshalJSON :
+func (n *Null[T]) UnmarshalJSON(b []byte) error {
+	*n = Null[T]{}
+	if isNull(b) {
+		return nil
+	}
+	if err := json.Unmarshal(b, &n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalJSONB :
+func (n Null[T]) MarshalJSONB() ([]byte, error) {
+	return n.MarshalJSON()
+}
+
+// UnmarshalJSONB :
+func (n *Null[T]) UnmarshalJSONB(b []byte) error {
+	return n.UnmarshalJSON(b)
+}
+
+// MarshalBSONValue :
+func (n Null[T]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if !n.Valid {
+		return bsontype.Null, nil, nil
+	}
+	return bson.MarshalValue(n.V)
+}
+
+// UnmarshalBSONValue :
+func (n *Null[T]) UnmarshalBSONValue(t bsontype.Type, b []byte) error {
+	*n = Null[T]{}
+	if t == bsontype.Null {
+		return nil
+	}
+	if err := (bson.RawValue{Type: t, Value: b}).Unmarshal(&n.V); err != nil {
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// MarshalGQL :
+func (n Null[T]) MarshalGQL(w io.Writer) {
+	b, _ := n.MarshalJSON()
+	w.Write(b)
+}
+
+// UnmarshalGQL :
+func (n *Null[T]) UnmarshalGQL(it interface{}) error {
+	return unmarshalNullGQL(it, n.UnmarshalJSON)
+}
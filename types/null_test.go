@@ -0,0 +1,361 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Oskang09/sqlike/jsonb"
+	"github.com/Oskang09/sqlike/reflext"
+	sqldriver "github.com/Oskang09/sqlike/sql/driver"
+	"github.com/Oskang09/sqlike/sqlike/columns"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNull(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 891000000, time.UTC)
+	key := IDKey("User", 100, NameKey("Account", "a", nil))
+
+	t.Run("DataType", func(t *testing.T) {
+		type entity struct {
+			Name    NullString
+			Code    NullString `sqlike:",size=20,charset=latin1,collate=latin1_bin"`
+			Count   NullInt64
+			Rate    NullFloat64 `sqlike:",unsigned"`
+			Enabled NullBool
+			Expired NullTime `sqlike:",size=3"`
+			Parent  NullKey
+		}
+
+		fields := reflext.DefaultMapper.CodecByType(reflect.TypeOf(entity{})).Properties()
+		for i, result := range []string{
+			"VARCHAR(191)",
+			"VARCHAR(20)",
+			"BIGINT",
+			"REAL UNSIGNED",
+			"TINYINT(1)",
+			"DATETIME(3)",
+			"VARCHAR(512)",
+		} {
+			v := reflect.Zero(fields[i].Type()).Interface()
+			col := v.(interface {
+				DataType(sqldriver.Info, reflext.StructFielder) columns.Column
+			}).DataType(nil, fields[i])
+			require.Equal(t, fields[i].Name(), col.Name)
+			require.Equal(t, result, col.Type)
+			require.True(t, col.Nullable)
+			require.Nil(t, col.DefaultValue)
+		}
+
+		col := NullString{}.DataType(nil, fields[1])
+		require.Equal(t, "latin1", *col.Charset)
+		require.Equal(t, "latin1_bin", *col.Collation)
+		col = NullKey{}.DataType(nil, fields[6])
+		require.Equal(t, "latin1_bin", *col.Collation)
+
+		// the character set is derived from the collation, same as string
+		type collated struct {
+			Code NullString `sqlike:",collate=latin1_general_ci"`
+			Name NullString `sqlike:",charset=latin1"`
+		}
+		fields = reflext.DefaultMapper.CodecByType(reflect.TypeOf(collated{})).Properties()
+		col = NullString{}.DataType(nil, fields[0])
+		require.Equal(t, "latin1", *col.Charset)
+		require.Equal(t, "latin1_general_ci", *col.Collation)
+		col = NullString{}.DataType(nil, fields[1])
+		require.Equal(t, "latin1", *col.Charset)
+		require.Equal(t, "latin1_bin", *col.Collation)
+	})
+
+	t.Run("Value and Scan", func(t *testing.T) {
+		var (
+			s NullString
+			i NullInt64
+			f NullFloat64
+			b NullBool
+			d NullTime
+			k NullKey
+		)
+
+		v, err := s.Value()
+		require.NoError(t, err)
+		require.Nil(t, v)
+		v, err = NewNullString("abc").Value()
+		require.NoError(t, err)
+		require.Equal(t, "abc", v)
+		require.NoError(t, s.Scan([]byte("xyz")))
+		require.Equal(t, NewNullString("xyz"), s)
+		require.NoError(t, s.Scan(nil))
+		require.Equal(t, NullString{}, s)
+
+		v, err = NewNullInt64(-10).Value()
+		require.NoError(t, err)
+		require.Equal(t, int64(-10), v)
+		require.NoError(t, i.Scan([]byte("88")))
+		require.Equal(t, NewNullInt64(88), i)
+		require.Error(t, i.Scan("abc"))
+		require.NoError(t, i.Scan(nil))
+		require.False(t, i.Valid)
+
+		v, err = NewNullFloat64(1.5).Value()
+		require.NoError(t, err)
+		require.Equal(t, 1.5, v)
+		require.NoError(t, f.Scan([]byte("0.25")))
+		require.Equal(t, NewNullFloat64(0.25), f)
+
+		v, err = NewNullBool(true).Value()
+		require.NoError(t, err)
+		require.Equal(t, true, v)
+		require.NoError(t, b.Scan(int64(0)))
+		require.Equal(t, NewNullBool(false), b)
+		require.NoError(t, b.Scan(nil))
+		require.False(t, b.Valid)
+
+		v, err = NullTime{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, v)
+		v, err = NewNullTime(now.In(time.FixedZone("+8", 8*3600))).Value()
+		require.NoError(t, err)
+		require.Equal(t, now, v)
+		require.NoError(t, d.Scan([]byte("2021-03-04 05:06:07.891")))
+		require.Equal(t, NewNullTime(now), d)
+		require.NoError(t, d.Scan("2021-03-04"))
+		require.Equal(t, NewNullTime(time.Date(2021, 3, 4, 0, 0, 0, 0, time.UTC)), d)
+		require.NoError(t, d.Scan(now))
+		require.True(t, d.Valid)
+		require.Error(t, d.Scan([]byte("abc")))
+		require.Error(t, d.Scan(true))
+		require.NoError(t, d.Scan(nil))
+		require.False(t, d.Valid)
+
+		v, err = NullKey{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, v)
+		v, err = NewNullKey(key).Value()
+		require.NoError(t, err)
+		require.Equal(t, `Account,'a'/User,100`, v)
+		require.NoError(t, k.Scan([]byte(`Account,'a'/User,100`)))
+		require.Equal(t, NewNullKey(key), k)
+		require.NoError(t, k.Scan(nil))
+		require.False(t, k.Valid)
+		require.False(t, NewNullKey(nil).Valid)
+		require.False(t, NewNullKey(&Key{Kind: "User"}).Valid)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type entity struct {
+			Name    NullString
+			Count   NullInt64
+			Rate    NullFloat64
+			Enabled NullBool
+			Expired NullTime
+			Parent  NullKey
+		}
+
+		src := entity{
+			Name:    NewNullString(`a"b`),
+			Count:   NewNullInt64(10),
+			Rate:    NewNullFloat64(0.5),
+			Enabled: NewNullBool(true),
+			Expired: NewNullTime(now),
+			Parent:  NewNullKey(key),
+		}
+		b, err := jsonb.Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Name":"a\"b","Count":10,"Rate":0.5,"Enabled":true,"Expired":"2021-03-04T05:06:07.891Z","Parent":"Account,'a'/User,100"}`, string(b))
+
+		var dest entity
+		require.NoError(t, jsonb.Unmarshal(b, &dest))
+		require.Equal(t, src, dest)
+
+		b, err = jsonb.Marshal(entity{})
+		require.NoError(t, err)
+		require.Equal(t, `{"Name":null,"Count":null,"Rate":null,"Enabled":null,"Expired":null,"Parent":null}`, string(b))
+
+		dest = src
+		require.NoError(t, jsonb.Unmarshal(b, &dest))
+		require.Equal(t, entity{}, dest)
+
+		b, err = json.Marshal(entity{Count: NewNullInt64(1)})
+		require.NoError(t, err)
+		require.Equal(t, `{"Name":null,"Count":1,"Rate":null,"Enabled":null,"Expired":null,"Parent":null}`, string(b))
+
+		dest = entity{}
+		require.NoError(t, json.Unmarshal([]byte(`{"Name":"x","Enabled":false,"Parent":null}`), &dest))
+		require.Equal(t, entity{Name: NewNullString("x"), Enabled: NewNullBool(false)}, dest)
+		require.Error(t, json.Unmarshal([]byte(`{"Count":"x"}`), &dest))
+	})
+
+	t.Run("BSON", func(t *testing.T) {
+		type entity struct {
+			Name    NullString
+			Count   NullInt64
+			Rate    NullFloat64
+			Enabled NullBool
+			Expired NullTime
+			Parent  NullKey
+		}
+
+		src := entity{
+			Name:    NewNullString("abc"),
+			Count:   NewNullInt64(10),
+			Rate:    NewNullFloat64(0.5),
+			Enabled: NewNullBool(true),
+			Expired: NewNullTime(now),
+			Parent:  NewNullKey(key),
+		}
+		b, err := bson.Marshal(src)
+		require.NoError(t, err)
+
+		var dest entity
+		require.NoError(t, bson.Unmarshal(b, &dest))
+		require.Equal(t, src, dest)
+
+		b, err = bson.Marshal(entity{})
+		require.NoError(t, err)
+		dest = src
+		require.NoError(t, bson.Unmarshal(b, &dest))
+		require.Equal(t, entity{}, dest)
+
+		var i NullInt64
+		b, err = bson.Marshal(bson.M{"Count": int32(7)})
+		require.NoError(t, err)
+		require.NoError(t, bson.Unmarshal(b, &struct{ Count *NullInt64 }{&i}))
+		require.Equal(t, NewNullInt64(7), i)
+	})
+
+	t.Run("GQL", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		NewNullString("abc").MarshalGQL(w)
+		NullInt64{}.MarshalGQL(w)
+		NewNullFloat64(1.25).MarshalGQL(w)
+		NewNullBool(false).MarshalGQL(w)
+		NewNullTime(now).MarshalGQL(w)
+		NewNullKey(key).MarshalGQL(w)
+		NullKey{}.MarshalGQL(w)
+		require.Equal(t, `"abc"null1.25false"2021-03-04T05:06:07.891Z"`+`"`+key.Encode()+`"null`, w.String())
+
+		var (
+			s NullString
+			i NullInt64
+			f NullFloat64
+			b NullBool
+			d NullTime
+			k NullKey
+		)
+		require.NoError(t, s.UnmarshalGQL("abc"))
+		require.Equal(t, NewNullString("abc"), s)
+		require.NoError(t, s.UnmarshalGQL(nil))
+		require.False(t, s.Valid)
+		require.NoError(t, i.UnmarshalGQL(json.Number("12")))
+		require.Equal(t, NewNullInt64(12), i)
+		require.NoError(t, i.UnmarshalGQL(int64(13)))
+		require.Equal(t, NewNullInt64(13), i)
+		require.NoError(t, i.UnmarshalGQL("14"))
+		require.Equal(t, NewNullInt64(14), i)
+		require.Error(t, i.UnmarshalGQL(true))
+		require.NoError(t, f.UnmarshalGQL(2.5))
+		require.Equal(t, NewNullFloat64(2.5), f)
+		require.NoError(t, b.UnmarshalGQL(true))
+		require.Equal(t, NewNullBool(true), b)
+		require.NoError(t, d.UnmarshalGQL("2021-03-04T05:06:07.891Z"))
+		require.Equal(t, NewNullTime(now), d)
+		require.NoError(t, k.UnmarshalGQL(`"`+key.Encode()+`"`))
+		require.Equal(t, NewNullKey(key), k)
+		require.NoError(t, k.UnmarshalGQL(nil))
+		require.False(t, k.Valid)
+	})
+}
+
+func TestNullGeneric(t *testing.T) {
+	now := time.Date(2021, 3, 4, 5, 6, 7, 891000000, time.UTC)
+
+	t.Run("Value and Scan", func(t *testing.T) {
+		v, err := Null[int32]{}.Value()
+		require.NoError(t, err)
+		require.Nil(t, v)
+		v, err = NewNull[int32](10).Value()
+		require.NoError(t, err)
+		require.Equal(t, int64(10), v)
+		v, err = NewNull("abc").Value()
+		require.NoError(t, err)
+		require.Equal(t, "abc", v)
+
+		var i Null[int32]
+		require.NoError(t, i.Scan([]byte("12")))
+		require.Equal(t, NewNull[int32](12), i)
+		require.NoError(t, i.Scan(nil))
+		require.Equal(t, Null[int32]{}, i)
+		require.Error(t, i.Scan("abc"))
+		require.False(t, i.Valid)
+
+		var d Null[time.Time]
+		require.NoError(t, d.Scan(now))
+		require.Equal(t, NewNull(now), d)
+
+		var u Null[uint8]
+		require.NoError(t, u.Scan(int64(255)))
+		require.Equal(t, NewNull[uint8](255), u)
+		require.Error(t, u.Scan(int64(256)))
+
+		m := NewNull(map[string]int{"a": 1})
+		v, err = m.Value()
+		require.NoError(t, err)
+		require.Equal(t, []byte(`{"a":1}`), v)
+		m = Null[map[string]int]{}
+		require.NoError(t, m.Scan([]byte(`{"b":2}`)))
+		require.Equal(t, NewNull(map[string]int{"b": 2}), m)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type entity struct {
+			Count Null[int]
+			Name  Null[string]
+		}
+
+		src := entity{Count: NewNull(3)}
+		b, err := json.Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Count":3,"Name":null}`, string(b))
+		var dest entity
+		require.NoError(t, json.Unmarshal(b, &dest))
+		require.Equal(t, src, dest)
+
+		b, err = jsonb.Marshal(src)
+		require.NoError(t, err)
+		require.Equal(t, `{"Count":3,"Name":null}`, string(b))
+		dest = entity{}
+		require.NoError(t, jsonb.Unmarshal(b, &dest))
+		require.Equal(t, src, dest)
+	})
+
+	t.Run("BSON", func(t *testing.T) {
+		type entity struct {
+			Count Null[int64]
+			Name  Null[string]
+		}
+
+		src := entity{Name: NewNull("x")}
+		b, err := bson.Marshal(src)
+		require.NoError(t, err)
+		var dest entity
+		require.NoError(t, bson.Unmarshal(b, &dest))
+		require.Equal(t, src, dest)
+	})
+
+	t.Run("GQL", func(t *testing.T) {
+		w := new(bytes.Buffer)
+		NewNull(1.5).MarshalGQL(w)
+		Null[string]{}.MarshalGQL(w)
+		require.Equal(t, `1.5null`, w.String())
+
+		var s Null[string]
+		require.NoError(t, s.UnmarshalGQL("abc"))
+		require.Equal(t, NewNull("abc"), s)
+		require.NoError(t, s.UnmarshalGQL(nil))
+		require.False(t, s.Valid)
+	})
+}